@@ -1,9 +1,9 @@
 package auth
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -18,27 +18,40 @@ type Claims struct {
 // GerarToken gera um token JWT para o usuário
 func GerarToken(usuarioID int) (string, error) { // Verifica se a chave secreta está configurada
 
-	// Define o tempo de expiração do token (7 dias)
-	expirationTime := time.Now().Add(24 * time.Hour * 7)
+	// Define o tempo de expiração do token de acesso (curto; renovado via refresh token)
+	agora := time.Now()
+	expirationTime := agora.Add(DuracaoAccessToken())
+
+	// Identificador único (jti) usado para revogar o token no logout
+	jti, err := aleatorio(16)
+	if err != nil {
+		return "", fmt.Errorf("erro ao gerar o token: %v", err)
+	}
 
 	claims := &Claims{
 		UsuarioID: usuarioID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        hex.EncodeToString(jti),
+			IssuedAt:  jwt.NewNumericDate(agora),
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 		},
 	}
 
-	// Cria um novo token com os claims
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	// Garante que as chaves foram carregadas na inicialização
+	if chaveiro == nil {
+		return "", fmt.Errorf("erro ao gerar o token: chaves de assinatura não carregadas")
+	}
+
+	// Cria um novo token com os claims e identifica a chave atual no cabeçalho
+	token := jwt.NewWithClaims(chaveiro.atual.Metodo, claims)
+	token.Header["kid"] = chaveiro.atual.KID
 
-	// Assina o token com a chave secreta
-	jwtKey := []byte(os.Getenv("SECRETKEY"))
-	signedToken, err := token.SignedString(jwtKey)
+	// Assina o token com a chave atual
+	signedToken, err := token.SignedString(chaveiro.atual.assinatura)
 	if err != nil {
 		return "", fmt.Errorf("erro ao gerar o token: %v", err)
 	}
 
-	fmt.Println("Token gerado:", signedToken) // Exibe o token gerado para depuração
 	return signedToken, nil
 }
 
@@ -47,20 +60,13 @@ func ValidarToken(tokenString string) (*Claims, error) {
 	// Cria a estrutura Claims para armazenar os dados do token
 	claims := &Claims{}
 
-	// Pega a chave secreta do ambiente
-	jwtKey := []byte(os.Getenv("SECRETKEY"))
-	if len(jwtKey) == 0 {
-		return nil, fmt.Errorf("chave secreta não configurada no ambiente")
+	// Garante que as chaves foram carregadas na inicialização
+	if chaveiro == nil {
+		return nil, fmt.Errorf("chaves de assinatura não carregadas")
 	}
 
-	// Faz o parsing do token com os claims
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		// Verifica o método de assinatura do token
-		if token.Method != jwt.SigningMethodHS256 {
-			return nil, fmt.Errorf("algoritmo de assinatura inválido")
-		}
-		return jwtKey, nil
-	})
+	// Faz o parsing do token com os claims, escolhendo a chave pelo kid do cabeçalho
+	token, err := jwt.ParseWithClaims(tokenString, claims, chaveiro.chaveParaValidacao)
 	if err != nil {
 		// Verificando se o erro é devido ao token expirado
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -0,0 +1,263 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Tempo padrão em que tokens assinados por chaves anteriores continuam aceitos após uma rotação
+const periodoTransicaoPadrao = 24 * time.Hour
+
+// Tamanho mínimo recomendado para segredos HMAC (256 bits)
+const tamanhoMinimoSegredoHMAC = 32
+
+// Chave é uma chave de assinatura identificada pelo kid que vai no cabeçalho do JWT
+type Chave struct {
+	KID         string
+	Metodo      jwt.SigningMethod
+	assinatura  any // []byte, ed25519.PrivateKey ou *rsa.PrivateKey; nil para chaves só de verificação
+	verificacao any // []byte, ed25519.PublicKey ou *rsa.PublicKey
+}
+
+// Chaveiro reúne a chave atual (usada para assinar) e as anteriores (aceitas durante a transição)
+type Chaveiro struct {
+	atual                *Chave
+	chaves               map[string]*Chave
+	anterioresValidasAte time.Time
+}
+
+// chaveiro é carregado uma única vez na inicialização por CarregarChaves
+var chaveiro *Chaveiro
+
+// CarregarChaves monta o chaveiro a partir de JWT_KEYS_DIR ou, na ausência dele, das variáveis
+// SECRETKEY/SECRETKEY_ANTERIOR. Retorna erro se nenhuma chave de assinatura estiver configurada.
+func CarregarChaves() error {
+	var c *Chaveiro
+	var err error
+	if dir := os.Getenv("JWT_KEYS_DIR"); dir != "" {
+		periodo := duracaoDoAmbiente("JWT_PERIODO_TRANSICAO", periodoTransicaoPadrao)
+		c, err = carregarChavesDiretorio(dir, os.Getenv("JWT_KID_ATUAL"), periodo)
+	} else {
+		c, err = carregarChavesAmbiente()
+	}
+	if err != nil {
+		return err
+	}
+
+	chaveiro = c
+	log.Printf("Chaves JWT carregadas: %d (atual: %s, %s)", len(c.chaves), c.atual.KID, c.atual.Metodo.Alg())
+	return nil
+}
+
+// carregarChavesDiretorio lê arquivos <kid>.key (segredo HMAC) e <kid>.pem (Ed25519 ou RSA).
+// A chave atual é a indicada por kidAtual ou, se vazio, a modificada mais recentemente;
+// as demais continuam aceitas até periodo após a modificação da chave atual.
+func carregarChavesDiretorio(dir, kidAtual string, periodo time.Duration) (*Chaveiro, error) {
+	arquivos, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o diretório de chaves %s: %w", dir, err)
+	}
+
+	c := &Chaveiro{chaves: map[string]*Chave{}}
+	var modificadaEm time.Time
+	for _, arquivo := range arquivos {
+		if arquivo.IsDir() {
+			continue
+		}
+		extensao := filepath.Ext(arquivo.Name())
+		if extensao != ".key" && extensao != ".pem" {
+			continue
+		}
+
+		kid := strings.TrimSuffix(arquivo.Name(), extensao)
+		conteudo, err := os.ReadFile(filepath.Join(dir, arquivo.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler a chave %s: %w", arquivo.Name(), err)
+		}
+		info, err := arquivo.Info()
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler a chave %s: %w", arquivo.Name(), err)
+		}
+
+		chave, err := interpretarChave(kid, conteudo)
+		if err != nil {
+			return nil, err
+		}
+		if _, existe := c.chaves[kid]; existe {
+			return nil, fmt.Errorf("kid duplicado no diretório de chaves: %s", kid)
+		}
+		c.chaves[kid] = chave
+
+		// Sem JWT_KID_ATUAL, a chave de assinatura mais recente passa a ser a atual
+		ehAtual := kid == kidAtual || (kidAtual == "" && chave.assinatura != nil && info.ModTime().After(modificadaEm))
+		if ehAtual {
+			c.atual = chave
+			modificadaEm = info.ModTime()
+		}
+	}
+
+	if kidAtual != "" && c.atual == nil {
+		return nil, fmt.Errorf("a chave atual %q não foi encontrada em %s", kidAtual, dir)
+	}
+	if err := c.validarAtual(); err != nil {
+		return nil, err
+	}
+
+	c.anterioresValidasAte = modificadaEm.Add(periodo)
+	return c, nil
+}
+
+// carregarChavesAmbiente usa SECRETKEY como chave atual e SECRETKEY_ANTERIOR como chave de transição.
+// Os valores podem ser segredos HMAC ou chaves PEM; os kids vêm de SECRETKEY_KID e SECRETKEY_ANTERIOR_KID.
+// A chave anterior vale até SECRETKEY_ANTERIOR_ATE (RFC 3339), obrigatória junto com ela: um prazo
+// fixo, e não contado a partir da inicialização, para que reiniciar o servidor não o prorrogue.
+func carregarChavesAmbiente() (*Chaveiro, error) {
+	c := &Chaveiro{chaves: map[string]*Chave{}}
+
+	segredo := os.Getenv("SECRETKEY")
+	if segredo == "" {
+		return nil, fmt.Errorf("nenhuma chave de assinatura configurada: defina SECRETKEY ou JWT_KEYS_DIR")
+	}
+	atual, err := interpretarChave(valorOuPadrao(os.Getenv("SECRETKEY_KID"), "principal"), []byte(segredo))
+	if err != nil {
+		return nil, err
+	}
+	c.atual = atual
+	c.chaves[atual.KID] = atual
+
+	if anterior := os.Getenv("SECRETKEY_ANTERIOR"); anterior != "" {
+		chave, err := interpretarChave(valorOuPadrao(os.Getenv("SECRETKEY_ANTERIOR_KID"), "anterior"), []byte(anterior))
+		if err != nil {
+			return nil, err
+		}
+		if chave.KID == atual.KID {
+			return nil, fmt.Errorf("SECRETKEY_KID e SECRETKEY_ANTERIOR_KID devem ser diferentes")
+		}
+
+		ate := os.Getenv("SECRETKEY_ANTERIOR_ATE")
+		if ate == "" {
+			return nil, fmt.Errorf("SECRETKEY_ANTERIOR exige SECRETKEY_ANTERIOR_ATE com o fim da transição (RFC 3339)")
+		}
+		c.anterioresValidasAte, err = time.Parse(time.RFC3339, ate)
+		if err != nil {
+			return nil, fmt.Errorf("SECRETKEY_ANTERIOR_ATE inválida (use RFC 3339, ex.: 2025-03-01T00:00:00Z): %w", err)
+		}
+		c.chaves[chave.KID] = chave
+	}
+
+	return c, c.validarAtual()
+}
+
+// validarAtual garante que existe uma chave capaz de assinar novos tokens
+func (c *Chaveiro) validarAtual() error {
+	if c.atual == nil {
+		return fmt.Errorf("nenhuma chave de assinatura configurada")
+	}
+	if c.atual.assinatura == nil {
+		return fmt.Errorf("a chave atual %q é apenas pública e não pode assinar tokens", c.atual.KID)
+	}
+	return nil
+}
+
+// interpretarChave identifica o tipo da chave: PEM (Ed25519/RSA) ou segredo HMAC em texto
+func interpretarChave(kid string, conteudo []byte) (*Chave, error) {
+	conteudo = bytes.TrimSpace(conteudo)
+	if kid == "" {
+		return nil, fmt.Errorf("chave sem identificador (kid)")
+	}
+	if len(conteudo) == 0 {
+		return nil, fmt.Errorf("a chave %q está vazia", kid)
+	}
+
+	bloco, _ := pem.Decode(conteudo)
+	if bloco == nil {
+		if len(conteudo) < tamanhoMinimoSegredoHMAC {
+			log.Printf("Aviso: o segredo HMAC da chave %q tem menos de %d bytes", kid, tamanhoMinimoSegredoHMAC)
+		}
+		return &Chave{KID: kid, Metodo: jwt.SigningMethodHS256, assinatura: conteudo, verificacao: conteudo}, nil
+	}
+
+	switch bloco.Type {
+	case "PRIVATE KEY":
+		privada, err := x509.ParsePKCS8PrivateKey(bloco.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler a chave privada %q: %w", kid, err)
+		}
+		switch k := privada.(type) {
+		case ed25519.PrivateKey:
+			return &Chave{KID: kid, Metodo: jwt.SigningMethodEdDSA, assinatura: k, verificacao: k.Public()}, nil
+		case *rsa.PrivateKey:
+			return &Chave{KID: kid, Metodo: jwt.SigningMethodRS256, assinatura: k, verificacao: &k.PublicKey}, nil
+		}
+		return nil, fmt.Errorf("tipo de chave privada não suportado em %q (use Ed25519 ou RSA)", kid)
+
+	case "RSA PRIVATE KEY":
+		k, err := x509.ParsePKCS1PrivateKey(bloco.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler a chave RSA %q: %w", kid, err)
+		}
+		return &Chave{KID: kid, Metodo: jwt.SigningMethodRS256, assinatura: k, verificacao: &k.PublicKey}, nil
+
+	case "PUBLIC KEY":
+		publica, err := x509.ParsePKIXPublicKey(bloco.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler a chave pública %q: %w", kid, err)
+		}
+		switch k := publica.(type) {
+		case ed25519.PublicKey:
+			return &Chave{KID: kid, Metodo: jwt.SigningMethodEdDSA, verificacao: k}, nil
+		case *rsa.PublicKey:
+			return &Chave{KID: kid, Metodo: jwt.SigningMethodRS256, verificacao: k}, nil
+		}
+		return nil, fmt.Errorf("tipo de chave pública não suportado em %q (use Ed25519 ou RSA)", kid)
+	}
+
+	return nil, fmt.Errorf("bloco PEM %q não suportado na chave %q", bloco.Type, kid)
+}
+
+// chaveParaValidacao escolhe a chave que verifica o token a partir do kid do cabeçalho
+func (c *Chaveiro) chaveParaValidacao(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	// Tokens emitidos antes do kid existir só podem ter sido assinados com o segredo HMAC principal
+	var chave *Chave
+	if kid == "" {
+		chave = c.atual
+	} else {
+		chave = c.chaves[kid]
+	}
+	if chave == nil {
+		return nil, fmt.Errorf("chave de assinatura desconhecida: %s", kid)
+	}
+
+	// Verifica o método de assinatura do token
+	if token.Method.Alg() != chave.Metodo.Alg() {
+		return nil, fmt.Errorf("algoritmo de assinatura inválido")
+	}
+
+	// Chaves anteriores só valem durante o período de transição
+	if chave != c.atual && time.Now().After(c.anterioresValidasAte) {
+		return nil, fmt.Errorf("chave de assinatura %s aposentada", chave.KID)
+	}
+
+	return chave.verificacao, nil
+}
+
+// valorOuPadrao retorna valor ou, se vazio, padrao
+func valorOuPadrao(valor, padrao string) string {
+	if valor == "" {
+		return padrao
+	}
+	return valor
+}
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// chaveiroTeste cria um chaveiro HMAC com a chave "atual" e a "anterior", esta válida até validaAte
+func chaveiroTeste(t *testing.T, validaAte time.Time) *Chaveiro {
+	t.Helper()
+	atual, err := interpretarChave("atual", []byte(strings.Repeat("a", tamanhoMinimoSegredoHMAC)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	anterior, err := interpretarChave("anterior", []byte(strings.Repeat("b", tamanhoMinimoSegredoHMAC)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Chaveiro{
+		atual:                atual,
+		chaves:               map[string]*Chave{atual.KID: atual, anterior.KID: anterior},
+		anterioresValidasAte: validaAte,
+	}
+}
+
+// tokenTeste monta o token como o jwt.Parse o entrega à função de chave
+func tokenTeste(metodo jwt.SigningMethod, kid string) *jwt.Token {
+	token := jwt.New(metodo)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	return token
+}
+
+func TestChaveParaValidacao(t *testing.T) {
+	vigente := chaveiroTeste(t, time.Now().Add(time.Hour))
+	aposentada := chaveiroTeste(t, time.Now().Add(-time.Hour))
+
+	casos := []struct {
+		nome     string
+		chaveiro *Chaveiro
+		token    *jwt.Token
+		chave    string // kid da chave esperada; vazio se o token deve ser recusado
+	}{
+		{"chave atual", vigente, tokenTeste(jwt.SigningMethodHS256, "atual"), "atual"},
+		{"sem kid usa a atual", vigente, tokenTeste(jwt.SigningMethodHS256, ""), "atual"},
+		{"anterior na transição", vigente, tokenTeste(jwt.SigningMethodHS256, "anterior"), "anterior"},
+		{"anterior aposentada", aposentada, tokenTeste(jwt.SigningMethodHS256, "anterior"), ""},
+		{"atual não expira com a transição", aposentada, tokenTeste(jwt.SigningMethodHS256, "atual"), "atual"},
+		{"kid desconhecido", vigente, tokenTeste(jwt.SigningMethodHS256, "outra"), ""},
+		{"algoritmo diferente", vigente, tokenTeste(jwt.SigningMethodHS384, "atual"), ""},
+		{"algoritmo none", vigente, tokenTeste(jwt.SigningMethodNone, "atual"), ""},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			chave, err := caso.chaveiro.chaveParaValidacao(caso.token)
+			if caso.chave == "" {
+				if err == nil {
+					t.Errorf("token aceito, esperado erro")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if esperada := caso.chaveiro.chaves[caso.chave].verificacao; string(chave.([]byte)) != string(esperada.([]byte)) {
+				t.Errorf("chave devolvida não é a %q", caso.chave)
+			}
+		})
+	}
+}
+
+func TestCarregarChavesAmbienteComAnterior(t *testing.T) {
+	t.Setenv("SECRETKEY", strings.Repeat("a", tamanhoMinimoSegredoHMAC))
+	t.Setenv("SECRETKEY_ANTERIOR", strings.Repeat("b", tamanhoMinimoSegredoHMAC))
+
+	t.Run("sem prazo", func(t *testing.T) {
+		t.Setenv("SECRETKEY_ANTERIOR_ATE", "")
+		if _, err := carregarChavesAmbiente(); err == nil {
+			t.Error("chaveiro carregado sem SECRETKEY_ANTERIOR_ATE, esperado erro")
+		}
+	})
+	t.Run("prazo inválido", func(t *testing.T) {
+		t.Setenv("SECRETKEY_ANTERIOR_ATE", "amanhã")
+		if _, err := carregarChavesAmbiente(); err == nil {
+			t.Error("chaveiro carregado com prazo inválido, esperado erro")
+		}
+	})
+	t.Run("prazo fixo", func(t *testing.T) {
+		t.Setenv("SECRETKEY_ANTERIOR_ATE", "2025-03-01T12:00:00Z")
+		c, err := carregarChavesAmbiente()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// O prazo é o configurado, e não contado a partir da inicialização
+		if !c.anterioresValidasAte.Equal(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)) {
+			t.Errorf("anteriores válidas até %s, esperado o prazo configurado", c.anterioresValidasAte)
+		}
+		if _, err := c.chaveParaValidacao(tokenTeste(jwt.SigningMethodHS256, "anterior")); err == nil {
+			t.Error("chave anterior aceita depois do prazo")
+		}
+	})
+}
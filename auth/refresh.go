@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Validade padrão dos tokens, sobrescrita por ACCESS_TOKEN_TTL e REFRESH_TOKEN_TTL
+const (
+	duracaoAccessTokenPadrao  = 15 * time.Minute
+	duracaoRefreshTokenPadrao = 30 * 24 * time.Hour
+)
+
+// duracaoDoAmbiente lê uma duração (ex.: "15m", "720h") do ambiente ou usa o padrão
+func duracaoDoAmbiente(variavel string, padrao time.Duration) time.Duration {
+	valor := os.Getenv(variavel)
+	if valor == "" {
+		return padrao
+	}
+	duracao, err := time.ParseDuration(valor)
+	if err != nil || duracao <= 0 {
+		return padrao
+	}
+	return duracao
+}
+
+// DuracaoAccessToken retorna a validade dos tokens de acesso (JWT)
+func DuracaoAccessToken() time.Duration {
+	return duracaoDoAmbiente("ACCESS_TOKEN_TTL", duracaoAccessTokenPadrao)
+}
+
+// DuracaoRefreshToken retorna a validade dos refresh tokens
+func DuracaoRefreshToken() time.Duration {
+	return duracaoDoAmbiente("REFRESH_TOKEN_TTL", duracaoRefreshTokenPadrao)
+}
+
+// aleatorio gera n bytes criptograficamente seguros
+func aleatorio(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("erro ao gerar bytes aleatórios: %w", err)
+	}
+	return b, nil
+}
+
+// GerarRefreshToken gera um refresh token opaco e o hash que deve ser armazenado no banco
+func GerarRefreshToken() (token string, hash string, err error) {
+	b, err := aleatorio(32)
+	if err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken calcula o hash SHA-256 usado para localizar um refresh token
+func HashRefreshToken(token string) string {
+	soma := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(soma[:])
+}
+
+// GerarFamiliaToken gera o identificador de uma nova família de refresh tokens
+func GerarFamiliaToken() (string, error) {
+	b, err := aleatorio(16)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
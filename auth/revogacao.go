@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jpeccia/quantogasto_app_server/database"
+)
+
+// Por quanto tempo uma consulta "não revogado" é reaproveitada antes de ir ao banco de novo.
+// Define o atraso máximo para que uma revogação feita em outra instância tenha efeito.
+const validadeCacheRevogacao = 5 * time.Second
+
+// Limite de entradas no cache antes de descartar as vencidas
+const tamanhoMaximoCacheRevogacao = 10000
+
+// entradaRevogacao guarda o resultado da última verificação de um token
+type entradaRevogacao struct {
+	usuarioID    int
+	revogado     bool
+	verificadoEm time.Time
+	expiraEm     time.Time
+}
+
+// cacheRevogacao é o cache em memória das verificações de revogação, indexado pelo jti
+var cacheRevogacao = struct {
+	sync.Mutex
+	entradas map[string]entradaRevogacao
+}{entradas: map[string]entradaRevogacao{}}
+
+// guardarNoCache registra o resultado de uma verificação, limpando entradas antigas se necessário
+func guardarNoCache(jti string, entrada entradaRevogacao) {
+	cacheRevogacao.Lock()
+	defer cacheRevogacao.Unlock()
+
+	if len(cacheRevogacao.entradas) >= tamanhoMaximoCacheRevogacao {
+		agora := time.Now()
+		for chave, e := range cacheRevogacao.entradas {
+			if agora.After(e.expiraEm) || (!e.revogado && agora.Sub(e.verificadoEm) > validadeCacheRevogacao) {
+				delete(cacheRevogacao.entradas, chave)
+			}
+		}
+	}
+	cacheRevogacao.entradas[jti] = entrada
+}
+
+// TokenRevogado indica se o token foi revogado por logout ou por logout em todos os dispositivos
+func TokenRevogado(ctx context.Context, claims *Claims) (bool, error) {
+	jti := claims.ID
+	var expiraEm time.Time
+	if claims.ExpiresAt != nil {
+		expiraEm = claims.ExpiresAt.Time
+	}
+
+	// Revogações são definitivas; resultados negativos valem por poucos segundos
+	cacheRevogacao.Lock()
+	entrada, ok := cacheRevogacao.entradas[jti]
+	cacheRevogacao.Unlock()
+	if ok && jti != "" && (entrada.revogado || time.Since(entrada.verificadoEm) < validadeCacheRevogacao) {
+		return entrada.revogado, nil
+	}
+
+	var emitidoEm time.Time
+	if claims.IssuedAt != nil {
+		emitidoEm = claims.IssuedAt.Time
+	}
+
+	// Consulta a lista de revogação e o marco de logout-all do usuário em uma única ida ao banco.
+	// O iat só tem precisão de segundos e o marco é gravado truncado no segundo (RevogarTodosTokens):
+	// um novo login no mesmo segundo do logout-all continua válido.
+	query := `
+        SELECT
+            EXISTS (SELECT 1 FROM tokens_revogados WHERE jti = $1),
+            COALESCE((SELECT tokens_validos_desde > $3 FROM usuarios WHERE id = $2), FALSE)
+    `
+	var revogadoPorJTI, revogadoPorUsuario bool
+	err := database.DB.QueryRow(ctx, query, jti, claims.UsuarioID, emitidoEm).Scan(&revogadoPorJTI, &revogadoPorUsuario)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar revogação do token: %w", err)
+	}
+
+	revogado := revogadoPorJTI || revogadoPorUsuario
+	if jti != "" {
+		guardarNoCache(jti, entradaRevogacao{
+			usuarioID:    claims.UsuarioID,
+			revogado:     revogado,
+			verificadoEm: time.Now(),
+			expiraEm:     expiraEm,
+		})
+	}
+	return revogado, nil
+}
+
+// RevogarToken revoga um token de acesso específico (logout) até a sua expiração
+func RevogarToken(ctx context.Context, claims *Claims) error {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return fmt.Errorf("token sem identificador ou expiração não pode ser revogado individualmente")
+	}
+
+	query := `
+        INSERT INTO tokens_revogados (jti, usuario_id, expira_em)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (jti) DO NOTHING
+    `
+	if _, err := database.DB.Exec(ctx, query, claims.ID, claims.UsuarioID, claims.ExpiresAt.Time); err != nil {
+		return fmt.Errorf("erro ao revogar o token: %w", err)
+	}
+
+	// Remove da lista os tokens que já expiraram por conta própria
+	if _, err := database.DB.Exec(ctx, `DELETE FROM tokens_revogados WHERE expira_em < NOW()`); err != nil {
+		return fmt.Errorf("erro ao limpar tokens revogados expirados: %w", err)
+	}
+
+	guardarNoCache(claims.ID, entradaRevogacao{
+		usuarioID:    claims.UsuarioID,
+		revogado:     true,
+		verificadoEm: time.Now(),
+		expiraEm:     claims.ExpiresAt.Time,
+	})
+	return nil
+}
+
+// RevogarTodosTokens invalida todos os tokens de acesso emitidos para o usuário antes do segundo
+// atual. O marco fica no segundo, como o iat, para não revogar um login feito logo em seguida.
+func RevogarTodosTokens(ctx context.Context, usuarioID int) error {
+	query := `UPDATE usuarios SET tokens_validos_desde = date_trunc('second', NOW()) WHERE id = $1`
+	if _, err := database.DB.Exec(ctx, query, usuarioID); err != nil {
+		return fmt.Errorf("erro ao revogar os tokens do usuário: %w", err)
+	}
+
+	// Descarta as verificações em cache do usuário para que a revogação valha imediatamente nesta instância
+	cacheRevogacao.Lock()
+	for jti, e := range cacheRevogacao.entradas {
+		if e.usuarioID == usuarioID {
+			delete(cacheRevogacao.entradas, jti)
+		}
+	}
+	cacheRevogacao.Unlock()
+	return nil
+}
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Custo do bcrypt usado para novas senhas
+const custoSenha = 12
+
+// hashFicticio é comparado quando o usuário não existe, para que o tempo de resposta
+// do login não revele quais e-mails estão cadastrados
+var hashFicticio, _ = bcrypt.GenerateFromPassword([]byte("senha-ficticia-para-tempo-constante"), custoSenha)
+
+// GerarHashSenha gera o hash bcrypt de uma senha em texto puro
+func GerarHashSenha(senha string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(senha), custoSenha)
+	if err != nil {
+		return "", fmt.Errorf("erro ao gerar o hash da senha: %w", err)
+	}
+	return string(hash), nil
+}
+
+// VerificarSenha compara a senha informada com o hash armazenado.
+// Um hash vazio (usuário inexistente ou sem senha) também consome o tempo de uma comparação.
+func VerificarSenha(hash, senha string) bool {
+	if hash == "" {
+		bcrypt.CompareHashAndPassword(hashFicticio, []byte(senha))
+		return false
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(senha)) == nil
+}
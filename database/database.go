@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"fmt"
+	"log"
 	"os"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -30,6 +31,9 @@ func Connect() error {
 		return fmt.Errorf("erro ao parsear a string de conexão: %w", err)
 	}
 
+	// Limita a espera por uma conexão livre, para que o pool esgotado não segure as requisições
+	config.ConnConfig.Tracer = rastreadorPool{espera: esperaConexaoDoAmbiente()}
+
 	// Cria o pool de conexões
 	DB, err = pgxpool.NewWithConfig(context.Background(), config)
 	if err != nil {
@@ -42,6 +46,6 @@ func Connect() error {
 		return fmt.Errorf("erro ao testar a conexão com o banco de dados: %w", err)
 	}
 
-	fmt.Println("Conectado ao banco de dados!")
+	log.Println("Conectado ao banco de dados!")
 	return nil
 }
\ No newline at end of file
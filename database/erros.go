@@ -0,0 +1,39 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Códigos de erro do PostgreSQL tratados pela aplicação
+const (
+	codigoViolacaoUnica            = "23505"
+	codigoViolacaoChaveEstrangeira = "23503"
+	codigoViolacaoCheck            = "23514"
+)
+
+// codigoErro retorna o código SQLSTATE de um erro do PostgreSQL, ou vazio
+func codigoErro(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
+
+// ErroViolacaoUnica indica se o erro foi causado por uma restrição UNIQUE
+func ErroViolacaoUnica(err error) bool {
+	return codigoErro(err) == codigoViolacaoUnica
+}
+
+// ErroViolacaoChaveEstrangeira indica se o erro foi causado por uma restrição FOREIGN KEY
+// (ex.: remover um registro ainda referenciado)
+func ErroViolacaoChaveEstrangeira(err error) bool {
+	return codigoErro(err) == codigoViolacaoChaveEstrangeira
+}
+
+// ErroViolacaoCheck indica se o erro foi causado por uma restrição CHECK
+func ErroViolacaoCheck(err error) bool {
+	return codigoErro(err) == codigoViolacaoCheck
+}
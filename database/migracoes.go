@@ -0,0 +1,253 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migracoes/*.sql
+var arquivosMigracoes embed.FS
+
+// Chave do advisory lock usado para impedir que duas instâncias migrem ao mesmo tempo
+const chaveLockMigracoes int64 = 7240142025
+
+// Migracao representa uma versão do schema com seus scripts de subida e descida
+type Migracao struct {
+	Versao int
+	Nome   string
+	Up     string
+	Down   string
+}
+
+// StatusMigracao indica se uma migração já foi aplicada no banco
+type StatusMigracao struct {
+	Versao     int
+	Nome       string
+	AplicadaEm *time.Time // nil quando a migração está pendente
+}
+
+// carregarMigracoes lê os arquivos embutidos no formato NNNN_nome.up.sql / NNNN_nome.down.sql
+func carregarMigracoes() ([]Migracao, error) {
+	arquivos, err := fs.ReadDir(arquivosMigracoes, "migracoes")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler as migrações embutidas: %w", err)
+	}
+
+	porVersao := map[int]*Migracao{}
+	for _, arquivo := range arquivos {
+		nomeArquivo := arquivo.Name()
+
+		var direcao string
+		switch {
+		case strings.HasSuffix(nomeArquivo, ".up.sql"):
+			direcao = "up"
+		case strings.HasSuffix(nomeArquivo, ".down.sql"):
+			direcao = "down"
+		default:
+			return nil, fmt.Errorf("arquivo de migração com nome inválido: %s", nomeArquivo)
+		}
+
+		base := strings.TrimSuffix(nomeArquivo, "."+direcao+".sql")
+		versaoTexto, nome, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("arquivo de migração com nome inválido: %s", nomeArquivo)
+		}
+		versao, err := strconv.Atoi(versaoTexto)
+		if err != nil || versao <= 0 {
+			return nil, fmt.Errorf("versão inválida no arquivo de migração: %s", nomeArquivo)
+		}
+
+		conteudo, err := arquivosMigracoes.ReadFile(path.Join("migracoes", nomeArquivo))
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler a migração %s: %w", nomeArquivo, err)
+		}
+
+		m, existe := porVersao[versao]
+		if !existe {
+			m = &Migracao{Versao: versao, Nome: nome}
+			porVersao[versao] = m
+		} else if m.Nome != nome {
+			return nil, fmt.Errorf("a versão %d possui nomes divergentes: %s e %s", versao, m.Nome, nome)
+		}
+		if direcao == "up" {
+			m.Up = string(conteudo)
+		} else {
+			m.Down = string(conteudo)
+		}
+	}
+
+	migracoes := make([]Migracao, 0, len(porVersao))
+	for _, m := range porVersao {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("a migração %04d_%s precisa dos arquivos up e down", m.Versao, m.Nome)
+		}
+		migracoes = append(migracoes, *m)
+	}
+	sort.Slice(migracoes, func(i, j int) bool { return migracoes[i].Versao < migracoes[j].Versao })
+
+	return migracoes, nil
+}
+
+// comLockMigracoes reserva uma conexão, cria a tabela de controle e segura o advisory lock durante fn
+func comLockMigracoes(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	if DB == nil {
+		return fmt.Errorf("banco de dados não conectado")
+	}
+
+	conn, err := DB.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao obter conexão para migrações: %w", err)
+	}
+	defer conn.Release()
+
+	// Bloqueia até que nenhuma outra instância esteja migrando
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, chaveLockMigracoes); err != nil {
+		return fmt.Errorf("erro ao obter o lock de migrações: %w", err)
+	}
+	defer conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, chaveLockMigracoes)
+
+	query := `
+        CREATE TABLE IF NOT EXISTS schema_migracoes (
+            versao      INTEGER PRIMARY KEY,
+            nome        TEXT NOT NULL,
+            aplicada_em TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )
+    `
+	if _, err := conn.Exec(ctx, query); err != nil {
+		return fmt.Errorf("erro ao criar a tabela de controle de migrações: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// versoesAplicadas retorna as versões registradas na tabela de controle
+func versoesAplicadas(ctx context.Context, conn *pgxpool.Conn) (map[int]time.Time, error) {
+	rows, err := conn.Query(ctx, `SELECT versao, aplicada_em FROM schema_migracoes`)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar migrações aplicadas: %w", err)
+	}
+	defer rows.Close()
+
+	aplicadas := map[int]time.Time{}
+	for rows.Next() {
+		var versao int
+		var aplicadaEm time.Time
+		if err := rows.Scan(&versao, &aplicadaEm); err != nil {
+			return nil, fmt.Errorf("erro ao ler migrações aplicadas: %w", err)
+		}
+		aplicadas[versao] = aplicadaEm
+	}
+	return aplicadas, rows.Err()
+}
+
+// executarMigracao roda um script e atualiza a tabela de controle na mesma transação
+func executarMigracao(ctx context.Context, conn *pgxpool.Conn, script, registro string, args ...any) error {
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, script); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, registro, args...)
+		return err
+	})
+}
+
+// MigrarUp aplica todas as migrações pendentes em ordem e retorna quantas foram aplicadas
+func MigrarUp(ctx context.Context) (int, error) {
+	migracoes, err := carregarMigracoes()
+	if err != nil {
+		return 0, err
+	}
+
+	aplicadasAgora := 0
+	err = comLockMigracoes(ctx, func(conn *pgxpool.Conn) error {
+		aplicadas, err := versoesAplicadas(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migracoes {
+			if _, ok := aplicadas[m.Versao]; ok {
+				continue
+			}
+			err := executarMigracao(ctx, conn, m.Up,
+				`INSERT INTO schema_migracoes (versao, nome) VALUES ($1, $2)`, m.Versao, m.Nome)
+			if err != nil {
+				return fmt.Errorf("erro ao aplicar a migração %04d_%s: %w", m.Versao, m.Nome, err)
+			}
+			aplicadasAgora++
+		}
+		return nil
+	})
+	return aplicadasAgora, err
+}
+
+// MigrarDown reverte as últimas migrações aplicadas, da mais recente para a mais antiga
+func MigrarDown(ctx context.Context, passos int) (int, error) {
+	if passos <= 0 {
+		return 0, fmt.Errorf("a quantidade de migrações a reverter deve ser maior que zero")
+	}
+
+	migracoes, err := carregarMigracoes()
+	if err != nil {
+		return 0, err
+	}
+
+	revertidas := 0
+	err = comLockMigracoes(ctx, func(conn *pgxpool.Conn) error {
+		aplicadas, err := versoesAplicadas(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(migracoes) - 1; i >= 0 && revertidas < passos; i-- {
+			m := migracoes[i]
+			if _, ok := aplicadas[m.Versao]; !ok {
+				continue
+			}
+			err := executarMigracao(ctx, conn, m.Down,
+				`DELETE FROM schema_migracoes WHERE versao = $1`, m.Versao)
+			if err != nil {
+				return fmt.Errorf("erro ao reverter a migração %04d_%s: %w", m.Versao, m.Nome, err)
+			}
+			revertidas++
+		}
+		return nil
+	})
+	return revertidas, err
+}
+
+// StatusMigracoes lista todas as migrações conhecidas e quando foram aplicadas
+func StatusMigracoes(ctx context.Context) ([]StatusMigracao, error) {
+	migracoes, err := carregarMigracoes()
+	if err != nil {
+		return nil, err
+	}
+
+	var status []StatusMigracao
+	err = comLockMigracoes(ctx, func(conn *pgxpool.Conn) error {
+		aplicadas, err := versoesAplicadas(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migracoes {
+			s := StatusMigracao{Versao: m.Versao, Nome: m.Nome}
+			if aplicadaEm, ok := aplicadas[m.Versao]; ok {
+				s.AplicadaEm = &aplicadaEm
+			}
+			status = append(status, s)
+		}
+		return nil
+	})
+	return status, err
+}
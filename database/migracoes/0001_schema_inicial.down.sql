@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS gastos_variaveis;
+DROP TABLE IF EXISTS gastos_fixos;
+DROP TABLE IF EXISTS rendas;
+DROP TABLE IF EXISTS usuarios;
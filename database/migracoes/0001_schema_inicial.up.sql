@@ -0,0 +1,41 @@
+-- Schema inicial: tabelas já utilizadas pelos handlers.
+-- Usa IF NOT EXISTS para que bancos criados manualmente possam adotar as migrações.
+
+CREATE TABLE IF NOT EXISTS usuarios (
+    id          SERIAL PRIMARY KEY,
+    nome        TEXT NOT NULL,
+    foto_perfil TEXT NOT NULL DEFAULT '',
+    cargo       TEXT NOT NULL DEFAULT '',
+    renda       NUMERIC(14, 2) NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS rendas (
+    id         SERIAL PRIMARY KEY,
+    usuario_id INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    valor      NUMERIC(14, 2) NOT NULL CHECK (valor > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rendas_usuario ON rendas (usuario_id);
+
+CREATE TABLE IF NOT EXISTS gastos_fixos (
+    id         SERIAL PRIMARY KEY,
+    usuario_id INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    nome       TEXT NOT NULL,
+    valor      NUMERIC(14, 2) NOT NULL CHECK (valor > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_gastos_fixos_usuario ON gastos_fixos (usuario_id);
+
+CREATE TABLE IF NOT EXISTS gastos_variaveis (
+    id         SERIAL PRIMARY KEY,
+    usuario_id INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    nome       TEXT NOT NULL,
+    valor      NUMERIC(14, 2) NOT NULL CHECK (valor > 0),
+    data       DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_gastos_variaveis_usuario_data ON gastos_variaveis (usuario_id, data);
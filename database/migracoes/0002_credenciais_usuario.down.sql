@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_usuarios_email;
+
+ALTER TABLE usuarios DROP COLUMN IF EXISTS senha_hash;
+ALTER TABLE usuarios DROP COLUMN IF EXISTS email;
@@ -0,0 +1,7 @@
+-- Credenciais de acesso: e-mail (sempre normalizado em minúsculas) e hash da senha.
+-- As colunas aceitam NULL para não quebrar usuários cadastrados antes do login existir.
+
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS email TEXT;
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS senha_hash TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_usuarios_email ON usuarios (email);
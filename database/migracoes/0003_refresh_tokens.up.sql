@@ -0,0 +1,17 @@
+-- Refresh tokens opacos: apenas o hash SHA-256 é armazenado.
+-- Tokens rotacionados a partir do mesmo login compartilham a mesma família,
+-- que é revogada inteira quando um token já utilizado é apresentado de novo.
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id          BIGSERIAL PRIMARY KEY,
+    usuario_id  INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    familia_id  TEXT NOT NULL,
+    token_hash  TEXT NOT NULL UNIQUE,
+    expira_em   TIMESTAMPTZ NOT NULL,
+    usado_em    TIMESTAMPTZ,
+    revogado_em TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_familia ON refresh_tokens (familia_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_usuario ON refresh_tokens (usuario_id);
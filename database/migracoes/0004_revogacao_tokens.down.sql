@@ -0,0 +1,3 @@
+ALTER TABLE usuarios DROP COLUMN IF EXISTS tokens_validos_desde;
+
+DROP TABLE IF EXISTS tokens_revogados;
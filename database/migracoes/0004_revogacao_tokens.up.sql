@@ -0,0 +1,14 @@
+-- Revogação de tokens de acesso.
+-- tokens_revogados guarda o jti de tokens encerrados por logout até a data de expiração deles;
+-- usuarios.tokens_validos_desde invalida de uma vez todos os tokens emitidos antes (logout-all).
+
+CREATE TABLE IF NOT EXISTS tokens_revogados (
+    jti         TEXT PRIMARY KEY,
+    usuario_id  INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    expira_em   TIMESTAMPTZ NOT NULL,
+    revogado_em TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tokens_revogados_expira ON tokens_revogados (expira_em);
+
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS tokens_validos_desde TIMESTAMPTZ;
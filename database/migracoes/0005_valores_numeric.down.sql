@@ -0,0 +1,2 @@
+-- Nada a reverter: voltar para ponto flutuante reintroduziria os erros de arredondamento.
+SELECT 1;
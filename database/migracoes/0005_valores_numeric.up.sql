@@ -0,0 +1,7 @@
+-- Garante que todos os valores monetários sejam NUMERIC(14, 2), inclusive em bancos
+-- criados manualmente antes das migrações (que podiam usar REAL ou DOUBLE PRECISION).
+
+ALTER TABLE usuarios ALTER COLUMN renda TYPE NUMERIC(14, 2) USING ROUND(renda::numeric, 2);
+ALTER TABLE rendas ALTER COLUMN valor TYPE NUMERIC(14, 2) USING ROUND(valor::numeric, 2);
+ALTER TABLE gastos_fixos ALTER COLUMN valor TYPE NUMERIC(14, 2) USING ROUND(valor::numeric, 2);
+ALTER TABLE gastos_variaveis ALTER COLUMN valor TYPE NUMERIC(14, 2) USING ROUND(valor::numeric, 2);
@@ -0,0 +1,4 @@
+ALTER TABLE gastos_variaveis DROP COLUMN IF EXISTS categoria_id;
+ALTER TABLE gastos_fixos DROP COLUMN IF EXISTS categoria_id;
+
+DROP TABLE IF EXISTS categorias;
@@ -0,0 +1,29 @@
+-- Categorias de gastos. Categorias com usuario_id NULL são as padrão, visíveis para todos;
+-- as demais são personalizadas e pertencem a um único usuário.
+
+CREATE TABLE IF NOT EXISTS categorias (
+    id         SERIAL PRIMARY KEY,
+    usuario_id INTEGER REFERENCES usuarios (id) ON DELETE CASCADE,
+    nome       TEXT NOT NULL,
+    cor        TEXT NOT NULL DEFAULT '#9E9E9E',
+    icone      TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- O nome é único entre as categorias padrão e entre as categorias de cada usuário
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categorias_usuario_nome ON categorias (COALESCE(usuario_id, 0), LOWER(nome));
+
+INSERT INTO categorias (usuario_id, nome, cor, icone) VALUES
+    (NULL, 'Moradia',     '#5C6BC0', 'home'),
+    (NULL, 'Alimentação', '#EF6C00', 'food'),
+    (NULL, 'Transporte',  '#0288D1', 'car'),
+    (NULL, 'Saúde',       '#E53935', 'heart-pulse'),
+    (NULL, 'Educação',    '#8E24AA', 'school'),
+    (NULL, 'Lazer',       '#43A047', 'gamepad-variant'),
+    (NULL, 'Compras',     '#D81B60', 'shopping'),
+    (NULL, 'Contas',      '#6D4C41', 'file-document'),
+    (NULL, 'Outros',      '#757575', 'dots-horizontal')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE gastos_fixos ADD COLUMN IF NOT EXISTS categoria_id INTEGER REFERENCES categorias (id) ON DELETE SET NULL;
+ALTER TABLE gastos_variaveis ADD COLUMN IF NOT EXISTS categoria_id INTEGER REFERENCES categorias (id) ON DELETE SET NULL;
@@ -0,0 +1,15 @@
+-- Orçamentos mensais. Com categoria_id, limitam os gastos da categoria; sem categoria,
+-- limitam o total de gastos do mês. O orçamento geral pode omitir o valor para usar
+-- a renda do mês como limite.
+
+CREATE TABLE IF NOT EXISTS orcamentos (
+    id           SERIAL PRIMARY KEY,
+    usuario_id   INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    categoria_id INTEGER REFERENCES categorias (id) ON DELETE CASCADE,
+    valor        NUMERIC(14, 2),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (valor > 0 OR (valor IS NULL AND categoria_id IS NULL))
+);
+
+-- Um orçamento por categoria (e um geral) por usuário
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orcamentos_usuario_categoria ON orcamentos (usuario_id, COALESCE(categoria_id, 0));
@@ -0,0 +1,8 @@
+-- Remove as parcelas antes da coluna, para não deixá-las como gastos avulsos
+DELETE FROM gastos_variaveis WHERE compra_parcelada_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_gastos_variaveis_compra;
+ALTER TABLE gastos_variaveis DROP COLUMN IF EXISTS parcela_numero;
+ALTER TABLE gastos_variaveis DROP COLUMN IF EXISTS compra_parcelada_id;
+
+DROP TABLE IF EXISTS compras_parceladas;
@@ -0,0 +1,21 @@
+-- Compras parceladas. Cada parcela é gravada como um gasto variável ligado à compra,
+-- com a data do mês em que vence, para que resumo e listagens a contem no mês certo.
+
+CREATE TABLE IF NOT EXISTS compras_parceladas (
+    id            SERIAL PRIMARY KEY,
+    usuario_id    INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    nome          TEXT NOT NULL,
+    valor_total   NUMERIC(14, 2) NOT NULL CHECK (valor_total > 0),
+    num_parcelas  INTEGER NOT NULL CHECK (num_parcelas BETWEEN 1 AND 120),
+    primeira_data DATE NOT NULL,
+    categoria_id  INTEGER REFERENCES categorias (id) ON DELETE SET NULL,
+    cancelada_em  TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_compras_parceladas_usuario ON compras_parceladas (usuario_id);
+
+ALTER TABLE gastos_variaveis ADD COLUMN IF NOT EXISTS compra_parcelada_id INTEGER REFERENCES compras_parceladas (id) ON DELETE CASCADE;
+ALTER TABLE gastos_variaveis ADD COLUMN IF NOT EXISTS parcela_numero INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_gastos_variaveis_compra ON gastos_variaveis (compra_parcelada_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_gastos_variaveis_fatura;
+ALTER TABLE gastos_variaveis DROP COLUMN IF EXISTS vencimento_fatura;
+ALTER TABLE gastos_variaveis DROP COLUMN IF EXISTS cartao_id;
+
+DROP TABLE IF EXISTS cartoes;
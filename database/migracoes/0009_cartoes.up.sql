@@ -0,0 +1,20 @@
+-- Cartões de crédito. As compras feitas no cartão são gastos variáveis com cartao_id;
+-- vencimento_fatura guarda o vencimento da fatura em que a compra caiu, e é essa data
+-- (e não a da compra) que define o mês em que o gasto pesa no resumo.
+
+CREATE TABLE IF NOT EXISTS cartoes (
+    id             SERIAL PRIMARY KEY,
+    usuario_id     INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    nome           TEXT NOT NULL,
+    limite         NUMERIC(14, 2) NOT NULL CHECK (limite > 0),
+    dia_fechamento INTEGER NOT NULL CHECK (dia_fechamento BETWEEN 1 AND 31),
+    dia_vencimento INTEGER NOT NULL CHECK (dia_vencimento BETWEEN 1 AND 31),
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_cartoes_usuario ON cartoes (usuario_id);
+
+ALTER TABLE gastos_variaveis ADD COLUMN IF NOT EXISTS cartao_id INTEGER REFERENCES cartoes (id) ON DELETE SET NULL;
+ALTER TABLE gastos_variaveis ADD COLUMN IF NOT EXISTS vencimento_fatura DATE;
+
+CREATE INDEX IF NOT EXISTS idx_gastos_variaveis_fatura ON gastos_variaveis (cartao_id, vencimento_fatura);
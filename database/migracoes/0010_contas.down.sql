@@ -0,0 +1,6 @@
+ALTER TABLE gastos_variaveis DROP COLUMN IF EXISTS conta_id;
+ALTER TABLE gastos_fixos DROP COLUMN IF EXISTS conta_id;
+ALTER TABLE rendas DROP COLUMN IF EXISTS conta_id;
+
+DROP TABLE IF EXISTS transferencias;
+DROP TABLE IF EXISTS contas;
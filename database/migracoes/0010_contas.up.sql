@@ -0,0 +1,35 @@
+-- Contas (corrente, poupança, dinheiro, carteira digital) com saldo inicial.
+-- Rendas e gastos podem indicar de qual conta o dinheiro entrou ou saiu; as
+-- transferências movem dinheiro entre contas sem contar como renda nem gasto.
+
+CREATE TABLE IF NOT EXISTS contas (
+    id            SERIAL PRIMARY KEY,
+    usuario_id    INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    nome          TEXT NOT NULL,
+    tipo          TEXT NOT NULL CHECK (tipo IN ('corrente', 'poupanca', 'dinheiro', 'carteira_digital')),
+    saldo_inicial NUMERIC(14, 2) NOT NULL DEFAULT 0,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contas_usuario_nome ON contas (usuario_id, LOWER(nome));
+
+-- Contas com transferências não podem ser removidas, para não alterar o saldo da outra ponta
+CREATE TABLE IF NOT EXISTS transferencias (
+    id               SERIAL PRIMARY KEY,
+    usuario_id       INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    conta_origem_id  INTEGER NOT NULL REFERENCES contas (id) ON DELETE RESTRICT,
+    conta_destino_id INTEGER NOT NULL REFERENCES contas (id) ON DELETE RESTRICT,
+    valor            NUMERIC(14, 2) NOT NULL CHECK (valor > 0),
+    data             DATE NOT NULL,
+    descricao        TEXT NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (conta_origem_id <> conta_destino_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transferencias_usuario ON transferencias (usuario_id);
+CREATE INDEX IF NOT EXISTS idx_transferencias_origem ON transferencias (conta_origem_id);
+CREATE INDEX IF NOT EXISTS idx_transferencias_destino ON transferencias (conta_destino_id);
+
+ALTER TABLE rendas ADD COLUMN IF NOT EXISTS conta_id INTEGER REFERENCES contas (id) ON DELETE SET NULL;
+ALTER TABLE gastos_fixos ADD COLUMN IF NOT EXISTS conta_id INTEGER REFERENCES contas (id) ON DELETE SET NULL;
+ALTER TABLE gastos_variaveis ADD COLUMN IF NOT EXISTS conta_id INTEGER REFERENCES contas (id) ON DELETE SET NULL;
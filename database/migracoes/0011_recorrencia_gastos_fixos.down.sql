@@ -0,0 +1,5 @@
+ALTER TABLE gastos_fixos DROP CONSTRAINT IF EXISTS gastos_fixos_fim_check;
+ALTER TABLE gastos_fixos DROP COLUMN IF EXISTS dia;
+ALTER TABLE gastos_fixos DROP COLUMN IF EXISTS frequencia;
+ALTER TABLE gastos_fixos DROP COLUMN IF EXISTS fim;
+ALTER TABLE gastos_fixos DROP COLUMN IF EXISTS inicio;
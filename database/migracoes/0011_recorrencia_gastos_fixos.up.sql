@@ -0,0 +1,15 @@
+-- Recorrência dos gastos fixos: mês de início, mês final opcional (inclusive),
+-- frequência e dia do mês da cobrança. Gastos existentes passam a valer a partir
+-- do mês em que foram cadastrados, todo mês, como antes.
+
+ALTER TABLE gastos_fixos ADD COLUMN IF NOT EXISTS inicio DATE;
+UPDATE gastos_fixos SET inicio = date_trunc('month', created_at)::date WHERE inicio IS NULL;
+ALTER TABLE gastos_fixos ALTER COLUMN inicio SET DEFAULT date_trunc('month', CURRENT_DATE)::date;
+ALTER TABLE gastos_fixos ALTER COLUMN inicio SET NOT NULL;
+
+ALTER TABLE gastos_fixos ADD COLUMN IF NOT EXISTS fim DATE;
+ALTER TABLE gastos_fixos ADD COLUMN IF NOT EXISTS frequencia TEXT NOT NULL DEFAULT 'mensal'
+    CHECK (frequencia IN ('mensal', 'bimestral', 'trimestral', 'anual'));
+ALTER TABLE gastos_fixos ADD COLUMN IF NOT EXISTS dia INTEGER CHECK (dia BETWEEN 1 AND 31);
+
+ALTER TABLE gastos_fixos ADD CONSTRAINT gastos_fixos_fim_check CHECK (fim IS NULL OR fim >= inicio);
@@ -0,0 +1,15 @@
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS renda NUMERIC(14, 2) NOT NULL DEFAULT 0;
+UPDATE usuarios u SET renda = COALESCE((
+    SELECT SUM(valor) FROM rendas
+    WHERE usuario_id = u.id AND recorrencia = 'mensal'
+      AND data_efetiva <= CURRENT_DATE AND (data_fim IS NULL OR data_fim >= CURRENT_DATE)
+), 0);
+DELETE FROM rendas WHERE recorrencia = 'mensal';
+
+DROP INDEX IF EXISTS idx_rendas_usuario_data;
+ALTER TABLE rendas DROP CONSTRAINT IF EXISTS rendas_data_fim_check;
+ALTER TABLE rendas DROP COLUMN IF EXISTS data_fim;
+ALTER TABLE rendas DROP COLUMN IF EXISTS data_efetiva;
+ALTER TABLE rendas DROP COLUMN IF EXISTS recorrencia;
+ALTER TABLE rendas DROP COLUMN IF EXISTS descricao;
+ALTER TABLE rendas DROP COLUMN IF EXISTS fonte;
@@ -0,0 +1,24 @@
+-- Rendas passam a ter fonte, recorrência (única ou mensal) e data efetiva.
+-- A renda mensal do usuário deixa de ser uma coluna própria e passa a ser a soma
+-- das rendas mensais ativas; o valor antigo vira uma renda mensal de salário.
+
+ALTER TABLE rendas ADD COLUMN IF NOT EXISTS fonte TEXT NOT NULL DEFAULT 'outra'
+    CHECK (fonte IN ('salario', 'freelance', 'aluguel', 'decimo_terceiro', 'ferias', 'outra'));
+ALTER TABLE rendas ADD COLUMN IF NOT EXISTS descricao TEXT NOT NULL DEFAULT '';
+ALTER TABLE rendas ADD COLUMN IF NOT EXISTS recorrencia TEXT NOT NULL DEFAULT 'unica'
+    CHECK (recorrencia IN ('unica', 'mensal'));
+ALTER TABLE rendas ADD COLUMN IF NOT EXISTS data_efetiva DATE;
+UPDATE rendas SET data_efetiva = created_at::date WHERE data_efetiva IS NULL;
+ALTER TABLE rendas ALTER COLUMN data_efetiva SET DEFAULT CURRENT_DATE;
+ALTER TABLE rendas ALTER COLUMN data_efetiva SET NOT NULL;
+ALTER TABLE rendas ADD COLUMN IF NOT EXISTS data_fim DATE;
+
+ALTER TABLE rendas ADD CONSTRAINT rendas_data_fim_check
+    CHECK (data_fim IS NULL OR (recorrencia = 'mensal' AND data_fim >= data_efetiva));
+
+CREATE INDEX IF NOT EXISTS idx_rendas_usuario_data ON rendas (usuario_id, data_efetiva);
+
+INSERT INTO rendas (usuario_id, valor, fonte, recorrencia, data_efetiva)
+SELECT id, renda, 'salario', 'mensal', created_at::date FROM usuarios WHERE renda > 0;
+
+ALTER TABLE usuarios DROP COLUMN IF EXISTS renda;
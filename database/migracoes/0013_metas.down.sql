@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS aportes_meta;
+DROP TABLE IF EXISTS metas;
@@ -0,0 +1,24 @@
+-- Metas de economia e os aportes feitos nelas. Com descontar_do_saldo, os aportes
+-- do período são descontados do saldo disponível no resumo.
+
+CREATE TABLE IF NOT EXISTS metas (
+    id                 SERIAL PRIMARY KEY,
+    usuario_id         INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    nome               TEXT NOT NULL,
+    valor_alvo         NUMERIC(14, 2) NOT NULL CHECK (valor_alvo > 0),
+    prazo              DATE NOT NULL,
+    descontar_do_saldo BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_metas_usuario ON metas (usuario_id);
+
+CREATE TABLE IF NOT EXISTS aportes_meta (
+    id         SERIAL PRIMARY KEY,
+    meta_id    INTEGER NOT NULL REFERENCES metas (id) ON DELETE CASCADE,
+    valor      NUMERIC(14, 2) NOT NULL CHECK (valor > 0),
+    data       DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_aportes_meta_meta ON aportes_meta (meta_id, data);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS importacoes;
+DROP INDEX IF EXISTS idx_rendas_id_externo;
+DROP INDEX IF EXISTS idx_gastos_variaveis_id_externo;
+ALTER TABLE rendas DROP COLUMN IF EXISTS id_externo;
+ALTER TABLE gastos_variaveis DROP COLUMN IF EXISTS id_externo;
@@ -0,0 +1,24 @@
+-- Importação de extratos: cada arquivo enviado vira uma importação pendente com os
+-- lançamentos lidos, que o usuário revisa antes de confirmar. O id_externo (conta do
+-- banco + FITID, no OFX) identifica o lançamento de origem e impede que uma nova
+-- importação do mesmo extrato o duplique.
+
+ALTER TABLE gastos_variaveis ADD COLUMN IF NOT EXISTS id_externo TEXT;
+ALTER TABLE rendas ADD COLUMN IF NOT EXISTS id_externo TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_gastos_variaveis_id_externo
+    ON gastos_variaveis (usuario_id, id_externo) WHERE id_externo IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_rendas_id_externo
+    ON rendas (usuario_id, id_externo) WHERE id_externo IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS importacoes (
+    id            SERIAL PRIMARY KEY,
+    usuario_id    INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    formato       TEXT NOT NULL,
+    conta_id      INTEGER REFERENCES contas (id) ON DELETE SET NULL,
+    lancamentos   JSONB NOT NULL,
+    confirmada_em TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_importacoes_usuario ON importacoes (usuario_id);
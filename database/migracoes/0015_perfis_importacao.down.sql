@@ -0,0 +1,3 @@
+ALTER TABLE importacoes DROP COLUMN IF EXISTS erros;
+
+DROP TABLE IF EXISTS perfis_importacao;
@@ -0,0 +1,25 @@
+-- Perfis de importação CSV: o mapeamento de colunas do extrato de cada banco,
+-- salvo com um nome para ser reutilizado. As colunas são indicadas pelo nome no
+-- cabeçalho ou pela posição (a partir de 1).
+
+CREATE TABLE IF NOT EXISTS perfis_importacao (
+    id                   SERIAL PRIMARY KEY,
+    usuario_id           INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    nome                 TEXT NOT NULL,
+    delimitador          TEXT NOT NULL DEFAULT ',',
+    linhas_ignoradas     INTEGER NOT NULL DEFAULT 0 CHECK (linhas_ignoradas >= 0),
+    tem_cabecalho        BOOLEAN NOT NULL DEFAULT TRUE,
+    coluna_data          TEXT NOT NULL,
+    formato_data         TEXT NOT NULL,
+    coluna_descricao     TEXT NOT NULL,
+    coluna_valor         TEXT NOT NULL,
+    coluna_identificador TEXT,
+    separador_decimal    TEXT NOT NULL CHECK (separador_decimal IN (',', '.')),
+    convencao_sinal      TEXT NOT NULL CHECK (convencao_sinal IN ('debito_negativo', 'debito_positivo')),
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_perfis_importacao_usuario_nome ON perfis_importacao (usuario_id, LOWER(nome));
+
+-- Linhas do arquivo que não puderam ser lidas, para o relatório da importação
+ALTER TABLE importacoes ADD COLUMN IF NOT EXISTS erros JSONB NOT NULL DEFAULT '[]';
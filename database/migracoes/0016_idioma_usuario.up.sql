@@ -0,0 +1,4 @@
+-- Idioma preferido do usuário para as mensagens da API e a exportação; quando nulo,
+-- vale o cabeçalho Accept-Language da requisição.
+
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS idioma TEXT CHECK (idioma IN ('pt-BR', 'en', 'es'));
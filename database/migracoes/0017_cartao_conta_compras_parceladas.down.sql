@@ -0,0 +1,2 @@
+ALTER TABLE compras_parceladas DROP COLUMN IF EXISTS conta_id;
+ALTER TABLE compras_parceladas DROP COLUMN IF EXISTS cartao_id;
@@ -0,0 +1,5 @@
+-- Cartão e conta das compras parceladas. As parcelas herdam os dois; no cartão, cada parcela
+-- entra na fatura em que cai a sua data, como uma compra avulsa.
+
+ALTER TABLE compras_parceladas ADD COLUMN IF NOT EXISTS cartao_id INTEGER REFERENCES cartoes (id) ON DELETE SET NULL;
+ALTER TABLE compras_parceladas ADD COLUMN IF NOT EXISTS conta_id INTEGER REFERENCES contas (id) ON DELETE SET NULL;
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+)
+
+// Espera máxima por uma conexão livre do pool, sobrescrita por TEMPO_ESPERA_CONEXAO
+const esperaConexaoPadrao = 2 * time.Second
+
+// esperaConexaoDoAmbiente lê a espera máxima (ex.: "2s", "500ms") do ambiente ou usa o padrão
+func esperaConexaoDoAmbiente() time.Duration {
+	duracao, err := time.ParseDuration(os.Getenv("TEMPO_ESPERA_CONEXAO"))
+	if err != nil || duracao <= 0 {
+		return esperaConexaoPadrao
+	}
+	return duracao
+}
+
+// esperaConexao guarda no contexto da aquisição o contexto original e a função que libera o prazo
+type esperaConexao struct {
+	original context.Context
+	cancelar context.CancelFunc
+}
+
+type chaveEsperaConexao struct{}
+
+// rastreadorPool limita e registra a espera por conexões do pool. O prazo vale só para a
+// aquisição: a consulta em si continua limitada pelo contexto da requisição.
+type rastreadorPool struct {
+	espera time.Duration
+}
+
+func (r rastreadorPool) TraceAcquireStart(ctx context.Context, pool *pgxpool.Pool, data pgxpool.TraceAcquireStartData) context.Context {
+	comPrazo, cancelar := context.WithTimeout(ctx, r.espera)
+	return context.WithValue(comPrazo, chaveEsperaConexao{}, esperaConexao{ctx, cancelar})
+}
+
+func (r rastreadorPool) TraceAcquireEnd(ctx context.Context, pool *pgxpool.Pool, data pgxpool.TraceAcquireEndData) {
+	e, ok := ctx.Value(chaveEsperaConexao{}).(esperaConexao)
+	if !ok {
+		return
+	}
+	e.cancelar()
+
+	if erros.EsperaConexaoEsgotada(e.original, data.Err) {
+		stat := pool.Stat()
+		log.Printf("Espera por conexão do pool esgotada após %s (em uso: %d de %d)",
+			r.espera, stat.AcquiredConns(), stat.MaxConns())
+	}
+}
+
+// As consultas não são rastreadas; os métodos só completam a interface pgx.QueryTracer,
+// exigida para que o pool use o rastreador de aquisição
+func (rastreadorPool) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return ctx
+}
+
+func (rastreadorPool) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+}
@@ -0,0 +1,143 @@
+package erros
+
+import "net/http"
+
+// Codigo identifica o erro de forma estável; os clientes devem reagir ao código, não à mensagem
+type Codigo string
+
+// Códigos de erro da API
+const (
+	// Requisição
+	DadosInvalidos      Codigo = "dados_invalidos"
+	CorpoInvalido       Codigo = "corpo_invalido"
+	ArquivoMuitoGrande  Codigo = "arquivo_muito_grande"
+	ArquivoIlegivel     Codigo = "arquivo_ilegivel"
+	OFXInvalido         Codigo = "ofx_invalido"
+	CSVInvalido         Codigo = "csv_invalido"
+	ColunaNaoEncontrada Codigo = "coluna_nao_encontrada"
+	LinhasInvalidas     Codigo = "linhas_invalidas_demais"
+	LinhaMalFormatada   Codigo = "linha_mal_formatada"
+
+	// Autenticação
+	TokenAusente             Codigo = "token_ausente"
+	TokenFormatoInvalido     Codigo = "token_formato_invalido"
+	TokenInvalido            Codigo = "token_invalido"
+	TokenRevogado            Codigo = "token_revogado"
+	CredenciaisInvalidas     Codigo = "credenciais_invalidas"
+	RefreshTokenInvalido     Codigo = "refresh_token_invalido"
+	AutenticacaoIndisponivel Codigo = "autenticacao_indisponivel"
+
+	// Registros não encontrados (ou de outro usuário)
+	UsuarioNaoEncontrado          Codigo = "usuario_nao_encontrado"
+	GastoFixoNaoEncontrado        Codigo = "gasto_fixo_nao_encontrado"
+	GastoVariavelNaoEncontrado    Codigo = "gasto_variavel_nao_encontrado"
+	RendaNaoEncontrada            Codigo = "renda_nao_encontrada"
+	CategoriaNaoEncontrada        Codigo = "categoria_nao_encontrada"
+	ContaNaoEncontrada            Codigo = "conta_nao_encontrada"
+	CartaoNaoEncontrado           Codigo = "cartao_nao_encontrado"
+	TransferenciaNaoEncontrada    Codigo = "transferencia_nao_encontrada"
+	MetaNaoEncontrada             Codigo = "meta_nao_encontrada"
+	AporteNaoEncontrado           Codigo = "aporte_nao_encontrado"
+	OrcamentoNaoEncontrado        Codigo = "orcamento_nao_encontrado"
+	CompraParceladaNaoEncontrada  Codigo = "compra_parcelada_nao_encontrada"
+	ImportacaoNaoEncontrada       Codigo = "importacao_nao_encontrada"
+	PerfilImportacaoNaoEncontrado Codigo = "perfil_importacao_nao_encontrado"
+
+	// Conflitos
+	CadastroIndisponivel      Codigo = "cadastro_indisponivel"
+	CategoriaDuplicada        Codigo = "categoria_duplicada"
+	ContaDuplicada            Codigo = "conta_duplicada"
+	PerfilImportacaoDuplicado Codigo = "perfil_importacao_duplicado"
+	ContaComTransferencias    Codigo = "conta_com_transferencias"
+	ImportacaoConfirmada      Codigo = "importacao_confirmada"
+	ParcelaDeCompra           Codigo = "parcela_de_compra" // args: ID da compra parcelada
+
+	// Servidor
+	ErroInterno     Codigo = "erro_interno"
+	ServidorOcupado Codigo = "servidor_ocupado"
+	TempoEsgotado   Codigo = "tempo_esgotado"
+)
+
+// CodigoCampo identifica a regra de validação que o campo não cumpriu
+type CodigoCampo string
+
+// Códigos das validações de campo
+const (
+	CampoObrigatorio      CodigoCampo = "obrigatorio"
+	CampoTipoInvalido     CodigoCampo = "tipo_invalido"
+	CampoInvalido         CodigoCampo = "invalido"
+	CampoNumero           CodigoCampo = "numero"
+	CampoMaiorQueZero     CodigoCampo = "maior_que_zero"
+	CampoMinimo           CodigoCampo = "minimo"         // args: mínimo
+	CampoMaximo           CodigoCampo = "maximo"         // args: máximo
+	CampoIntervalo        CodigoCampo = "intervalo"      // args: mínimo, máximo
+	CampoTamanhoMinimo    CodigoCampo = "tamanho_minimo" // args: caracteres
+	CampoTamanhoMaximo    CodigoCampo = "tamanho_maximo" // args: caracteres
+	CampoTamanho          CodigoCampo = "tamanho"        // args: mínimo, máximo de caracteres
+	CampoEmail            CodigoCampo = "email"
+	CampoFormatoData      CodigoCampo = "formato_data"
+	CampoFormatoMes       CodigoCampo = "formato_mes"
+	CampoFormatoDataOuMes CodigoCampo = "formato_data_ou_mes"
+	CampoFormatoCor       CodigoCampo = "formato_cor"
+	CampoFormato          CodigoCampo = "formato"        // args: formato esperado
+	CampoOpcao            CodigoCampo = "opcao"          // args: []string com as opções
+	CampoNaoAnteriorA     CodigoCampo = "nao_anterior_a" // args: outro campo
+	CampoDiferenteDe      CodigoCampo = "diferente_de"   // args: outro campo
+	CampoExige            CodigoCampo = "exige"          // args: outro campo
+	CampoExclusivo        CodigoCampo = "exclusivo"      // args: outro campo
+	CampoUmDos            CodigoCampo = "um_dos"         // args: outro campo
+	CampoSomenteCom       CodigoCampo = "somente_com"    // args: outro campo, valor
+	CampoNaoEncontrado    CodigoCampo = "nao_encontrado"
+	CampoPequenoParcelas  CodigoCampo = "pequeno_para_parcelas"
+	CampoMenorQuePagas    CodigoCampo = "menor_que_pagas"   // args: parcelas pagas
+	CampoIncompativelPago CodigoCampo = "incompativel_pago" // args: valor pago
+	CampoCasasDecimais    CodigoCampo = "casas_decimais"
+)
+
+// statusPorCodigo é o status HTTP de cada código de erro
+var statusPorCodigo = map[Codigo]int{
+	DadosInvalidos:      http.StatusBadRequest,
+	CorpoInvalido:       http.StatusBadRequest,
+	ArquivoMuitoGrande:  http.StatusRequestEntityTooLarge,
+	ArquivoIlegivel:     http.StatusBadRequest,
+	OFXInvalido:         http.StatusBadRequest,
+	CSVInvalido:         http.StatusBadRequest,
+	ColunaNaoEncontrada: http.StatusBadRequest,
+	LinhasInvalidas:     http.StatusBadRequest,
+	LinhaMalFormatada:   http.StatusBadRequest,
+
+	TokenAusente:             http.StatusUnauthorized,
+	TokenFormatoInvalido:     http.StatusUnauthorized,
+	TokenInvalido:            http.StatusUnauthorized,
+	TokenRevogado:            http.StatusUnauthorized,
+	CredenciaisInvalidas:     http.StatusUnauthorized,
+	RefreshTokenInvalido:     http.StatusUnauthorized,
+	AutenticacaoIndisponivel: http.StatusServiceUnavailable,
+
+	UsuarioNaoEncontrado:          http.StatusNotFound,
+	GastoFixoNaoEncontrado:        http.StatusNotFound,
+	GastoVariavelNaoEncontrado:    http.StatusNotFound,
+	RendaNaoEncontrada:            http.StatusNotFound,
+	CategoriaNaoEncontrada:        http.StatusNotFound,
+	ContaNaoEncontrada:            http.StatusNotFound,
+	CartaoNaoEncontrado:           http.StatusNotFound,
+	TransferenciaNaoEncontrada:    http.StatusNotFound,
+	MetaNaoEncontrada:             http.StatusNotFound,
+	AporteNaoEncontrado:           http.StatusNotFound,
+	OrcamentoNaoEncontrado:        http.StatusNotFound,
+	CompraParceladaNaoEncontrada:  http.StatusNotFound,
+	ImportacaoNaoEncontrada:       http.StatusNotFound,
+	PerfilImportacaoNaoEncontrado: http.StatusNotFound,
+
+	CadastroIndisponivel:      http.StatusConflict,
+	CategoriaDuplicada:        http.StatusConflict,
+	ContaDuplicada:            http.StatusConflict,
+	PerfilImportacaoDuplicado: http.StatusConflict,
+	ContaComTransferencias:    http.StatusConflict,
+	ImportacaoConfirmada:      http.StatusConflict,
+	ParcelaDeCompra:           http.StatusConflict,
+
+	ErroInterno:     http.StatusInternalServerError,
+	ServidorOcupado: http.StatusServiceUnavailable,
+	TempoEsgotado:   http.StatusGatewayTimeout,
+}
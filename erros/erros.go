@@ -0,0 +1,107 @@
+// Package erros define o erro padrão da API: um código estável para os clientes reagirem,
+// o status HTTP, a mensagem do catálogo e os detalhes por campo das validações. Responder
+// envia o erro no formato application/problem+json (RFC 7807) com o ID da requisição.
+package erros
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jpeccia/quantogasto_app_server/idioma"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+// Erro é um erro da API. A mensagem vem do catálogo pelo código; a causa, quando houver,
+// vai apenas para o log.
+type Erro struct {
+	Codigo Codigo
+	Args   []any // argumentos da mensagem do catálogo
+	Campos []ErroCampo
+	Causa  error
+}
+
+// ErroCampo é o detalhe da validação de um campo do corpo, da query string ou do formulário
+type ErroCampo struct {
+	Campo  string
+	Codigo CodigoCampo
+	Args   []any // argumentos da mensagem, depois do nome do campo
+}
+
+// Novo cria um erro com o código e os argumentos da mensagem
+func Novo(codigo Codigo, args ...any) *Erro {
+	return &Erro{Codigo: codigo, Args: args}
+}
+
+// Validacao cria um erro de dados inválidos com os detalhes de cada campo
+func Validacao(campos ...ErroCampo) *Erro {
+	return &Erro{Codigo: DadosInvalidos, Campos: campos}
+}
+
+// NoCampo cria um erro de dados inválidos para um único campo
+func NoCampo(campo string, codigo CodigoCampo, args ...any) *Erro {
+	return Validacao(ErroCampo{Campo: campo, Codigo: codigo, Args: args})
+}
+
+// Interno embrulha uma falha inesperada; o contexto (ex.: "Erro ao buscar metas") e a causa vão
+// para o log e o cliente recebe só o código erro_interno. Erros da API passam sem alteração.
+func Interno(causa error, contexto string) *Erro {
+	var e *Erro
+	if errors.As(causa, &e) {
+		return e
+	}
+	return &Erro{Codigo: ErroInterno, Causa: fmt.Errorf("%s: %w", contexto, causa)}
+}
+
+// ComCausa registra a causa do erro para o log
+func (e *Erro) ComCausa(causa error) *Erro {
+	e.Causa = causa
+	return e
+}
+
+// Status é o status HTTP do código do erro
+func (e *Erro) Status() int {
+	return statusPorCodigo[e.Codigo]
+}
+
+// Mensagem é o texto do erro no idioma; nos erros de validação, as mensagens dos campos
+func (e *Erro) Mensagem(i idioma.Idioma) string {
+	if len(e.Campos) == 0 {
+		return formatar(i, mensagens.Texto(i, string(e.Codigo)), e.Args)
+	}
+	textos := make([]string, len(e.Campos))
+	for n, campo := range e.Campos {
+		textos[n] = campo.Mensagem(i)
+	}
+	return strings.Join(textos, "; ")
+}
+
+// Mensagem é o texto da validação do campo no idioma
+func (c ErroCampo) Mensagem(i idioma.Idioma) string {
+	return formatar(i, mensagens.Texto(i, "campo."+string(c.Codigo)), append([]any{c.Campo}, c.Args...))
+}
+
+func (e *Erro) Error() string {
+	if e.Causa != nil {
+		return e.Mensagem(idioma.Padrao) + ": " + e.Causa.Error()
+	}
+	return e.Mensagem(idioma.Padrao)
+}
+
+func (e *Erro) Unwrap() error { return e.Causa }
+
+// formatar preenche os argumentos da mensagem; listas de opções viram "'a', 'b', 'c'"
+// e valores em dinheiro seguem o formato do idioma
+func formatar(i idioma.Idioma, mensagem string, args []any) string {
+	valores := make([]any, len(args))
+	for n, arg := range args {
+		switch v := arg.(type) {
+		case []string:
+			arg = "'" + strings.Join(v, "', '") + "'"
+		case models.Dinheiro:
+			arg = i.FormatarValorTexto(v)
+		}
+		valores[n] = arg
+	}
+	return fmt.Sprintf(mensagem, valores...)
+}
@@ -0,0 +1,238 @@
+package erros
+
+import "github.com/jpeccia/quantogasto_app_server/idioma"
+
+// mensagens é o catálogo de mensagens de cada idioma: os códigos de erro e, com o prefixo
+// "campo.", os códigos de validação de campo (o primeiro argumento é sempre o nome do campo)
+var mensagens = idioma.Catalogo{
+	idioma.PortuguesBR: {
+		string(DadosInvalidos):      "Dados inválidos",
+		string(CorpoInvalido):       "O corpo da requisição é inválido: verifique o JSON e o tipo dos campos",
+		string(ArquivoMuitoGrande):  "O arquivo deve ter no máximo %d MB",
+		string(ArquivoIlegivel):     "Não foi possível ler o arquivo enviado",
+		string(OFXInvalido):         "Arquivo OFX inválido: elemento <OFX> não encontrado",
+		string(CSVInvalido):         "Arquivo CSV inválido",
+		string(ColunaNaoEncontrada): "Coluna '%s' não encontrada no cabeçalho do arquivo",
+		string(LinhasInvalidas):     "O arquivo tem mais de %d linhas inválidas; verifique o perfil de importação",
+		string(LinhaMalFormatada):   "Linha mal formatada",
+
+		string(TokenAusente):             "Token não fornecido",
+		string(TokenFormatoInvalido):     "Formato do token inválido. O token deve ser precedido de 'Bearer '",
+		string(TokenInvalido):            "Token inválido",
+		string(TokenRevogado):            "Token revogado. Por favor, faça login novamente",
+		string(CredenciaisInvalidas):     "E-mail ou senha inválidos",
+		string(RefreshTokenInvalido):     "Refresh token inválido ou expirado. Por favor, faça login novamente",
+		string(AutenticacaoIndisponivel): "Não foi possível validar o token. Tente novamente.",
+
+		string(UsuarioNaoEncontrado):          "Usuário não encontrado",
+		string(GastoFixoNaoEncontrado):        "Gasto fixo não encontrado ou você não tem permissão para acessá-lo",
+		string(GastoVariavelNaoEncontrado):    "Gasto variável não encontrado ou você não tem permissão para acessá-lo",
+		string(RendaNaoEncontrada):            "Renda não encontrada ou você não tem permissão para acessá-la",
+		string(CategoriaNaoEncontrada):        "Categoria não encontrada ou você não tem permissão para acessá-la",
+		string(ContaNaoEncontrada):            "Conta não encontrada ou você não tem permissão para acessá-la",
+		string(CartaoNaoEncontrado):           "Cartão não encontrado ou você não tem permissão para acessá-lo",
+		string(TransferenciaNaoEncontrada):    "Transferência não encontrada ou você não tem permissão para acessá-la",
+		string(MetaNaoEncontrada):             "Meta não encontrada ou você não tem permissão para acessá-la",
+		string(AporteNaoEncontrado):           "Aporte não encontrado ou você não tem permissão para acessá-lo",
+		string(OrcamentoNaoEncontrado):        "Orçamento não encontrado ou você não tem permissão para acessá-lo",
+		string(CompraParceladaNaoEncontrada):  "Compra parcelada não encontrada, cancelada ou você não tem permissão para acessá-la",
+		string(ImportacaoNaoEncontrada):       "Importação não encontrada",
+		string(PerfilImportacaoNaoEncontrado): "Perfil de importação não encontrado ou você não tem permissão para acessá-lo",
+
+		string(CadastroIndisponivel):      "Não foi possível registrar o usuário com os dados informados.",
+		string(CategoriaDuplicada):        "Já existe uma categoria com esse nome",
+		string(ContaDuplicada):            "Já existe uma conta com esse nome",
+		string(PerfilImportacaoDuplicado): "Já existe um perfil de importação com esse nome",
+		string(ContaComTransferencias):    "A conta possui transferências e não pode ser removida",
+		string(ImportacaoConfirmada):      "Esta importação já foi confirmada",
+		string(ParcelaDeCompra):           "Este gasto é uma parcela de compra parcelada; altere ou cancele a compra em /compras-parceladas/%d",
+
+		string(ErroInterno):     "Erro interno. Tente novamente mais tarde.",
+		string(ServidorOcupado): "Servidor ocupado. Tente novamente em instantes.",
+		string(TempoEsgotado):   "A operação excedeu o tempo limite. Tente novamente.",
+
+		"campo." + string(CampoObrigatorio):      "O campo '%s' é obrigatório",
+		"campo." + string(CampoTipoInvalido):     "O campo '%s' tem um tipo inválido",
+		"campo." + string(CampoInvalido):         "O campo '%s' é inválido",
+		"campo." + string(CampoNumero):           "O campo '%s' deve ser um número",
+		"campo." + string(CampoMaiorQueZero):     "O campo '%s' deve ser maior que zero",
+		"campo." + string(CampoMinimo):           "O campo '%s' deve ser no mínimo %v",
+		"campo." + string(CampoMaximo):           "O campo '%s' deve ser no máximo %v",
+		"campo." + string(CampoIntervalo):        "O campo '%s' deve estar entre %v e %v",
+		"campo." + string(CampoTamanhoMinimo):    "O campo '%s' deve ter no mínimo %v caracteres",
+		"campo." + string(CampoTamanhoMaximo):    "O campo '%s' deve ter no máximo %v caracteres",
+		"campo." + string(CampoTamanho):          "O campo '%s' deve ter entre %v e %v caracteres",
+		"campo." + string(CampoEmail):            "O campo '%s' deve ser um e-mail válido",
+		"campo." + string(CampoFormatoData):      "O campo '%s' deve estar no formato YYYY-MM-DD",
+		"campo." + string(CampoFormatoMes):       "O campo '%s' deve estar no formato YYYY-MM",
+		"campo." + string(CampoFormatoDataOuMes): "O campo '%s' deve estar no formato YYYY-MM ou YYYY-MM-DD",
+		"campo." + string(CampoFormatoCor):       "O campo '%s' deve estar no formato #RRGGBB",
+		"campo." + string(CampoFormato):          "O campo '%s' deve estar no formato %s",
+		"campo." + string(CampoOpcao):            "O campo '%s' deve ser um dos valores: %s",
+		"campo." + string(CampoNaoAnteriorA):     "O campo '%s' deve ser igual ou posterior a '%s'",
+		"campo." + string(CampoDiferenteDe):      "O campo '%s' deve ser diferente de '%s'",
+		"campo." + string(CampoExige):            "O campo '%s' exige que '%s' também seja informado",
+		"campo." + string(CampoExclusivo):        "O campo '%s' não pode ser usado junto com '%s'",
+		"campo." + string(CampoUmDos):            "Informe '%s' ou '%s' (apenas um deles)",
+		"campo." + string(CampoSomenteCom):       "O campo '%s' só pode ser usado quando '%s' é '%s'",
+		"campo." + string(CampoNaoEncontrado):    "O registro informado em '%s' não existe ou não pertence a você",
+		"campo." + string(CampoPequenoParcelas):  "O campo '%s' é pequeno demais para a quantidade de parcelas",
+		"campo." + string(CampoMenorQuePagas):    "O campo '%s' não pode ser menor que as %d parcelas já pagas",
+		"campo." + string(CampoIncompativelPago): "O campo '%s' não é compatível com o valor já pago (%s)",
+		"campo." + string(CampoCasasDecimais):    "O campo '%s' deve ter no máximo duas casas decimais",
+	},
+
+	idioma.Ingles: {
+		string(DadosInvalidos):      "Invalid data",
+		string(CorpoInvalido):       "The request body is invalid: check the JSON and the field types",
+		string(ArquivoMuitoGrande):  "The file must be at most %d MB",
+		string(ArquivoIlegivel):     "The uploaded file could not be read",
+		string(OFXInvalido):         "Invalid OFX file: <OFX> element not found",
+		string(CSVInvalido):         "Invalid CSV file",
+		string(ColunaNaoEncontrada): "Column '%s' not found in the file header",
+		string(LinhasInvalidas):     "The file has more than %d invalid lines; check the import profile",
+		string(LinhaMalFormatada):   "Malformed line",
+
+		string(TokenAusente):             "Token not provided",
+		string(TokenFormatoInvalido):     "Invalid token format. The token must be preceded by 'Bearer '",
+		string(TokenInvalido):            "Invalid token",
+		string(TokenRevogado):            "Token revoked. Please log in again",
+		string(CredenciaisInvalidas):     "Invalid e-mail or password",
+		string(RefreshTokenInvalido):     "Invalid or expired refresh token. Please log in again",
+		string(AutenticacaoIndisponivel): "The token could not be validated. Please try again.",
+
+		string(UsuarioNaoEncontrado):          "User not found",
+		string(GastoFixoNaoEncontrado):        "Fixed expense not found or you are not allowed to access it",
+		string(GastoVariavelNaoEncontrado):    "Variable expense not found or you are not allowed to access it",
+		string(RendaNaoEncontrada):            "Income not found or you are not allowed to access it",
+		string(CategoriaNaoEncontrada):        "Category not found or you are not allowed to access it",
+		string(ContaNaoEncontrada):            "Account not found or you are not allowed to access it",
+		string(CartaoNaoEncontrado):           "Card not found or you are not allowed to access it",
+		string(TransferenciaNaoEncontrada):    "Transfer not found or you are not allowed to access it",
+		string(MetaNaoEncontrada):             "Goal not found or you are not allowed to access it",
+		string(AporteNaoEncontrado):           "Contribution not found or you are not allowed to access it",
+		string(OrcamentoNaoEncontrado):        "Budget not found or you are not allowed to access it",
+		string(CompraParceladaNaoEncontrada):  "Installment purchase not found, cancelled or you are not allowed to access it",
+		string(ImportacaoNaoEncontrada):       "Import not found",
+		string(PerfilImportacaoNaoEncontrado): "Import profile not found or you are not allowed to access it",
+
+		string(CadastroIndisponivel):      "The user could not be registered with the given data.",
+		string(CategoriaDuplicada):        "A category with this name already exists",
+		string(ContaDuplicada):            "An account with this name already exists",
+		string(PerfilImportacaoDuplicado): "An import profile with this name already exists",
+		string(ContaComTransferencias):    "The account has transfers and cannot be removed",
+		string(ImportacaoConfirmada):      "This import has already been confirmed",
+		string(ParcelaDeCompra):           "This expense is an installment of a purchase; edit or cancel the purchase at /compras-parceladas/%d",
+
+		string(ErroInterno):     "Internal error. Please try again later.",
+		string(ServidorOcupado): "Server busy. Please try again shortly.",
+		string(TempoEsgotado):   "The operation timed out. Please try again.",
+
+		"campo." + string(CampoObrigatorio):      "The field '%s' is required",
+		"campo." + string(CampoTipoInvalido):     "The field '%s' has an invalid type",
+		"campo." + string(CampoInvalido):         "The field '%s' is invalid",
+		"campo." + string(CampoNumero):           "The field '%s' must be a number",
+		"campo." + string(CampoMaiorQueZero):     "The field '%s' must be greater than zero",
+		"campo." + string(CampoMinimo):           "The field '%s' must be at least %v",
+		"campo." + string(CampoMaximo):           "The field '%s' must be at most %v",
+		"campo." + string(CampoIntervalo):        "The field '%s' must be between %v and %v",
+		"campo." + string(CampoTamanhoMinimo):    "The field '%s' must have at least %v characters",
+		"campo." + string(CampoTamanhoMaximo):    "The field '%s' must have at most %v characters",
+		"campo." + string(CampoTamanho):          "The field '%s' must have between %v and %v characters",
+		"campo." + string(CampoEmail):            "The field '%s' must be a valid e-mail",
+		"campo." + string(CampoFormatoData):      "The field '%s' must use the format YYYY-MM-DD",
+		"campo." + string(CampoFormatoMes):       "The field '%s' must use the format YYYY-MM",
+		"campo." + string(CampoFormatoDataOuMes): "The field '%s' must use the format YYYY-MM or YYYY-MM-DD",
+		"campo." + string(CampoFormatoCor):       "The field '%s' must use the format #RRGGBB",
+		"campo." + string(CampoFormato):          "The field '%s' must use the format %s",
+		"campo." + string(CampoOpcao):            "The field '%s' must be one of: %s",
+		"campo." + string(CampoNaoAnteriorA):     "The field '%s' must be equal to or later than '%s'",
+		"campo." + string(CampoDiferenteDe):      "The field '%s' must be different from '%s'",
+		"campo." + string(CampoExige):            "The field '%s' requires '%s' to be provided as well",
+		"campo." + string(CampoExclusivo):        "The field '%s' cannot be used together with '%s'",
+		"campo." + string(CampoUmDos):            "Provide '%s' or '%s' (only one of them)",
+		"campo." + string(CampoSomenteCom):       "The field '%s' can only be used when '%s' is '%s'",
+		"campo." + string(CampoNaoEncontrado):    "The record given in '%s' does not exist or does not belong to you",
+		"campo." + string(CampoPequenoParcelas):  "The field '%s' is too small for the number of installments",
+		"campo." + string(CampoMenorQuePagas):    "The field '%s' cannot be less than the %d installments already paid",
+		"campo." + string(CampoIncompativelPago): "The field '%s' is not compatible with the amount already paid (%s)",
+		"campo." + string(CampoCasasDecimais):    "The field '%s' must have at most two decimal places",
+	},
+
+	idioma.Espanhol: {
+		string(DadosInvalidos):      "Datos inválidos",
+		string(CorpoInvalido):       "El cuerpo de la solicitud no es válido: revise el JSON y el tipo de los campos",
+		string(ArquivoMuitoGrande):  "El archivo debe tener como máximo %d MB",
+		string(ArquivoIlegivel):     "No se pudo leer el archivo enviado",
+		string(OFXInvalido):         "Archivo OFX inválido: no se encontró el elemento <OFX>",
+		string(CSVInvalido):         "Archivo CSV inválido",
+		string(ColunaNaoEncontrada): "No se encontró la columna '%s' en el encabezado del archivo",
+		string(LinhasInvalidas):     "El archivo tiene más de %d líneas inválidas; revise el perfil de importación",
+		string(LinhaMalFormatada):   "Línea mal formada",
+
+		string(TokenAusente):             "Token no proporcionado",
+		string(TokenFormatoInvalido):     "Formato de token inválido. El token debe ir precedido de 'Bearer '",
+		string(TokenInvalido):            "Token inválido",
+		string(TokenRevogado):            "Token revocado. Por favor, inicie sesión de nuevo",
+		string(CredenciaisInvalidas):     "Correo electrónico o contraseña inválidos",
+		string(RefreshTokenInvalido):     "Refresh token inválido o expirado. Por favor, inicie sesión de nuevo",
+		string(AutenticacaoIndisponivel): "No se pudo validar el token. Inténtelo de nuevo.",
+
+		string(UsuarioNaoEncontrado):          "Usuario no encontrado",
+		string(GastoFixoNaoEncontrado):        "Gasto fijo no encontrado o no tiene permiso para acceder a él",
+		string(GastoVariavelNaoEncontrado):    "Gasto variable no encontrado o no tiene permiso para acceder a él",
+		string(RendaNaoEncontrada):            "Ingreso no encontrado o no tiene permiso para acceder a él",
+		string(CategoriaNaoEncontrada):        "Categoría no encontrada o no tiene permiso para acceder a ella",
+		string(ContaNaoEncontrada):            "Cuenta no encontrada o no tiene permiso para acceder a ella",
+		string(CartaoNaoEncontrado):           "Tarjeta no encontrada o no tiene permiso para acceder a ella",
+		string(TransferenciaNaoEncontrada):    "Transferencia no encontrada o no tiene permiso para acceder a ella",
+		string(MetaNaoEncontrada):             "Meta no encontrada o no tiene permiso para acceder a ella",
+		string(AporteNaoEncontrado):           "Aporte no encontrado o no tiene permiso para acceder a él",
+		string(OrcamentoNaoEncontrado):        "Presupuesto no encontrado o no tiene permiso para acceder a él",
+		string(CompraParceladaNaoEncontrada):  "Compra a plazos no encontrada, cancelada o no tiene permiso para acceder a ella",
+		string(ImportacaoNaoEncontrada):       "Importación no encontrada",
+		string(PerfilImportacaoNaoEncontrado): "Perfil de importación no encontrado o no tiene permiso para acceder a él",
+
+		string(CadastroIndisponivel):      "No se pudo registrar el usuario con los datos proporcionados.",
+		string(CategoriaDuplicada):        "Ya existe una categoría con ese nombre",
+		string(ContaDuplicada):            "Ya existe una cuenta con ese nombre",
+		string(PerfilImportacaoDuplicado): "Ya existe un perfil de importación con ese nombre",
+		string(ContaComTransferencias):    "La cuenta tiene transferencias y no se puede eliminar",
+		string(ImportacaoConfirmada):      "Esta importación ya fue confirmada",
+		string(ParcelaDeCompra):           "Este gasto es una cuota de una compra a plazos; modifique o cancele la compra en /compras-parceladas/%d",
+
+		string(ErroInterno):     "Error interno. Inténtelo de nuevo más tarde.",
+		string(ServidorOcupado): "Servidor ocupado. Inténtelo de nuevo en unos instantes.",
+		string(TempoEsgotado):   "La operación superó el tiempo límite. Inténtelo de nuevo.",
+
+		"campo." + string(CampoObrigatorio):      "El campo '%s' es obligatorio",
+		"campo." + string(CampoTipoInvalido):     "El campo '%s' tiene un tipo inválido",
+		"campo." + string(CampoInvalido):         "El campo '%s' no es válido",
+		"campo." + string(CampoNumero):           "El campo '%s' debe ser un número",
+		"campo." + string(CampoMaiorQueZero):     "El campo '%s' debe ser mayor que cero",
+		"campo." + string(CampoMinimo):           "El campo '%s' debe ser como mínimo %v",
+		"campo." + string(CampoMaximo):           "El campo '%s' debe ser como máximo %v",
+		"campo." + string(CampoIntervalo):        "El campo '%s' debe estar entre %v y %v",
+		"campo." + string(CampoTamanhoMinimo):    "El campo '%s' debe tener como mínimo %v caracteres",
+		"campo." + string(CampoTamanhoMaximo):    "El campo '%s' debe tener como máximo %v caracteres",
+		"campo." + string(CampoTamanho):          "El campo '%s' debe tener entre %v y %v caracteres",
+		"campo." + string(CampoEmail):            "El campo '%s' debe ser un correo electrónico válido",
+		"campo." + string(CampoFormatoData):      "El campo '%s' debe tener el formato YYYY-MM-DD",
+		"campo." + string(CampoFormatoMes):       "El campo '%s' debe tener el formato YYYY-MM",
+		"campo." + string(CampoFormatoDataOuMes): "El campo '%s' debe tener el formato YYYY-MM o YYYY-MM-DD",
+		"campo." + string(CampoFormatoCor):       "El campo '%s' debe tener el formato #RRGGBB",
+		"campo." + string(CampoFormato):          "El campo '%s' debe tener el formato %s",
+		"campo." + string(CampoOpcao):            "El campo '%s' debe ser uno de los valores: %s",
+		"campo." + string(CampoNaoAnteriorA):     "El campo '%s' debe ser igual o posterior a '%s'",
+		"campo." + string(CampoDiferenteDe):      "El campo '%s' debe ser diferente de '%s'",
+		"campo." + string(CampoExige):            "El campo '%s' requiere que también se informe '%s'",
+		"campo." + string(CampoExclusivo):        "El campo '%s' no se puede usar junto con '%s'",
+		"campo." + string(CampoUmDos):            "Informe '%s' o '%s' (solo uno de ellos)",
+		"campo." + string(CampoSomenteCom):       "El campo '%s' solo se puede usar cuando '%s' es '%s'",
+		"campo." + string(CampoNaoEncontrado):    "El registro informado en '%s' no existe o no le pertenece",
+		"campo." + string(CampoPequenoParcelas):  "El campo '%s' es demasiado pequeño para la cantidad de cuotas",
+		"campo." + string(CampoMenorQuePagas):    "El campo '%s' no puede ser menor que las %d cuotas ya pagadas",
+		"campo." + string(CampoIncompativelPago): "El campo '%s' no es compatible con el importe ya pagado (%s)",
+		"campo." + string(CampoCasasDecimais):    "El campo '%s' debe tener como máximo dos decimales",
+	},
+}
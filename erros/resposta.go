@@ -0,0 +1,85 @@
+package erros
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpeccia/quantogasto_app_server/idioma"
+)
+
+// ChaveIDRequisicao é a chave do contexto do Gin com o ID da requisição (middleware.IDRequisicao)
+const ChaveIDRequisicao = "id_requisicao"
+
+// Status registrado quando o cliente desconecta antes da resposta (convenção do nginx)
+const statusClienteDesconectado = 499
+
+// problema é o corpo application/problem+json (RFC 7807). "code", "request_id" e "errors" são
+// extensões; "error" repete o "detail" para os clientes que ainda leem o formato anterior.
+type problema struct {
+	Tipo         string          `json:"type"`
+	Titulo       string          `json:"title"`
+	Status       int             `json:"status"`
+	Detalhe      string          `json:"detail"`
+	Instancia    string          `json:"instance"`
+	Codigo       Codigo          `json:"code"`
+	IDRequisicao string          `json:"request_id,omitempty"`
+	Campos       []problemaCampo `json:"errors,omitempty"`
+	Legado       string          `json:"error"`
+}
+
+type problemaCampo struct {
+	Campo    string      `json:"field"`
+	Codigo   CodigoCampo `json:"code"`
+	Mensagem string      `json:"message"`
+}
+
+// Responder envia o erro, com a mensagem no idioma da requisição, e interrompe a cadeia de
+// handlers. Erros que não são da API viram erro_interno. Nas falhas do servidor, o prazo da rota
+// esgotado vira tempo_esgotado (504) e a falta de conexões livres no pool, servidor_ocupado (503).
+func Responder(c *gin.Context, err error) {
+	e := Interno(err, "Erro não tratado")
+	idRequisicao := c.GetString(ChaveIDRequisicao)
+
+	if e.Status() >= http.StatusInternalServerError {
+		ctx := c.Request.Context()
+		switch {
+		case errors.Is(ctx.Err(), context.Canceled):
+			// O cliente desconectou: não há a quem responder
+			c.AbortWithStatus(statusClienteDesconectado)
+			return
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			e = Novo(TempoEsgotado).ComCausa(e.Causa)
+		case EsperaConexaoEsgotada(ctx, e.Causa):
+			c.Header("Retry-After", "1")
+			e = Novo(ServidorOcupado).ComCausa(e.Causa)
+		}
+		log.Printf("[%s] %s %s: %s: %v", idRequisicao, c.Request.Method, c.Request.URL.Path, e.Codigo, e.Causa)
+	}
+
+	i := idioma.DaRequisicao(c)
+	p := problema{
+		Tipo:         "about:blank",
+		Titulo:       http.StatusText(e.Status()),
+		Status:       e.Status(),
+		Detalhe:      e.Mensagem(i),
+		Instancia:    c.Request.URL.Path,
+		Codigo:       e.Codigo,
+		IDRequisicao: idRequisicao,
+	}
+	for _, campo := range e.Campos {
+		p.Campos = append(p.Campos, problemaCampo{campo.Campo, campo.Codigo, campo.Mensagem(i)})
+	}
+	p.Legado = p.Detalhe
+
+	c.Header("Content-Type", "application/problem+json; charset=utf-8")
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+// EsperaConexaoEsgotada indica se err veio do fim da espera por uma conexão livre do pool:
+// o erro é de prazo, mas o contexto da requisição (ctx) ainda está valendo
+func EsperaConexaoEsgotada(ctx context.Context, err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil
+}
@@ -0,0 +1,167 @@
+package erros
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+// ConfigurarValidacao faz o validador do Gin identificar os campos pelo nome no JSON
+// (ex.: "valor_alvo" em vez de "ValorAlvo"), usado nos detalhes dos erros de validação
+func ConfigurarValidacao() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(campo reflect.StructField) string {
+		nome, _, _ := strings.Cut(campo.Tag.Get("json"), ",")
+		if nome == "-" {
+			return ""
+		}
+		if nome == "" {
+			return campo.Name
+		}
+		return nome
+	})
+}
+
+// DaValidacao converte o erro de ShouldBindBodyWithJSON em dados_invalidos, com um detalhe para
+// cada regra de binding violada, ou em corpo_invalido se o JSON não puder ser lido.
+// destino é o valor passado ao bind, usado para achar o campo de um valor recusado.
+func DaValidacao(c *gin.Context, destino any, err error) *Erro {
+	var violacoes validator.ValidationErrors
+	if errors.As(err, &violacoes) {
+		campos := make([]ErroCampo, len(violacoes))
+		for i, v := range violacoes {
+			campos[i] = campoDaRegra(v)
+		}
+		return Validacao(campos...)
+	}
+
+	var tipo *json.UnmarshalTypeError
+	if errors.As(err, &tipo) && tipo.Field != "" {
+		return NoCampo(tipo.Field, CampoTipoInvalido)
+	}
+
+	// O decodificador não informa o campo do valor recusado; o corpo guardado pelo Gin é
+	// percorrido seguindo os campos de destino até o valor monetário que não pode ser lido
+	var casas *models.ErroCasasDecimais
+	if errors.As(err, &casas) && destino != nil {
+		if corpo, ok := c.Get(gin.BodyBytesKey); ok {
+			if campo, ok := campoComCasasDecimais(corpo.([]byte), reflect.TypeOf(destino), ""); ok {
+				return NoCampo(campo, CampoCasasDecimais)
+			}
+		}
+	}
+	return Novo(CorpoInvalido).ComCausa(err)
+}
+
+var tipoDinheiro = reflect.TypeOf(models.Dinheiro(0))
+
+// campoComCasasDecimais retorna o caminho, no formato dos erros de validação (ex.: "valor" ou
+// "ajustes[2].valor"), do primeiro valor monetário de corpo com mais de duas casas decimais
+func campoComCasasDecimais(corpo []byte, tipo reflect.Type, caminho string) (string, bool) {
+	for tipo.Kind() == reflect.Pointer {
+		tipo = tipo.Elem()
+	}
+
+	switch {
+	case tipo == tipoDinheiro:
+		var d models.Dinheiro
+		var casas *models.ErroCasasDecimais
+		return caminho, caminho != "" && errors.As(json.Unmarshal(corpo, &d), &casas)
+
+	case tipo.Kind() == reflect.Slice:
+		var itens []json.RawMessage
+		if json.Unmarshal(corpo, &itens) != nil {
+			return "", false
+		}
+		for i, item := range itens {
+			if campo, ok := campoComCasasDecimais(item, tipo.Elem(), fmt.Sprintf("%s[%d]", caminho, i)); ok {
+				return campo, true
+			}
+		}
+
+	case tipo.Kind() == reflect.Struct:
+		var objeto map[string]json.RawMessage
+		if json.Unmarshal(corpo, &objeto) != nil {
+			return "", false
+		}
+		for _, f := range reflect.VisibleFields(tipo) {
+			nome, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if !f.IsExported() || nome == "-" || (f.Anonymous && nome == "") {
+				continue
+			}
+			if nome == "" {
+				nome = f.Name
+			}
+			valor, ok := valorDoCampo(objeto, nome)
+			if !ok {
+				continue
+			}
+			filho := nome
+			if caminho != "" {
+				filho = caminho + "." + nome
+			}
+			if campo, ok := campoComCasasDecimais(valor, f.Type, filho); ok {
+				return campo, true
+			}
+		}
+	}
+	return "", false
+}
+
+// valorDoCampo busca a chave no objeto como o decodificador JSON, aceitando maiúsculas e minúsculas
+func valorDoCampo(objeto map[string]json.RawMessage, nome string) (json.RawMessage, bool) {
+	if valor, ok := objeto[nome]; ok {
+		return valor, true
+	}
+	for chave, valor := range objeto {
+		if strings.EqualFold(chave, nome) {
+			return valor, true
+		}
+	}
+	return nil, false
+}
+
+// campoDaRegra traduz a regra de binding violada no código de validação do campo
+func campoDaRegra(v validator.FieldError) ErroCampo {
+	campo := nomeDoCampo(v)
+	texto := v.Kind() == reflect.String
+	switch v.Tag() {
+	case "required":
+		return ErroCampo{Campo: campo, Codigo: CampoObrigatorio}
+	case "email":
+		return ErroCampo{Campo: campo, Codigo: CampoEmail}
+	case "min":
+		if texto {
+			return ErroCampo{Campo: campo, Codigo: CampoTamanhoMinimo, Args: []any{v.Param()}}
+		}
+		return ErroCampo{Campo: campo, Codigo: CampoMinimo, Args: []any{v.Param()}}
+	case "max":
+		if texto {
+			return ErroCampo{Campo: campo, Codigo: CampoTamanhoMaximo, Args: []any{v.Param()}}
+		}
+		return ErroCampo{Campo: campo, Codigo: CampoMaximo, Args: []any{v.Param()}}
+	}
+	return ErroCampo{Campo: campo, Codigo: CampoInvalido}
+}
+
+// nomeDoCampo é o nome do campo no JSON; dentro de listas, o caminho a partir da lista
+// (ex.: "ajustes[2].id_externo"), sem o nome do tipo nem de structs embutidas
+func nomeDoCampo(v validator.FieldError) string {
+	partes := strings.Split(v.Namespace(), ".")
+	for i, parte := range partes {
+		if strings.Contains(parte, "[") {
+			return strings.Join(partes[i:], ".")
+		}
+	}
+	return v.Field()
+}
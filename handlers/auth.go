@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jpeccia/quantogasto_app_server/auth"
+	"github.com/jpeccia/quantogasto_app_server/database"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+)
+
+// normalizarEmail padroniza o e-mail para comparação e armazenamento
+func normalizarEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// emitirTokens gera um token de acesso e um refresh token da família informada
+func emitirTokens(ctx context.Context, db executor, usuarioID int, familiaID string) (gin.H, error) {
+	token, err := auth.GerarToken(usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshHash, err := auth.GerarRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// Armazena apenas o hash do refresh token
+	query := `
+        INSERT INTO refresh_tokens (usuario_id, familia_id, token_hash, expira_em)
+        VALUES ($1, $2, $3, $4)
+    `
+	_, err = db.Exec(ctx, query, usuarioID, familiaID, refreshHash, time.Now().Add(auth.DuracaoRefreshToken()))
+	if err != nil {
+		return nil, err
+	}
+
+	return gin.H{
+		"token":         token,
+		"token_expira":  int(auth.DuracaoAccessToken().Seconds()),
+		"refresh_token": refreshToken,
+	}, nil
+}
+
+// emitirTokensNovaFamilia inicia uma nova família de refresh tokens (cadastro ou login)
+func emitirTokensNovaFamilia(ctx context.Context, usuarioID int) (gin.H, error) {
+	familiaID, err := auth.GerarFamiliaToken()
+	if err != nil {
+		return nil, err
+	}
+	return emitirTokens(ctx, database.DB, usuarioID, familiaID)
+}
+
+// Login autentica o usuário com e-mail e senha e emite um novo token
+func Login(c *gin.Context) {
+	var input struct {
+		Email string `json:"email" binding:"required"`
+		Senha string `json:"senha" binding:"required"`
+	}
+
+	// Valida o JSON recebido
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
+		return
+	}
+
+	// Busca o usuário pelo e-mail
+	var id int
+	var senhaHash string
+	query := `SELECT id, COALESCE(senha_hash, '') FROM usuarios WHERE email = $1`
+	err := database.DB.QueryRow(c.Request.Context(), query, normalizarEmail(input.Email)).Scan(&id, &senhaHash)
+	if err != nil && err != pgx.ErrNoRows {
+		responderErroInterno(c, err, "Erro ao realizar login. Tente novamente mais tarde.")
+		return
+	}
+
+	// A senha é sempre verificada (mesmo sem usuário) para manter o tempo de resposta uniforme.
+	// O erro é o mesmo com ou sem usuário, para não revelar quais contas existem.
+	if !auth.VerificarSenha(senhaHash, input.Senha) {
+		erros.Responder(c, erros.Novo(erros.CredenciaisInvalidas))
+		return
+	}
+
+	// Gera o token de acesso e o refresh token
+	tokens, err := emitirTokensNovaFamilia(c.Request.Context(), id)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao gerar token. Tente novamente mais tarde.")
+		return
+	}
+
+	tokens["message"] = mensagem(c, msgLoginRealizado)
+	tokens["id"] = id
+	c.JSON(http.StatusOK, tokens)
+}
+
+// errRefreshReutilizado sinaliza que um refresh token já rotacionado foi apresentado de novo
+var errRefreshReutilizado = errors.New("refresh token reutilizado")
+
+// errRefreshInvalido sinaliza token inexistente, expirado ou revogado
+var errRefreshInvalido = errors.New("refresh token inválido")
+
+// rotacionarRefreshToken marca o refresh token como usado e emite o próximo da família.
+// Em caso de reutilização, a revogação da família é confirmada antes de retornar o erro.
+func rotacionarRefreshToken(ctx context.Context, refreshToken string) (tokens gin.H, usuarioID int, err error) {
+	tx, err := database.DB.Begin(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Bloqueia o registro para que duas renovações simultâneas não usem o mesmo token
+	var id int64
+	var familiaID string
+	var expiraEm time.Time
+	var usadoEm, revogadoEm *time.Time
+	query := `
+        SELECT id, usuario_id, familia_id, expira_em, usado_em, revogado_em
+        FROM refresh_tokens
+        WHERE token_hash = $1
+        FOR UPDATE
+    `
+	err = tx.QueryRow(ctx, query, auth.HashRefreshToken(refreshToken)).Scan(
+		&id, &usuarioID, &familiaID, &expiraEm, &usadoEm, &revogadoEm,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, 0, errRefreshInvalido
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if revogadoEm != nil || time.Now().After(expiraEm) {
+		return nil, usuarioID, errRefreshInvalido
+	}
+
+	// Token já rotacionado: possível roubo, revoga a família inteira
+	if usadoEm != nil {
+		query := `
+            UPDATE refresh_tokens SET revogado_em = NOW()
+            WHERE familia_id = $1 AND revogado_em IS NULL
+        `
+		if _, err := tx.Exec(ctx, query, familiaID); err != nil {
+			return nil, usuarioID, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, usuarioID, err
+		}
+		return nil, usuarioID, errRefreshReutilizado
+	}
+
+	// Marca o token atual como utilizado e emite o próximo da mesma família
+	if _, err := tx.Exec(ctx, `UPDATE refresh_tokens SET usado_em = NOW() WHERE id = $1`, id); err != nil {
+		return nil, usuarioID, err
+	}
+	tokens, err = emitirTokens(ctx, tx, usuarioID, familiaID)
+	if err != nil {
+		return nil, usuarioID, err
+	}
+
+	return tokens, usuarioID, tx.Commit(ctx)
+}
+
+// RenovarToken troca um refresh token válido por um novo par de tokens (rotação).
+// Se um token já utilizado for apresentado novamente, toda a família é revogada.
+func RenovarToken(c *gin.Context) {
+	var input struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+
+	// Valida o JSON recebido
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
+		return
+	}
+
+	// Refresh tokens inválidos, expirados, revogados ou reutilizados recebem o mesmo erro
+	tokens, usuarioID, err := rotacionarRefreshToken(c.Request.Context(), input.RefreshToken)
+	switch {
+	case errors.Is(err, errRefreshReutilizado):
+		log.Printf("Reutilização de refresh token detectada para o usuário %d; família revogada", usuarioID)
+		erros.Responder(c, erros.Novo(erros.RefreshTokenInvalido))
+		return
+	case errors.Is(err, errRefreshInvalido):
+		erros.Responder(c, erros.Novo(erros.RefreshTokenInvalido))
+		return
+	case err != nil:
+		responderErroInterno(c, err, "Erro ao renovar token. Tente novamente mais tarde.")
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout revoga o token de acesso atual e, se informado, a família do refresh token
+func Logout(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	claims := c.MustGet("claims").(*auth.Claims)
+
+	// O corpo é opcional: sem refresh_token, apenas o token de acesso é revogado
+	var input struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := auth.RevogarToken(ctx, claims); err != nil {
+		responderErroInterno(c, err, "Erro ao realizar logout. Tente novamente mais tarde.")
+		return
+	}
+
+	// Revoga a família do refresh token para que a sessão não possa ser renovada
+	if input.RefreshToken != "" {
+		query := `
+            UPDATE refresh_tokens SET revogado_em = NOW()
+            WHERE revogado_em IS NULL AND usuario_id = $1 AND familia_id = (
+                SELECT familia_id FROM refresh_tokens WHERE token_hash = $2
+            )
+        `
+		_, err := database.DB.Exec(ctx, query, usuarioID, auth.HashRefreshToken(input.RefreshToken))
+		if err != nil {
+			responderErroInterno(c, err, "Erro ao realizar logout. Tente novamente mais tarde.")
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgLogoutRealizado)})
+}
+
+// LogoutTodos revoga todos os tokens de acesso e refresh tokens do usuário em todos os dispositivos
+func LogoutTodos(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	claims := c.MustGet("claims").(*auth.Claims)
+
+	ctx := c.Request.Context()
+	if err := auth.RevogarTodosTokens(ctx, usuarioID); err != nil {
+		responderErroInterno(c, err, "Erro ao realizar logout. Tente novamente mais tarde.")
+		return
+	}
+
+	// O marco do logout-all não alcança tokens emitidos no mesmo segundo; o atual é revogado pelo jti
+	if err := auth.RevogarToken(ctx, claims); err != nil {
+		responderErroInterno(c, err, "Erro ao realizar logout. Tente novamente mais tarde.")
+		return
+	}
+
+	query := `UPDATE refresh_tokens SET revogado_em = NOW() WHERE usuario_id = $1 AND revogado_em IS NULL`
+	if _, err := database.DB.Exec(ctx, query, usuarioID); err != nil {
+		responderErroInterno(c, err, "Erro ao realizar logout. Tente novamente mais tarde.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgLogoutTodos)})
+}
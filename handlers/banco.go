@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// executor é satisfeito tanto pelo pool quanto por uma transação
+type executor interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// consultor permite que funções auxiliares rodem consultas no pool ou dentro de uma transação
+type consultor interface {
+	executor
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
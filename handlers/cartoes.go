@@ -0,0 +1,399 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jpeccia/quantogasto_app_server/database"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/models"
+	"github.com/jpeccia/quantogasto_app_server/repositorio"
+)
+
+// Uma fatura é considerada paga depois do vencimento; até lá, suas compras ocupam o limite
+const condicaoFaturaEmAberto = "vencimento_fatura >= CURRENT_DATE"
+
+// errCartaoNaoEncontrado indica cartão inexistente ou de outro usuário
+var errCartaoNaoEncontrado = errors.New("cartão não encontrado")
+
+// entradaCartao são os campos aceitos na criação e edição de cartões
+type entradaCartao struct {
+	Nome          string          `json:"nome" binding:"required,max=50"`
+	Limite        models.Dinheiro `json:"limite" binding:"required"`
+	DiaFechamento int             `json:"dia_fechamento" binding:"required,min=1,max=31"`
+	DiaVencimento int             `json:"dia_vencimento" binding:"required,min=1,max=31"`
+}
+
+// validarEntradaCartao lê e valida o JSON de um cartão, respondendo em caso de erro
+func validarEntradaCartao(c *gin.Context) (entradaCartao, bool) {
+	var input entradaCartao
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
+		return input, false
+	}
+	if input.Limite <= 0 {
+		erros.Responder(c, erros.NoCampo("limite", erros.CampoMaiorQueZero))
+		return input, false
+	}
+	return input, true
+}
+
+// diaNoMes retorna o dia informado dentro do mês da data, limitado ao último dia do mês
+// (dia 31 em fevereiro vira 28 ou 29)
+func diaNoMes(mes time.Time, dia int) time.Time {
+	inicio := inicioDoMes(mes)
+	if ultimoDia := inicio.AddDate(0, 1, -1).Day(); dia > ultimoDia {
+		dia = ultimoDia
+	}
+	return time.Date(inicio.Year(), inicio.Month(), dia, 0, 0, 0, 0, time.UTC)
+}
+
+// mesFechamento retorna o mês em que fecha a fatura que vence no mês informado.
+// Quando o vencimento é antes (ou no mesmo dia) do fechamento, a fatura fecha no mês anterior.
+func mesFechamento(cartao models.Cartao, mesVencimento time.Time) time.Time {
+	mes := inicioDoMes(mesVencimento)
+	if cartao.DiaVencimento <= cartao.DiaFechamento {
+		return mes.AddDate(0, -1, 0)
+	}
+	return mes
+}
+
+// vencimentoFatura retorna o vencimento da fatura em que cai uma compra feita na data.
+// Compras a partir do dia de fechamento entram na fatura seguinte.
+func vencimentoFatura(cartao models.Cartao, data time.Time) time.Time {
+	fechamento := inicioDoMes(data)
+	if !data.Before(diaNoMes(data, cartao.DiaFechamento)) {
+		fechamento = fechamento.AddDate(0, 1, 0)
+	}
+	vencimento := fechamento
+	if cartao.DiaVencimento <= cartao.DiaFechamento {
+		vencimento = vencimento.AddDate(0, 1, 0)
+	}
+	return diaNoMes(vencimento, cartao.DiaVencimento)
+}
+
+// periodoFatura retorna o intervalo de datas de compra da fatura que vence no mês informado:
+// do fechamento anterior (inclusive) até o fechamento da fatura (exclusivo)
+func periodoFatura(cartao models.Cartao, mesVencimento time.Time) periodo {
+	mes := mesFechamento(cartao, mesVencimento)
+	return periodo{
+		Inicio: diaNoMes(mes.AddDate(0, -1, 0), cartao.DiaFechamento),
+		Fim:    diaNoMes(mes, cartao.DiaFechamento),
+	}
+}
+
+// carregarCartao busca um cartão do usuário
+func carregarCartao(ctx context.Context, db consultor, usuarioID, cartaoID int) (models.Cartao, error) {
+	var cartao models.Cartao
+	query := `
+        SELECT id, usuario_id, nome, limite, dia_fechamento, dia_vencimento, created_at
+        FROM cartoes
+        WHERE id = $1 AND usuario_id = $2
+    `
+	err := db.QueryRow(ctx, query, cartaoID, usuarioID).Scan(
+		&cartao.ID, &cartao.UsuarioID, &cartao.Nome, &cartao.Limite,
+		&cartao.DiaFechamento, &cartao.DiaVencimento, &cartao.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return cartao, errCartaoNaoEncontrado
+	}
+	return cartao, err
+}
+
+// limiteDisponivel desconta do limite as compras das faturas ainda não vencidas
+func limiteDisponivel(ctx context.Context, db consultor, cartao models.Cartao) (models.Dinheiro, error) {
+	var usado models.Dinheiro
+	query := `SELECT COALESCE(SUM(valor), 0) FROM gastos_variaveis WHERE cartao_id = $1 AND ` + condicaoFaturaEmAberto
+	err := db.QueryRow(ctx, query, cartao.ID).Scan(&usado)
+	return cartao.Limite - usado, err
+}
+
+// cartaoDoLancamento busca o cartão informado em um gasto ou compra parcelada (nil sem cartão).
+// Responde com erro e retorna false se o cartão não for do usuário.
+func (h *Handler) cartaoDoLancamento(c *gin.Context, usuarioID int, cartaoID *int) (*models.Cartao, bool) {
+	if cartaoID == nil {
+		return nil, true
+	}
+	cartao, err := h.cartoes.Obter(c.Request.Context(), usuarioID, *cartaoID)
+	if errors.Is(err, repositorio.ErrNaoEncontrado) {
+		erros.Responder(c, erros.NoCampo("cartao_id", erros.CampoNaoEncontrado))
+		return nil, false
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao verificar o cartão")
+		return nil, false
+	}
+	return &cartao, true
+}
+
+// calcularVencimentoDoGasto valida o cartão informado em um gasto variável e retorna o vencimento
+// da fatura da compra (nil sem cartão). Responde com erro e retorna false em caso de falha.
+func (h *Handler) calcularVencimentoDoGasto(c *gin.Context, usuarioID int, cartaoID *int, data string) (*time.Time, bool) {
+	cartao, ok := h.cartaoDoLancamento(c, usuarioID, cartaoID)
+	if !ok || cartao == nil {
+		return nil, ok
+	}
+	dataCompra, err := time.Parse(formatoData, data)
+	if err != nil {
+		erros.Responder(c, erros.NoCampo("data", erros.CampoFormatoData))
+		return nil, false
+	}
+	vencimento := vencimentoFatura(*cartao, dataCompra)
+	return &vencimento, true
+}
+
+// recalcularFaturas atualiza o vencimento da fatura de todas as compras do cartão,
+// usado quando os dias de fechamento ou vencimento mudam
+func recalcularFaturas(ctx context.Context, tx pgx.Tx, cartao models.Cartao) error {
+	rows, err := tx.Query(ctx, `SELECT id, data FROM gastos_variaveis WHERE cartao_id = $1`, cartao.ID)
+	if err != nil {
+		return err
+	}
+	vencimentos := map[int]time.Time{}
+	for rows.Next() {
+		var id int
+		var data time.Time
+		if err := rows.Scan(&id, &data); err != nil {
+			rows.Close()
+			return err
+		}
+		vencimentos[id] = vencimentoFatura(cartao, data)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, vencimento := range vencimentos {
+		if _, err := tx.Exec(ctx, `UPDATE gastos_variaveis SET vencimento_fatura = $1 WHERE id = $2`, vencimento, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListarCartoes lista os cartões do usuário com o limite disponível de cada um
+func ListarCartoes(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	query := `
+        SELECT c.id, c.usuario_id, c.nome, c.limite, c.dia_fechamento, c.dia_vencimento, c.created_at,
+               c.limite - COALESCE((
+                   SELECT SUM(valor) FROM gastos_variaveis
+                   WHERE cartao_id = c.id AND ` + condicaoFaturaEmAberto + `
+               ), 0)
+        FROM cartoes c
+        WHERE c.usuario_id = $1
+        ORDER BY c.nome, c.id
+    `
+	rows, err := database.DB.Query(c.Request.Context(), query, usuarioID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar cartões")
+		return
+	}
+	defer rows.Close()
+
+	type cartaoComLimite struct {
+		models.Cartao
+		LimiteDisponivel models.Dinheiro `json:"limite_disponivel"`
+	}
+	cartoes := []cartaoComLimite{}
+	for rows.Next() {
+		var item cartaoComLimite
+		err := rows.Scan(&item.ID, &item.UsuarioID, &item.Nome, &item.Limite, &item.DiaFechamento,
+			&item.DiaVencimento, &item.CreatedAt, &item.LimiteDisponivel)
+		if err != nil {
+			responderErroInterno(c, err, "Erro ao buscar cartões")
+			return
+		}
+		cartoes = append(cartoes, item)
+	}
+	if err := rows.Err(); err != nil {
+		responderErroInterno(c, err, "Erro ao buscar cartões")
+		return
+	}
+
+	c.JSON(http.StatusOK, cartoes)
+}
+
+// CriarCartao cadastra um cartão de crédito do usuário
+func CriarCartao(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	input, ok := validarEntradaCartao(c)
+	if !ok {
+		return
+	}
+
+	// Insere o cartão no banco de dados
+	query := `
+        INSERT INTO cartoes (usuario_id, nome, limite, dia_fechamento, dia_vencimento)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id
+    `
+	var id int
+	err := database.DB.QueryRow(c.Request.Context(), query, usuarioID, input.Nome, input.Limite,
+		input.DiaFechamento, input.DiaVencimento).Scan(&id)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao criar cartão")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgCartaoCriado), "id": id})
+}
+
+// EditarCartao atualiza um cartão do usuário. Se os dias de fechamento ou vencimento mudarem,
+// as compras do cartão são redistribuídas entre as faturas.
+func EditarCartao(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	cartaoID, ok := idDaURL(c, erros.CartaoNaoEncontrado)
+	if !ok {
+		return
+	}
+
+	input, ok := validarEntradaCartao(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	err := pgx.BeginFunc(ctx, database.DB, func(tx pgx.Tx) error {
+		atual, err := carregarCartao(ctx, tx, usuarioID, cartaoID)
+		if err != nil {
+			return err
+		}
+
+		query := `
+            UPDATE cartoes
+            SET nome = $1, limite = $2, dia_fechamento = $3, dia_vencimento = $4
+            WHERE id = $5
+        `
+		if _, err := tx.Exec(ctx, query, input.Nome, input.Limite, input.DiaFechamento, input.DiaVencimento, atual.ID); err != nil {
+			return err
+		}
+
+		if atual.DiaFechamento == input.DiaFechamento && atual.DiaVencimento == input.DiaVencimento {
+			return nil
+		}
+		atual.DiaFechamento, atual.DiaVencimento = input.DiaFechamento, input.DiaVencimento
+		return recalcularFaturas(ctx, tx, atual)
+	})
+	if errors.Is(err, errCartaoNaoEncontrado) {
+		erros.Responder(c, erros.Novo(erros.CartaoNaoEncontrado))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao atualizar o cartão")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgCartaoAtualizado)})
+}
+
+// RemoverCartao remove um cartão do usuário. As compras continuam como gastos variáveis,
+// sem cartão, e mantêm o mês da fatura em que caíram.
+func RemoverCartao(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	cartaoID, ok := idDaURL(c, erros.CartaoNaoEncontrado)
+	if !ok {
+		return
+	}
+
+	query := `DELETE FROM cartoes WHERE id = $1 AND usuario_id = $2`
+	result, err := database.DB.Exec(c.Request.Context(), query, cartaoID, usuarioID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao remover o cartão")
+		return
+	}
+
+	// Verifica se o cartão foi encontrado e removido
+	if result.RowsAffected() == 0 {
+		erros.Responder(c, erros.Novo(erros.CartaoNaoEncontrado))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgCartaoRemovido)})
+}
+
+// ObterFatura retorna a fatura do cartão que vence no mês informado (YYYY-MM),
+// com o total, o limite disponível e as compras que a compõem
+func ObterFatura(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	cartaoID, ok := idDaURL(c, erros.CartaoNaoEncontrado)
+	if !ok {
+		return
+	}
+
+	mes, err := time.Parse(formatoMes, c.Param("mes"))
+	if err != nil {
+		erros.Responder(c, erros.NoCampo("mes", erros.CampoFormatoMes))
+		return
+	}
+
+	ctx := c.Request.Context()
+	cartao, err := carregarCartao(ctx, database.DB, usuarioID, cartaoID)
+	if errors.Is(err, errCartaoNaoEncontrado) {
+		erros.Responder(c, erros.Novo(erros.CartaoNaoEncontrado))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar o cartão")
+		return
+	}
+
+	vencimento := diaNoMes(mes, cartao.DiaVencimento)
+	query := `
+        SELECT id, usuario_id, nome, valor, to_char(data, 'YYYY-MM-DD'), categoria_id,
+               compra_parcelada_id, parcela_numero, cartao_id, to_char(vencimento_fatura, 'YYYY-MM-DD'), conta_id, created_at
+        FROM gastos_variaveis
+        WHERE cartao_id = $1 AND vencimento_fatura = $2
+        ORDER BY data, id
+    `
+	rows, err := database.DB.Query(ctx, query, cartao.ID, vencimento)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar a fatura")
+		return
+	}
+	defer rows.Close()
+
+	compras := []models.GastoVariavel{}
+	var total models.Dinheiro
+	for rows.Next() {
+		var g models.GastoVariavel
+		err := rows.Scan(&g.ID, &g.UsuarioID, &g.Nome, &g.Valor, &g.Data, &g.CategoriaID,
+			&g.CompraParceladaID, &g.ParcelaNumero, &g.CartaoID, &g.VencimentoFatura, &g.ContaID, &g.CreatedAt)
+		if err != nil {
+			responderErroInterno(c, err, "Erro ao buscar a fatura")
+			return
+		}
+		compras = append(compras, g)
+		total += g.Valor
+	}
+	if err := rows.Err(); err != nil {
+		responderErroInterno(c, err, "Erro ao buscar a fatura")
+		return
+	}
+
+	disponivel, err := limiteDisponivel(ctx, database.DB, cartao)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao calcular o limite disponível")
+		return
+	}
+
+	p := periodoFatura(cartao, mes)
+	c.JSON(http.StatusOK, gin.H{
+		"cartao_id":         cartao.ID,
+		"mes":               mes.Format(formatoMes),
+		"periodo":           p.JSON(),
+		"fechamento":        p.Fim.Format(formatoData),
+		"vencimento":        vencimento.Format(formatoData),
+		"total":             total,
+		"limite":            cartao.Limite,
+		"limite_disponivel": disponivel,
+		"compras":           compras,
+	})
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+func dataTeste(t *testing.T, texto string) time.Time {
+	t.Helper()
+	d, err := time.Parse(formatoData, texto)
+	if err != nil {
+		t.Fatalf("data inválida %q: %v", texto, err)
+	}
+	return d
+}
+
+func TestVencimentoFatura(t *testing.T) {
+	casos := []struct {
+		nome       string
+		fechamento int
+		vencimento int
+		compra     string
+		esperado   string
+	}{
+		{"antes do fechamento, vence no mês seguinte", 25, 5, "2025-03-24", "2025-04-05"},
+		{"no dia do fechamento entra na próxima fatura", 25, 5, "2025-03-25", "2025-05-05"},
+		{"depois do fechamento entra na próxima fatura", 25, 5, "2025-03-26", "2025-05-05"},
+		{"vencimento depois do fechamento, no mesmo mês", 5, 15, "2025-03-04", "2025-03-15"},
+		{"vencimento depois do fechamento, no dia do fechamento", 5, 15, "2025-03-05", "2025-04-15"},
+		{"vencimento no mesmo dia do fechamento", 10, 10, "2025-03-09", "2025-04-10"},
+		{"virada do ano", 25, 5, "2025-12-26", "2026-02-05"},
+		{"fechamento 31 em fevereiro fecha no dia 28", 31, 10, "2025-02-27", "2025-03-10"},
+		{"fechamento 31 em fevereiro, compra no dia 28", 31, 10, "2025-02-28", "2025-04-10"},
+		{"fechamento 31 em ano bissexto", 31, 10, "2024-02-28", "2024-03-10"},
+		{"vencimento 31 em fevereiro vence no último dia", 10, 31, "2025-02-05", "2025-02-28"},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			cartao := models.Cartao{DiaFechamento: caso.fechamento, DiaVencimento: caso.vencimento}
+			vencimento := vencimentoFatura(cartao, dataTeste(t, caso.compra))
+			if got := vencimento.Format(formatoData); got != caso.esperado {
+				t.Errorf("vencimentoFatura(fecha %d, vence %d, %s) = %s, esperado %s",
+					caso.fechamento, caso.vencimento, caso.compra, got, caso.esperado)
+			}
+		})
+	}
+}
+
+func TestPeriodoFaturaCobreAsComprasDoVencimento(t *testing.T) {
+	cartoes := []models.Cartao{
+		{DiaFechamento: 25, DiaVencimento: 5},
+		{DiaFechamento: 5, DiaVencimento: 15},
+		{DiaFechamento: 31, DiaVencimento: 10},
+	}
+	for _, cartao := range cartoes {
+		mes := dataTeste(t, "2025-03-01")
+		p := periodoFatura(cartao, mes)
+		for dia := p.Inicio.AddDate(0, 0, -3); dia.Before(p.Fim.AddDate(0, 0, 3)); dia = dia.AddDate(0, 0, 1) {
+			dentro := !dia.Before(p.Inicio) && dia.Before(p.Fim)
+			noMes := inicioDoMes(vencimentoFatura(cartao, dia)).Equal(mes)
+			if dentro != noMes {
+				t.Errorf("cartão fecha %d vence %d: compra em %s dentro do período = %v, vence em março = %v",
+					cartao.DiaFechamento, cartao.DiaVencimento, dia.Format(formatoData), dentro, noMes)
+			}
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpeccia/quantogasto_app_server/database"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+// Formato aceito para as cores das categorias (#RRGGBB)
+var regexCor = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// Cor usada quando a categoria é criada sem cor
+const corCategoriaPadrao = "#9E9E9E"
+
+// entradaCategoria são os campos aceitos na criação e edição de categorias
+type entradaCategoria struct {
+	Nome  string `json:"nome" binding:"required,max=50"`
+	Cor   string `json:"cor"`
+	Icone string `json:"icone" binding:"max=50"`
+}
+
+// validarEntradaCategoria lê e valida o JSON de uma categoria, respondendo em caso de erro
+func validarEntradaCategoria(c *gin.Context) (entradaCategoria, bool) {
+	var input entradaCategoria
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
+		return input, false
+	}
+
+	if input.Cor == "" {
+		input.Cor = corCategoriaPadrao
+	}
+	if !regexCor.MatchString(input.Cor) {
+		erros.Responder(c, erros.NoCampo("cor", erros.CampoFormatoCor))
+		return input, false
+	}
+	return input, true
+}
+
+// validarCategoriaDoGasto responde com erro se a categoria informada não puder ser usada pelo usuário
+func (h *Handler) validarCategoriaDoGasto(c *gin.Context, usuarioID int, categoriaID *int) bool {
+	if categoriaID == nil {
+		return true
+	}
+	disponivel, err := h.categorias.Disponivel(c.Request.Context(), usuarioID, *categoriaID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao verificar a categoria")
+		return false
+	}
+	if !disponivel {
+		erros.Responder(c, erros.NoCampo("categoria_id", erros.CampoNaoEncontrado))
+		return false
+	}
+	return true
+}
+
+// ListarCategorias lista as categorias padrão e as personalizadas do usuário
+func ListarCategorias(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	query := `
+        SELECT id, usuario_id, nome, cor, icone, usuario_id IS NULL, created_at
+        FROM categorias
+        WHERE usuario_id IS NULL OR usuario_id = $1
+        ORDER BY usuario_id NULLS FIRST, nome
+    `
+	rows, err := database.DB.Query(c.Request.Context(), query, usuarioID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar categorias")
+		return
+	}
+	defer rows.Close()
+
+	categorias := []models.Categoria{}
+	for rows.Next() {
+		var cat models.Categoria
+		if err := rows.Scan(&cat.ID, &cat.UsuarioID, &cat.Nome, &cat.Cor, &cat.Icone, &cat.Padrao, &cat.CreatedAt); err != nil {
+			responderErroInterno(c, err, "Erro ao buscar categorias")
+			return
+		}
+		categorias = append(categorias, cat)
+	}
+	if err := rows.Err(); err != nil {
+		responderErroInterno(c, err, "Erro ao buscar categorias")
+		return
+	}
+
+	c.JSON(http.StatusOK, categorias)
+}
+
+// CriarCategoria cria uma categoria personalizada do usuário
+func CriarCategoria(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	input, ok := validarEntradaCategoria(c)
+	if !ok {
+		return
+	}
+
+	// Insere a categoria no banco de dados
+	query := `
+        INSERT INTO categorias (usuario_id, nome, cor, icone)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id
+    `
+	var id int
+	err := database.DB.QueryRow(c.Request.Context(), query, usuarioID, input.Nome, input.Cor, input.Icone).Scan(&id)
+	if err != nil {
+		if database.ErroViolacaoUnica(err) {
+			erros.Responder(c, erros.Novo(erros.CategoriaDuplicada))
+			return
+		}
+		responderErroInterno(c, err, "Erro ao criar categoria")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgCategoriaCriada), "id": id})
+}
+
+// EditarCategoria atualiza uma categoria personalizada do usuário (as padrão não podem ser editadas)
+func EditarCategoria(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	categoriaID, ok := idDaURL(c, erros.CategoriaNaoEncontrada)
+	if !ok {
+		return
+	}
+
+	input, ok := validarEntradaCategoria(c)
+	if !ok {
+		return
+	}
+
+	// Atualiza a categoria no banco de dados
+	query := `
+        UPDATE categorias
+        SET nome = $1, cor = $2, icone = $3
+        WHERE id = $4 AND usuario_id = $5
+    `
+	result, err := database.DB.Exec(c.Request.Context(), query, input.Nome, input.Cor, input.Icone, categoriaID, usuarioID)
+	if err != nil {
+		if database.ErroViolacaoUnica(err) {
+			erros.Responder(c, erros.Novo(erros.CategoriaDuplicada))
+			return
+		}
+		responderErroInterno(c, err, "Erro ao atualizar a categoria")
+		return
+	}
+
+	// Verifica se a categoria foi encontrada e atualizada
+	if result.RowsAffected() == 0 {
+		erros.Responder(c, erros.Novo(erros.CategoriaNaoEncontrada))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgCategoriaAtualizada)})
+}
+
+// RemoverCategoria remove uma categoria personalizada; os gastos dela ficam sem categoria
+func RemoverCategoria(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	categoriaID, ok := idDaURL(c, erros.CategoriaNaoEncontrada)
+	if !ok {
+		return
+	}
+
+	query := `DELETE FROM categorias WHERE id = $1 AND usuario_id = $2`
+	result, err := database.DB.Exec(c.Request.Context(), query, categoriaID, usuarioID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao remover a categoria")
+		return
+	}
+
+	// Verifica se a categoria foi encontrada e removida
+	if result.RowsAffected() == 0 {
+		erros.Responder(c, erros.Novo(erros.CategoriaNaoEncontrada))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgCategoriaRemovida)})
+}
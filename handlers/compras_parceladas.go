@@ -0,0 +1,446 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jpeccia/quantogasto_app_server/database"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+// Parcelas com data até hoje são consideradas pagas e não mudam ao editar ou cancelar a compra
+const condicaoParcelaEmAberto = "data > CURRENT_DATE"
+
+// errCompraNaoEncontrada indica compra inexistente, de outro usuário ou já cancelada
+var errCompraNaoEncontrada = errors.New("compra parcelada não encontrada")
+
+// entradaValoresParcelas são os campos de valor comuns à criação e à edição da compra
+type entradaValoresParcelas struct {
+	Nome         string           `json:"nome" binding:"required"`
+	ValorTotal   *models.Dinheiro `json:"valor_total"`   // informe o total...
+	ValorParcela *models.Dinheiro `json:"valor_parcela"` // ...ou o valor de cada parcela
+	NumParcelas  int              `json:"num_parcelas" binding:"required,min=1,max=120"`
+	CategoriaID  *int             `json:"categoria_id"`
+	CartaoID     *int             `json:"cartao_id"`
+	ContaID      *int             `json:"conta_id"`
+}
+
+// validar confere que exatamente um dos valores foi informado e que ele é positivo
+func (e entradaValoresParcelas) validar() error {
+	if (e.ValorTotal == nil) == (e.ValorParcela == nil) {
+		return erros.NoCampo("valor_total", erros.CampoUmDos, "valor_parcela")
+	}
+	if e.ValorTotal != nil && *e.ValorTotal <= 0 {
+		return erros.NoCampo("valor_total", erros.CampoMaiorQueZero)
+	}
+	if e.ValorParcela != nil && *e.ValorParcela <= 0 {
+		return erros.NoCampo("valor_parcela", erros.CampoMaiorQueZero)
+	}
+	return nil
+}
+
+// inserirParcelas grava as parcelas da compra a partir do número primeiraParcela, com vencimentos
+// mensais contados a partir da primeira data da compra. No cartão, cada parcela entra na fatura
+// em que cai a sua data.
+func inserirParcelas(ctx context.Context, db executor, compra models.CompraParcelada, cartao *models.Cartao, primeiraParcela int, valores []models.Dinheiro) error {
+	primeiraData, err := time.Parse(formatoData, compra.PrimeiraData)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        INSERT INTO gastos_variaveis (usuario_id, nome, valor, data, categoria_id, compra_parcelada_id, parcela_numero,
+                                      cartao_id, vencimento_fatura, conta_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+    `
+	for i, valor := range valores {
+		numero := primeiraParcela + i
+		nome := fmt.Sprintf("%s (%d/%d)", compra.Nome, numero, compra.NumParcelas)
+		data := adicionarMeses(primeiraData, numero-1)
+		var vencimento *time.Time
+		if cartao != nil {
+			v := vencimentoFatura(*cartao, data)
+			vencimento = &v
+		}
+		_, err := db.Exec(ctx, query, compra.UsuarioID, nome, valor, data, compra.CategoriaID, compra.ID, numero,
+			compra.CartaoID, vencimento, compra.ContaID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// carregarCompraParcelada busca a compra do usuário e suas parcelas
+func carregarCompraParcelada(ctx context.Context, db consultor, usuarioID, compraID int) (models.CompraParcelada, error) {
+	var compra models.CompraParcelada
+	query := `
+        SELECT id, usuario_id, nome, valor_total, num_parcelas, to_char(primeira_data, 'YYYY-MM-DD'),
+               categoria_id, cartao_id, conta_id, cancelada_em, created_at
+        FROM compras_parceladas
+        WHERE id = $1 AND usuario_id = $2
+    `
+	err := db.QueryRow(ctx, query, compraID, usuarioID).Scan(
+		&compra.ID, &compra.UsuarioID, &compra.Nome, &compra.ValorTotal, &compra.NumParcelas,
+		&compra.PrimeiraData, &compra.CategoriaID, &compra.CartaoID, &compra.ContaID, &compra.CanceladaEm, &compra.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return compra, errCompraNaoEncontrada
+	}
+	if err != nil {
+		return compra, err
+	}
+
+	query = `
+        SELECT id, usuario_id, nome, valor, to_char(data, 'YYYY-MM-DD'), categoria_id,
+               compra_parcelada_id, parcela_numero, cartao_id, to_char(vencimento_fatura, 'YYYY-MM-DD'), conta_id, created_at
+        FROM gastos_variaveis
+        WHERE compra_parcelada_id = $1
+        ORDER BY parcela_numero, data
+    `
+	rows, err := db.Query(ctx, query, compra.ID)
+	if err != nil {
+		return compra, err
+	}
+	defer rows.Close()
+
+	compra.Parcelas = []models.GastoVariavel{}
+	for rows.Next() {
+		var g models.GastoVariavel
+		err := rows.Scan(&g.ID, &g.UsuarioID, &g.Nome, &g.Valor, &g.Data, &g.CategoriaID,
+			&g.CompraParceladaID, &g.ParcelaNumero, &g.CartaoID, &g.VencimentoFatura, &g.ContaID, &g.CreatedAt)
+		if err != nil {
+			return compra, err
+		}
+		compra.Parcelas = append(compra.Parcelas, g)
+	}
+	return compra, rows.Err()
+}
+
+// AdicionarCompraParcelada registra uma compra parcelada e gera uma parcela por mês
+func (h *Handler) AdicionarCompraParcelada(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	var input struct {
+		entradaValoresParcelas
+		PrimeiraData string `json:"primeira_data" binding:"required"`
+	}
+
+	// Valida o JSON recebido
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
+		return
+	}
+	if err := input.validar(); err != nil {
+		erros.Responder(c, err)
+		return
+	}
+
+	// Valida a data no formato esperado (YYYY-MM-DD)
+	if _, err := time.Parse(formatoData, input.PrimeiraData); err != nil {
+		erros.Responder(c, erros.NoCampo("primeira_data", erros.CampoFormatoData))
+		return
+	}
+
+	// Verifica se a categoria, o cartão e a conta podem ser usados pelo usuário
+	if !h.validarCategoriaDoGasto(c, usuarioID, input.CategoriaID) {
+		return
+	}
+	cartao, ok := h.cartaoDoLancamento(c, usuarioID, input.CartaoID)
+	if !ok || !h.validarContaDoLancamento(c, usuarioID, "conta_id", input.ContaID) {
+		return
+	}
+
+	// Calcula o valor de cada parcela; centavos que sobram da divisão vão para as primeiras
+	var valores []models.Dinheiro
+	if input.ValorTotal != nil {
+		valores = input.ValorTotal.Dividir(input.NumParcelas)
+	} else {
+		valores = make([]models.Dinheiro, input.NumParcelas)
+		for i := range valores {
+			valores[i] = *input.ValorParcela
+		}
+	}
+	var total models.Dinheiro
+	for _, v := range valores {
+		total += v
+	}
+	if valores[len(valores)-1] <= 0 {
+		erros.Responder(c, erros.NoCampo("valor_total", erros.CampoPequenoParcelas))
+		return
+	}
+
+	compra := models.CompraParcelada{
+		UsuarioID:    usuarioID,
+		Nome:         input.Nome,
+		ValorTotal:   total,
+		NumParcelas:  input.NumParcelas,
+		PrimeiraData: input.PrimeiraData,
+		CategoriaID:  input.CategoriaID,
+		CartaoID:     input.CartaoID,
+		ContaID:      input.ContaID,
+	}
+
+	// Grava a compra e as parcelas na mesma transação
+	ctx := c.Request.Context()
+	err := pgx.BeginFunc(ctx, database.DB, func(tx pgx.Tx) error {
+		query := `
+            INSERT INTO compras_parceladas (usuario_id, nome, valor_total, num_parcelas, primeira_data, categoria_id, cartao_id, conta_id)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+            RETURNING id
+        `
+		err := tx.QueryRow(ctx, query, usuarioID, compra.Nome, compra.ValorTotal, compra.NumParcelas,
+			compra.PrimeiraData, compra.CategoriaID, compra.CartaoID, compra.ContaID).Scan(&compra.ID)
+		if err != nil {
+			return err
+		}
+		return inserirParcelas(ctx, tx, compra, cartao, 1, valores)
+	})
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao adicionar compra parcelada")
+		return
+	}
+
+	compra, err = carregarCompraParcelada(ctx, database.DB, usuarioID, compra.ID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar compra parcelada")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgCompraParceladaAdicionada), "compra": compra})
+}
+
+// ListarComprasParceladas lista as compras parceladas do usuário, das mais recentes para as mais antigas
+func ListarComprasParceladas(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	query := `
+        SELECT id, usuario_id, nome, valor_total, num_parcelas, to_char(primeira_data, 'YYYY-MM-DD'),
+               categoria_id, cartao_id, conta_id, cancelada_em, created_at
+        FROM compras_parceladas
+        WHERE usuario_id = $1
+        ORDER BY created_at DESC, id DESC
+    `
+	rows, err := database.DB.Query(c.Request.Context(), query, usuarioID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar compras parceladas")
+		return
+	}
+	defer rows.Close()
+
+	compras := []models.CompraParcelada{}
+	for rows.Next() {
+		var compra models.CompraParcelada
+		err := rows.Scan(&compra.ID, &compra.UsuarioID, &compra.Nome, &compra.ValorTotal, &compra.NumParcelas,
+			&compra.PrimeiraData, &compra.CategoriaID, &compra.CartaoID, &compra.ContaID, &compra.CanceladaEm, &compra.CreatedAt)
+		if err != nil {
+			responderErroInterno(c, err, "Erro ao buscar compras parceladas")
+			return
+		}
+		compras = append(compras, compra)
+	}
+	if err := rows.Err(); err != nil {
+		responderErroInterno(c, err, "Erro ao buscar compras parceladas")
+		return
+	}
+
+	c.JSON(http.StatusOK, compras)
+}
+
+// ObterCompraParcelada retorna uma compra parcelada com todas as suas parcelas
+func ObterCompraParcelada(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	compraID, ok := idDaURL(c, erros.CompraParceladaNaoEncontrada)
+	if !ok {
+		return
+	}
+
+	compra, err := carregarCompraParcelada(c.Request.Context(), database.DB, usuarioID, compraID)
+	if errors.Is(err, errCompraNaoEncontrada) {
+		erros.Responder(c, erros.Novo(erros.CompraParceladaNaoEncontrada))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar compra parcelada")
+		return
+	}
+
+	c.JSON(http.StatusOK, compra)
+}
+
+// EditarCompraParcelada altera nome, categoria, cartão, conta, valor ou número de parcelas da compra.
+// As parcelas já vencidas são mantidas; as em aberto são recalculadas com os novos dados.
+func (h *Handler) EditarCompraParcelada(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	compraID, ok := idDaURL(c, erros.CompraParceladaNaoEncontrada)
+	if !ok {
+		return
+	}
+
+	var input entradaValoresParcelas
+
+	// Valida o JSON recebido
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
+		return
+	}
+	if err := input.validar(); err != nil {
+		erros.Responder(c, err)
+		return
+	}
+
+	// Verifica se a categoria, o cartão e a conta podem ser usados pelo usuário
+	if !h.validarCategoriaDoGasto(c, usuarioID, input.CategoriaID) {
+		return
+	}
+	cartao, ok := h.cartaoDoLancamento(c, usuarioID, input.CartaoID)
+	if !ok || !h.validarContaDoLancamento(c, usuarioID, "conta_id", input.ContaID) {
+		return
+	}
+
+	// Erros de validação que dependem das parcelas pagas desfazem a transação e são respondidos
+	// como vieram (responderErroInterno repassa os erros da API)
+	ctx := c.Request.Context()
+	err := pgx.BeginFunc(ctx, database.DB, func(tx pgx.Tx) error {
+		// Bloqueia a compra para evitar edições simultâneas
+		var compra models.CompraParcelada
+		query := `
+            SELECT id, usuario_id, to_char(primeira_data, 'YYYY-MM-DD')
+            FROM compras_parceladas
+            WHERE id = $1 AND usuario_id = $2 AND cancelada_em IS NULL
+            FOR UPDATE
+        `
+		err := tx.QueryRow(ctx, query, compraID, usuarioID).Scan(&compra.ID, &compra.UsuarioID, &compra.PrimeiraData)
+		if err == pgx.ErrNoRows {
+			return errCompraNaoEncontrada
+		}
+		if err != nil {
+			return err
+		}
+
+		// Soma o que já foi pago (parcelas vencidas)
+		var qtdPagas int
+		var totalPago models.Dinheiro
+		query = `
+            SELECT COUNT(*), COALESCE(SUM(valor), 0) FROM gastos_variaveis
+            WHERE compra_parcelada_id = $1 AND NOT (` + condicaoParcelaEmAberto + `)
+        `
+		if err := tx.QueryRow(ctx, query, compra.ID).Scan(&qtdPagas, &totalPago); err != nil {
+			return err
+		}
+
+		// Recalcula as parcelas em aberto
+		restantes := input.NumParcelas - qtdPagas
+		if restantes < 0 {
+			return erros.NoCampo("num_parcelas", erros.CampoMenorQuePagas, qtdPagas)
+		}
+		var valores []models.Dinheiro
+		if input.ValorParcela != nil {
+			valores = make([]models.Dinheiro, restantes)
+			for i := range valores {
+				valores[i] = *input.ValorParcela
+			}
+		} else {
+			saldo := *input.ValorTotal - totalPago
+			if (restantes == 0 && saldo != 0) || (restantes > 0 && saldo < models.Dinheiro(restantes)) {
+				return erros.NoCampo("valor_total", erros.CampoIncompativelPago, totalPago)
+			}
+			valores = saldo.Dividir(restantes)
+		}
+
+		compra.Nome = input.Nome
+		compra.NumParcelas = input.NumParcelas
+		compra.CategoriaID = input.CategoriaID
+		compra.CartaoID = input.CartaoID
+		compra.ContaID = input.ContaID
+		compra.ValorTotal = totalPago
+		for _, v := range valores {
+			compra.ValorTotal += v
+		}
+
+		query = `
+            UPDATE compras_parceladas
+            SET nome = $1, valor_total = $2, num_parcelas = $3, categoria_id = $4, cartao_id = $5, conta_id = $6
+            WHERE id = $7
+        `
+		_, err = tx.Exec(ctx, query, compra.Nome, compra.ValorTotal, compra.NumParcelas, compra.CategoriaID,
+			compra.CartaoID, compra.ContaID, compra.ID)
+		if err != nil {
+			return err
+		}
+
+		// Substitui as parcelas em aberto pelas recalculadas
+		query = `DELETE FROM gastos_variaveis WHERE compra_parcelada_id = $1 AND ` + condicaoParcelaEmAberto
+		if _, err := tx.Exec(ctx, query, compra.ID); err != nil {
+			return err
+		}
+		return inserirParcelas(ctx, tx, compra, cartao, qtdPagas+1, valores)
+	})
+
+	if errors.Is(err, errCompraNaoEncontrada) {
+		erros.Responder(c, erros.Novo(erros.CompraParceladaNaoEncontrada))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao atualizar a compra parcelada")
+		return
+	}
+
+	compra, err := carregarCompraParcelada(ctx, database.DB, usuarioID, compraID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar compra parcelada")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgCompraParceladaAtualizada), "compra": compra})
+}
+
+// CancelarCompraParcelada cancela a compra e remove as parcelas em aberto (as vencidas são mantidas)
+func CancelarCompraParcelada(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	compraID, ok := idDaURL(c, erros.CompraParceladaNaoEncontrada)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	var removidas int64
+	err := pgx.BeginFunc(ctx, database.DB, func(tx pgx.Tx) error {
+		query := `
+            UPDATE compras_parceladas SET cancelada_em = NOW()
+            WHERE id = $1 AND usuario_id = $2 AND cancelada_em IS NULL
+        `
+		result, err := tx.Exec(ctx, query, compraID, usuarioID)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return errCompraNaoEncontrada
+		}
+
+		query = `DELETE FROM gastos_variaveis WHERE compra_parcelada_id = $1 AND ` + condicaoParcelaEmAberto
+		result, err = tx.Exec(ctx, query, compraID)
+		removidas = result.RowsAffected()
+		return err
+	})
+	if errors.Is(err, errCompraNaoEncontrada) {
+		erros.Responder(c, erros.Novo(erros.CompraParceladaNaoEncontrada))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao cancelar a compra parcelada")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":            mensagem(c, msgCompraParceladaCancelada),
+		"parcelas_removidas": removidas,
+	})
+}
@@ -0,0 +1,327 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/models"
+	"github.com/jpeccia/quantogasto_app_server/repositorio"
+)
+
+// Tipos de conta aceitos na criação e edição
+var tiposConta = map[string]bool{
+	models.ContaCorrente:        true,
+	models.ContaPoupanca:        true,
+	models.ContaDinheiro:        true,
+	models.ContaCarteiraDigital: true,
+}
+
+// entradaConta são os campos aceitos na criação e edição de contas
+type entradaConta struct {
+	Nome         string          `json:"nome" binding:"required,max=50"`
+	Tipo         string          `json:"tipo" binding:"required"`
+	SaldoInicial models.Dinheiro `json:"saldo_inicial"`
+}
+
+// validarEntradaConta lê e valida o JSON de uma conta, respondendo em caso de erro
+func validarEntradaConta(c *gin.Context) (entradaConta, bool) {
+	var input entradaConta
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
+		return input, false
+	}
+	if !tiposConta[input.Tipo] {
+		erros.Responder(c, erros.NoCampo("tipo", erros.CampoOpcao, []string{
+			models.ContaCorrente, models.ContaPoupanca, models.ContaDinheiro, models.ContaCarteiraDigital,
+		}))
+		return input, false
+	}
+	return input, true
+}
+
+// validarContaDoLancamento responde com erro se a conta informada no campo de uma renda, gasto ou
+// transferência não for do usuário
+func (h *Handler) validarContaDoLancamento(c *gin.Context, usuarioID int, campo string, contaID *int) bool {
+	if contaID == nil {
+		return true
+	}
+	disponivel, err := h.contas.Pertence(c.Request.Context(), usuarioID, *contaID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao verificar a conta")
+		return false
+	}
+	if !disponivel {
+		erros.Responder(c, erros.NoCampo(campo, erros.CampoNaoEncontrado))
+		return false
+	}
+	return true
+}
+
+// saldoConta é a conta com o saldo atual e a composição dele: entradas são as rendas
+// recebidas na conta e saídas, os gastos fixos e variáveis pagos por ela
+type saldoConta struct {
+	models.Conta
+	Entradas                models.Dinheiro `json:"entradas"`
+	Saidas                  models.Dinheiro `json:"saidas"`
+	TransferenciasRecebidas models.Dinheiro `json:"transferencias_recebidas"`
+	TransferenciasEnviadas  models.Dinheiro `json:"transferencias_enviadas"`
+	SaldoAtual              models.Dinheiro `json:"saldo_atual"`
+}
+
+// recorrentesPorConta soma, por conta, os recebimentos de rendas e as cobranças
+// de gastos fixos desde o primeiro lançamento até hoje
+func (h *Handler) recorrentesPorConta(ctx context.Context, usuarioID int) (entradas, fixos map[int]models.Dinheiro, err error) {
+	inicio, err := h.contas.InicioRecorrentes(ctx, usuarioID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hoje := time.Now().UTC()
+	p := periodo{Inicio: inicio, Fim: time.Date(hoje.Year(), hoje.Month(), hoje.Day()+1, 0, 0, 0, 0, time.UTC)}
+
+	rendas, err := h.materializarRendas(ctx, usuarioID, p)
+	if err != nil {
+		return nil, nil, err
+	}
+	entradas = map[int]models.Dinheiro{}
+	for _, r := range rendas {
+		if r.ContaID != nil {
+			entradas[*r.ContaID] += r.Valor
+		}
+	}
+
+	gastos, err := h.materializarGastosFixos(ctx, usuarioID, p)
+	if err != nil {
+		return nil, nil, err
+	}
+	fixos = map[int]models.Dinheiro{}
+	for _, g := range gastos {
+		if g.ContaID != nil {
+			fixos[*g.ContaID] += g.Valor
+		}
+	}
+	return entradas, fixos, nil
+}
+
+// calcularSaldosContas retorna as contas do usuário com o saldo atual de cada uma.
+// O saldo considera o saldo inicial, as rendas, os gastos e as transferências até hoje:
+// gastos variáveis pela data (ou pelo vencimento da fatura, se no cartão), rendas e
+// gastos fixos em cada recebimento ou cobrança da recorrência.
+func (h *Handler) calcularSaldosContas(ctx context.Context, usuarioID int) ([]saldoConta, error) {
+	entradas, fixos, err := h.recorrentesPorConta(ctx, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	movimentos, err := h.contas.ComMovimentos(ctx, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	contas := make([]saldoConta, 0, len(movimentos))
+	for _, m := range movimentos {
+		s := saldoConta{
+			Conta:                   m.Conta,
+			Entradas:                entradas[m.ID],
+			Saidas:                  m.GastosVariaveis + fixos[m.ID],
+			TransferenciasRecebidas: m.TransferenciasRecebidas,
+			TransferenciasEnviadas:  m.TransferenciasEnviadas,
+		}
+		s.SaldoAtual = s.SaldoInicial + s.Entradas - s.Saidas + s.TransferenciasRecebidas - s.TransferenciasEnviadas
+		contas = append(contas, s)
+	}
+	return contas, nil
+}
+
+// ListarContas lista as contas do usuário com o saldo atual de cada uma e o saldo total
+func (h *Handler) ListarContas(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	contas, err := h.calcularSaldosContas(c.Request.Context(), usuarioID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar contas")
+		return
+	}
+
+	var saldoTotal models.Dinheiro
+	for _, conta := range contas {
+		saldoTotal += conta.SaldoAtual
+	}
+
+	c.JSON(http.StatusOK, gin.H{"contas": contas, "saldo_total": saldoTotal})
+}
+
+// CriarConta cadastra uma conta do usuário
+func (h *Handler) CriarConta(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	input, ok := validarEntradaConta(c)
+	if !ok {
+		return
+	}
+
+	// Insere a conta no banco de dados
+	id, err := h.contas.Criar(c.Request.Context(), usuarioID, repositorio.DadosConta(input))
+	if errors.Is(err, repositorio.ErrDuplicado) {
+		erros.Responder(c, erros.Novo(erros.ContaDuplicada))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao criar conta")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgContaCriada), "id": id})
+}
+
+// EditarConta atualiza nome, tipo e saldo inicial de uma conta do usuário
+func (h *Handler) EditarConta(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	contaID, ok := idDaURL(c, erros.ContaNaoEncontrada)
+	if !ok {
+		return
+	}
+
+	input, ok := validarEntradaConta(c)
+	if !ok {
+		return
+	}
+
+	// Atualiza a conta no banco de dados
+	err := h.contas.Atualizar(c.Request.Context(), usuarioID, contaID, repositorio.DadosConta(input))
+	if errors.Is(err, repositorio.ErrDuplicado) {
+		erros.Responder(c, erros.Novo(erros.ContaDuplicada))
+		return
+	}
+	if errors.Is(err, repositorio.ErrNaoEncontrado) {
+		erros.Responder(c, erros.Novo(erros.ContaNaoEncontrada))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao atualizar a conta")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgContaAtualizada)})
+}
+
+// RemoverConta remove uma conta do usuário; rendas e gastos dela ficam sem conta.
+// Contas com transferências não podem ser removidas (remova as transferências antes).
+func (h *Handler) RemoverConta(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	contaID, ok := idDaURL(c, erros.ContaNaoEncontrada)
+	if !ok {
+		return
+	}
+
+	err := h.contas.Remover(c.Request.Context(), usuarioID, contaID)
+	if errors.Is(err, repositorio.ErrEmUso) {
+		erros.Responder(c, erros.Novo(erros.ContaComTransferencias))
+		return
+	}
+	if errors.Is(err, repositorio.ErrNaoEncontrado) {
+		erros.Responder(c, erros.Novo(erros.ContaNaoEncontrada))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao remover a conta")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgContaRemovida)})
+}
+
+// AdicionarTransferencia registra uma transferência entre duas contas do usuário.
+// Transferências não entram no resumo: apenas mudam o saldo das contas.
+func (h *Handler) AdicionarTransferencia(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	var input struct {
+		ContaOrigemID  int             `json:"conta_origem_id" binding:"required"`
+		ContaDestinoID int             `json:"conta_destino_id" binding:"required"`
+		Valor          models.Dinheiro `json:"valor" binding:"required"`
+		Data           string          `json:"data" binding:"required"`
+		Descricao      string          `json:"descricao" binding:"max=200"`
+	}
+
+	// Valida o JSON recebido
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
+		return
+	}
+
+	// Verifica se o valor é positivo
+	if input.Valor <= 0 {
+		erros.Responder(c, erros.NoCampo("valor", erros.CampoMaiorQueZero))
+		return
+	}
+
+	// Valida o formato da data
+	data, err := time.Parse(formatoData, input.Data)
+	if err != nil {
+		erros.Responder(c, erros.NoCampo("data", erros.CampoFormatoData))
+		return
+	}
+
+	if input.ContaOrigemID == input.ContaDestinoID {
+		erros.Responder(c, erros.NoCampo("conta_destino_id", erros.CampoDiferenteDe, "conta_origem_id"))
+		return
+	}
+
+	// Verifica se as duas contas pertencem ao usuário
+	if !h.validarContaDoLancamento(c, usuarioID, "conta_origem_id", &input.ContaOrigemID) ||
+		!h.validarContaDoLancamento(c, usuarioID, "conta_destino_id", &input.ContaDestinoID) {
+		return
+	}
+
+	// Insere a transferência no banco de dados
+	id, err := h.transferencias.Criar(c.Request.Context(), usuarioID, repositorio.DadosTransferencia{
+		ContaOrigemID:  input.ContaOrigemID,
+		ContaDestinoID: input.ContaDestinoID,
+		Valor:          input.Valor,
+		Data:           data,
+		Descricao:      input.Descricao,
+	})
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao adicionar transferência")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgTransferenciaAdicionada), "id": id})
+}
+
+// listagemTransferencias descreve a listagem paginada de transferências
+var listagemTransferencias = configuracaoListagem{
+	ordenacoes:      repositorio.OrdenacoesTransferencias,
+	ordenacaoPadrao: "data",
+}
+
+// ListarTransferencias lista as transferências do usuário com paginação por cursor
+func (h *Handler) ListarTransferencias(c *gin.Context) {
+	listar(c, listagemTransferencias, h.transferencias.Listar)
+}
+
+// RemoverTransferencia remove uma transferência do usuário
+func (h *Handler) RemoverTransferencia(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	transferenciaID, ok := idDaURL(c, erros.TransferenciaNaoEncontrada)
+	if !ok {
+		return
+	}
+
+	err := h.transferencias.Remover(c.Request.Context(), usuarioID, transferenciaID)
+	if errors.Is(err, repositorio.ErrNaoEncontrado) {
+		erros.Responder(c, erros.Novo(erros.TransferenciaNaoEncontrada))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao remover a transferência")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgTransferenciaRemovida)})
+}
@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+)
+
+// responderErroInterno responde a uma falha ao atender a requisição. O contexto (ex.: "Erro ao
+// buscar metas") vai para o log com a causa; o cliente recebe erro_interno, ou tempo_esgotado
+// (504) e servidor_ocupado (503) quando puder tentar de novo. Erros da API passam sem alteração.
+func responderErroInterno(c *gin.Context, err error, contexto string) {
+	erros.Responder(c, erros.Interno(err, contexto))
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/idioma"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+// Quantidade máxima de linhas com erro listadas no relatório da importação
+const maximoErrosImportacao = 500
+
+// colunasCSV são as posições das colunas do perfil no arquivo; identificador é -1 se ausente
+type colunasCSV struct {
+	data, descricao, valor, identificador int
+}
+
+// indiceColunaCSV encontra a coluna pelo nome no cabeçalho (sem diferenciar maiúsculas)
+// ou, se não houver coluna com esse nome, pela posição a partir de 1
+func indiceColunaCSV(coluna string, cabecalho []string) (int, error) {
+	for i, nome := range cabecalho {
+		if strings.EqualFold(strings.TrimSpace(nome), strings.TrimSpace(coluna)) {
+			return i, nil
+		}
+	}
+	if posicao, err := strconv.Atoi(coluna); err == nil && posicao >= 1 {
+		return posicao - 1, nil
+	}
+	return 0, erros.Novo(erros.ColunaNaoEncontrada, coluna)
+}
+
+// localizarColunasCSV resolve as colunas do perfil a partir do cabeçalho (nil se o arquivo não tiver)
+func localizarColunasCSV(perfil models.PerfilImportacao, cabecalho []string) (colunasCSV, error) {
+	col := colunasCSV{identificador: -1}
+	var err error
+	if col.data, err = indiceColunaCSV(perfil.ColunaData, cabecalho); err != nil {
+		return col, err
+	}
+	if col.descricao, err = indiceColunaCSV(perfil.ColunaDescricao, cabecalho); err != nil {
+		return col, err
+	}
+	if col.valor, err = indiceColunaCSV(perfil.ColunaValor, cabecalho); err != nil {
+		return col, err
+	}
+	if perfil.ColunaIdentificador != nil {
+		if col.identificador, err = indiceColunaCSV(*perfil.ColunaIdentificador, cabecalho); err != nil {
+			return col, err
+		}
+	}
+	return col, nil
+}
+
+// lerValorCSV interpreta o valor com o separador decimal do perfil; o outro separador é tratado como de milhar
+func lerValorCSV(texto, separadorDecimal string) (models.Dinheiro, error) {
+	texto = strings.ReplaceAll(strings.TrimSpace(texto), " ", "")
+	if separadorDecimal == "," {
+		texto = strings.ReplaceAll(texto, ".", "")
+		texto = strings.Replace(texto, ",", ".", 1)
+	} else {
+		texto = strings.ReplaceAll(texto, ",", "")
+	}
+	return models.ParseDinheiro(texto)
+}
+
+// lerDataCSV interpreta a data no formato do perfil, ignorando um horário após a data
+func lerDataCSV(texto, formato string) (time.Time, error) {
+	campos := strings.Fields(texto)
+	if len(campos) == 0 {
+		return time.Time{}, erros.NoCampo("data", erros.CampoObrigatorio)
+	}
+	data, err := time.Parse(formatosDataImportacao[formato], campos[0])
+	if err != nil {
+		return time.Time{}, erros.NoCampo("data", erros.CampoFormato, formato)
+	}
+	return data, nil
+}
+
+// idExternoCSV identifica a linha para evitar duplicidade em uma nova importação. Sem coluna de
+// identificador, usa data, valor e descrição mais a ordem de linhas iguais no arquivo, para que
+// duas compras idênticas no mesmo dia não sejam tratadas como duplicadas.
+func idExternoCSV(perfil models.PerfilImportacao, identificador string, l models.LancamentoImportado, repeticao int) string {
+	if identificador != "" {
+		return fmt.Sprintf("csv:%d:%s", perfil.ID, identificador)
+	}
+	soma := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%s|%d", l.Tipo, l.Data, l.Valor, l.Nome, repeticao)))
+	return fmt.Sprintf("csv:%d:%s", perfil.ID, hex.EncodeToString(soma[:12]))
+}
+
+// lerCSV lê um extrato CSV com o mapeamento do perfil. Cada linha passa pelas mesmas regras de um
+// gasto variável (validarGastoVariavel); as rejeitadas vão para o relatório de erros com o número
+// da linha no arquivo e a mensagem no idioma i. Só retorna erro se o arquivo não puder ser lido
+// como um todo.
+func lerCSV(conteudo []byte, perfil models.PerfilImportacao, i idioma.Idioma) ([]models.LancamentoImportado, []models.ErroImportacao, error) {
+	lancamentos := []models.LancamentoImportado{}
+	errosLinhas := []models.ErroImportacao{}
+
+	texto := converterLatin1(bytes.TrimPrefix(conteudo, []byte(bomUTF8)))
+
+	// Descarta as linhas anteriores ao cabeçalho (título, período, saldo anterior), inclusive as em branco
+	for n := 0; n < perfil.LinhasIgnoradas; n++ {
+		fim := strings.IndexByte(texto, '\n')
+		if fim < 0 {
+			return lancamentos, errosLinhas, nil
+		}
+		texto = texto[fim+1:]
+	}
+
+	r := csv.NewReader(strings.NewReader(texto))
+	r.Comma, _ = utf8.DecodeRuneInString(perfil.Delimitador)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	var cabecalho []string
+	if perfil.TemCabecalho {
+		registro, err := r.Read()
+		if err == io.EOF {
+			return lancamentos, errosLinhas, nil
+		}
+		if err != nil {
+			return nil, nil, erros.Novo(erros.CSVInvalido).ComCausa(err)
+		}
+		cabecalho = registro
+	}
+	col, err := localizarColunasCSV(perfil, cabecalho)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repeticoes := map[string]int{}
+	for {
+		registro, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var errCSV *csv.ParseError
+			if !errors.As(err, &errCSV) {
+				return nil, nil, erros.Novo(erros.CSVInvalido).ComCausa(err)
+			}
+			errosLinhas = append(errosLinhas, models.ErroImportacao{
+				Linha: perfil.LinhasIgnoradas + errCSV.Line, Erro: erros.Novo(erros.LinhaMalFormatada).Mensagem(i),
+			})
+		} else if l, identificador, err := lerLinhaCSV(registro, perfil, col); err != nil {
+			linha, _ := r.FieldPos(0)
+			errosLinhas = append(errosLinhas, models.ErroImportacao{Linha: perfil.LinhasIgnoradas + linha, Erro: mensagemErroLinha(err, i)})
+		} else if l != nil {
+			chave := fmt.Sprintf("%s|%s|%s|%s", l.Tipo, l.Data, l.Valor, l.Nome)
+			l.IDExterno = idExternoCSV(perfil, identificador, *l, repeticoes[chave])
+			repeticoes[chave]++
+			lancamentos = append(lancamentos, *l)
+		}
+
+		if len(errosLinhas) > maximoErrosImportacao {
+			return nil, nil, erros.Novo(erros.LinhasInvalidas, maximoErrosImportacao)
+		}
+	}
+	return lancamentos, errosLinhas, nil
+}
+
+// mensagemErroLinha é o motivo da rejeição de uma linha no idioma do relatório
+func mensagemErroLinha(err error, i idioma.Idioma) string {
+	var e *erros.Erro
+	if errors.As(err, &e) {
+		return e.Mensagem(i)
+	}
+	return err.Error()
+}
+
+// lerLinhaCSV converte uma linha em lançamento; linhas em branco retornam nil sem erro
+func lerLinhaCSV(registro []string, perfil models.PerfilImportacao, col colunasCSV) (*models.LancamentoImportado, string, error) {
+	vazia := true
+	for _, campo := range registro {
+		if strings.TrimSpace(campo) != "" {
+			vazia = false
+			break
+		}
+	}
+	if vazia {
+		return nil, "", nil
+	}
+
+	campo := func(i int) string {
+		if i < 0 || i >= len(registro) {
+			return ""
+		}
+		return strings.TrimSpace(registro[i])
+	}
+
+	data, err := lerDataCSV(campo(col.data), perfil.FormatoData)
+	if err != nil {
+		return nil, "", err
+	}
+	valor, err := lerValorCSV(campo(col.valor), perfil.SeparadorDecimal)
+	if err != nil {
+		return nil, "", erros.NoCampo("valor", codigoErroValor(err))
+	}
+
+	l := models.LancamentoImportado{Tipo: models.LancamentoGastoVariavel, Data: data.Format(formatoData), Nome: campo(col.descricao)}
+	debito := valor < 0
+	if perfil.ConvencaoSinal == models.SinalDebitoPositivo {
+		debito = valor > 0
+	}
+	if !debito {
+		l.Tipo = models.LancamentoRenda
+	}
+	l.Valor = valor
+	if l.Valor < 0 {
+		l.Valor = -l.Valor
+	}
+	if utf8.RuneCountInString(l.Nome) > tamanhoMaximoNomeImportado {
+		l.Nome = string([]rune(l.Nome)[:tamanhoMaximoNomeImportado])
+	}
+
+	if err := validarGastoVariavel(l.Nome, l.Valor, l.Data); err != nil {
+		return nil, "", err
+	}
+	return &l, campo(col.identificador), nil
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/idioma"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+func TestLerValorCSV(t *testing.T) {
+	casos := []struct {
+		texto     string
+		separador string
+		valor     models.Dinheiro
+		codigo    erros.CodigoCampo // vazio se válido
+	}{
+		{"-1.234,56", ",", -123456, ""},
+		{"1.234,56", ",", 123456, ""},
+		{"1 234,56", ",", 123456, ""},
+		{"0,5", ",", 50, ""},
+		{"-1,234.56", ".", -123456, ""},
+		{"1234.56", ".", 123456, ""},
+		{" R$ 10,00 ", ",", 1000, ""},
+		{"1.234", ",", 123400, ""}, // com vírgula decimal, o ponto é de milhar
+		{"1,234", ".", 123400, ""}, // com ponto decimal, a vírgula é de milhar
+		{"", ",", 0, erros.CampoNumero},
+		{"abc", ",", 0, erros.CampoNumero},
+		{"1/2", ",", 0, erros.CampoNumero},
+		{"10,005", ",", 0, erros.CampoCasasDecimais},
+	}
+	for _, caso := range casos {
+		valor, err := lerValorCSV(caso.texto, caso.separador)
+		if caso.codigo == "" {
+			if err != nil {
+				t.Errorf("lerValorCSV(%q, %q): erro inesperado: %v", caso.texto, caso.separador, err)
+			} else if valor != caso.valor {
+				t.Errorf("lerValorCSV(%q, %q) = %d, esperado %d", caso.texto, caso.separador, valor, caso.valor)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("lerValorCSV(%q, %q): esperado erro", caso.texto, caso.separador)
+		} else if codigo := codigoErroValor(err); codigo != caso.codigo {
+			t.Errorf("lerValorCSV(%q, %q): erro %s, esperado %s", caso.texto, caso.separador, codigo, caso.codigo)
+		}
+	}
+}
+
+func perfilTeste() models.PerfilImportacao {
+	return models.PerfilImportacao{
+		ID:               7,
+		Delimitador:      ";",
+		TemCabecalho:     true,
+		ColunaData:       "Data",
+		FormatoData:      "DD/MM/AAAA",
+		ColunaDescricao:  "Descrição",
+		ColunaValor:      "Valor",
+		SeparadorDecimal: ",",
+		ConvencaoSinal:   models.SinalDebitoNegativo,
+	}
+}
+
+func TestLerCSV(t *testing.T) {
+	mensagem := func(err *erros.Erro) string { return err.Mensagem(idioma.PortuguesBR) }
+	casos := []struct {
+		nome        string
+		perfil      func(*models.PerfilImportacao)
+		conteudo    []byte
+		lancamentos []models.LancamentoImportado // sem IDExterno
+		erros       []models.ErroImportacao
+	}{
+		{
+			nome:     "débitos negativos com milhar e vírgula decimal",
+			conteudo: []byte("Data;Descrição;Valor\n10/03/2025;PADARIA;-1.234,56\n11/03/2025;SALARIO;5.000,00\n"),
+			lancamentos: []models.LancamentoImportado{
+				{Tipo: models.LancamentoGastoVariavel, Data: "2025-03-10", Nome: "PADARIA", Valor: 123456},
+				{Tipo: models.LancamentoRenda, Data: "2025-03-11", Nome: "SALARIO", Valor: 500000},
+			},
+			erros: []models.ErroImportacao{},
+		},
+		{
+			nome:     "débitos positivos, como nas faturas",
+			perfil:   func(p *models.PerfilImportacao) { p.ConvencaoSinal = models.SinalDebitoPositivo },
+			conteudo: []byte("Data;Descrição;Valor\n10/03/2025;LOJA;89,90\n12/03/2025;ESTORNO;-10,00\n"),
+			lancamentos: []models.LancamentoImportado{
+				{Tipo: models.LancamentoGastoVariavel, Data: "2025-03-10", Nome: "LOJA", Valor: 8990},
+				{Tipo: models.LancamentoRenda, Data: "2025-03-12", Nome: "ESTORNO", Valor: 1000},
+			},
+			erros: []models.ErroImportacao{},
+		},
+		{
+			nome: "linhas com colunas faltando vão para o relatório",
+			conteudo: []byte("Data;Descrição;Valor\n10/03/2025;PADARIA\n;;\n11/03/2025;MERCADO;-20,00\n" +
+				"12/03/2025\n13/03/2025;;-5,00\n"),
+			lancamentos: []models.LancamentoImportado{
+				{Tipo: models.LancamentoGastoVariavel, Data: "2025-03-11", Nome: "MERCADO", Valor: 2000},
+			},
+			erros: []models.ErroImportacao{
+				{Linha: 2, Erro: mensagem(erros.NoCampo("valor", erros.CampoNumero))},
+				{Linha: 5, Erro: mensagem(erros.NoCampo("valor", erros.CampoNumero))},
+				{Linha: 6, Erro: mensagem(erros.NoCampo("nome", erros.CampoObrigatorio))},
+			},
+		},
+		{
+			nome:     "Latin-1 com linhas ignoradas antes do cabeçalho",
+			perfil:   func(p *models.PerfilImportacao) { p.LinhasIgnoradas = 2 },
+			conteudo: []byte("Extrato de mar\xe7o\n\nData;Descri\xe7\xe3o;Valor\n10/03/2025;A\xc7OUGUE S\xc3O JO\xc3O;-8,50\n"),
+			lancamentos: []models.LancamentoImportado{
+				{Tipo: models.LancamentoGastoVariavel, Data: "2025-03-10", Nome: "AÇOUGUE SÃO JOÃO", Valor: 850},
+			},
+			erros: []models.ErroImportacao{},
+		},
+		{
+			nome: "sem cabeçalho, colunas pela posição",
+			perfil: func(p *models.PerfilImportacao) {
+				p.TemCabecalho, p.ColunaData, p.ColunaDescricao, p.ColunaValor = false, "1", "2", "3"
+			},
+			conteudo: []byte(bomUTF8 + "10/03/2025;PADARIA;-1,00\n10/03/2025;data ruim;-1,00;extra\n31/02/2025;X;-1,00\n"),
+			lancamentos: []models.LancamentoImportado{
+				{Tipo: models.LancamentoGastoVariavel, Data: "2025-03-10", Nome: "PADARIA", Valor: 100},
+				{Tipo: models.LancamentoGastoVariavel, Data: "2025-03-10", Nome: "data ruim", Valor: 100},
+			},
+			erros: []models.ErroImportacao{
+				{Linha: 3, Erro: mensagem(erros.NoCampo("data", erros.CampoFormato, "DD/MM/AAAA"))},
+			},
+		},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			perfil := perfilTeste()
+			if caso.perfil != nil {
+				caso.perfil(&perfil)
+			}
+			lancamentos, errosLinhas, err := lerCSV(caso.conteudo, perfil, idioma.PortuguesBR)
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			for i := range lancamentos {
+				if lancamentos[i].IDExterno == "" {
+					t.Errorf("lançamento %d sem identificador externo", i)
+				}
+				lancamentos[i].IDExterno = ""
+			}
+			if !reflect.DeepEqual(lancamentos, caso.lancamentos) {
+				t.Errorf("lançamentos = %+v, esperado %+v", lancamentos, caso.lancamentos)
+			}
+			if !reflect.DeepEqual(errosLinhas, caso.erros) {
+				t.Errorf("erros = %+v, esperado %+v", errosLinhas, caso.erros)
+			}
+		})
+	}
+}
+
+func TestLerCSVLinhasIguaisNaoSaoDuplicadas(t *testing.T) {
+	conteudo := []byte("Data;Descrição;Valor\n10/03/2025;CAFE;-5,00\n10/03/2025;CAFE;-5,00\n")
+	lancamentos, _, err := lerCSV(conteudo, perfilTeste(), idioma.PortuguesBR)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if len(lancamentos) != 2 || lancamentos[0].IDExterno == lancamentos[1].IDExterno {
+		t.Errorf("esperados dois lançamentos com identificadores diferentes, recebido %+v", lancamentos)
+	}
+}
+
+func TestLerCSVColunaInexistente(t *testing.T) {
+	perfil := perfilTeste()
+	perfil.ColunaValor = "Montante"
+	_, _, err := lerCSV([]byte("Data;Descrição;Valor\n"), perfil, idioma.PortuguesBR)
+	var e *erros.Erro
+	if !errors.As(err, &e) || e.Codigo != erros.ColunaNaoEncontrada {
+		t.Errorf("esperado erro %s, recebido %v", erros.ColunaNaoEncontrada, err)
+	}
+}
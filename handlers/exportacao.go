@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpeccia/quantogasto_app_server/database"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/idioma"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+// Modos de exportação CSV: o internacional segue a RFC 4180 (vírgula e ponto decimal);
+// o pt-BR usa ponto e vírgula, vírgula decimal, datas DD/MM/AAAA e BOM para o Excel abrir em UTF-8;
+// o local segue o idioma da requisição, com cabeçalho e tipos traduzidos e, nos idiomas de
+// vírgula decimal, o mesmo arquivo do pt-BR
+const (
+	modoExportacaoInternacional = "internacional"
+	modoExportacaoPtBR          = "pt-BR"
+	modoExportacaoLocal         = "local"
+)
+
+// Quantidade de linhas escritas entre cada envio parcial da resposta
+const linhasPorEnvioExportacao = 500
+
+// bomUTF8 faz o Excel reconhecer o arquivo como UTF-8
+const bomUTF8 = "\ufeff"
+
+// cabecalhoExportacao são as colunas do CSV exportado
+var cabecalhoExportacao = []string{"tipo", "data", "nome", "categoria", "valor", "conta"}
+
+// textosExportacao traduz as colunas e os tipos de lançamento no modo local
+var textosExportacao = idioma.Catalogo{
+	idioma.PortuguesBR: {
+		"tipo": "Tipo", "data": "Data", "nome": "Nome", "categoria": "Categoria", "valor": "Valor", "conta": "Conta",
+		"renda": "Renda", "gasto_fixo": "Gasto fixo", "gasto_variavel": "Gasto variável",
+	},
+	idioma.Ingles: {
+		"tipo": "Type", "data": "Date", "nome": "Name", "categoria": "Category", "valor": "Amount", "conta": "Account",
+		"renda": "Income", "gasto_fixo": "Fixed expense", "gasto_variavel": "Variable expense",
+	},
+	idioma.Espanhol: {
+		"tipo": "Tipo", "data": "Fecha", "nome": "Nombre", "categoria": "Categoría", "valor": "Importe", "conta": "Cuenta",
+		"renda": "Ingreso", "gasto_fixo": "Gasto fijo", "gasto_variavel": "Gasto variable",
+	},
+}
+
+// consultaExportacao junta rendas, gastos fixos e gastos variáveis do usuário em uma única
+// sequência ordenada por data. Rendas mensais e gastos fixos entram uma vez por ocorrência, com
+// as regras de materializarRendas e recorrencia.ocorrencias: a renda mensal todo mês no dia da
+// data efetiva, até a data final; o gasto fixo a cada intervalo da frequência a partir do mês de
+// início, no dia da cobrança, até o mês final. O dia passa para o último dia nos meses mais curtos.
+// Sem 'ate' ($3), as ocorrências vão até hoje.
+const consultaExportacao = `
+    WITH limite AS (SELECT COALESCE($3::date, CURRENT_DATE + 1) AS fim)
+    SELECT tipo, data, nome, categoria, valor, conta FROM (
+        SELECT 'renda' AS tipo, r.data_efetiva AS data, COALESCE(NULLIF(r.descricao, ''), r.fonte) AS nome,
+               '' AS categoria, r.valor, COALESCE(ct.nome, '') AS conta, r.id
+        FROM rendas r
+        LEFT JOIN contas ct ON ct.id = r.conta_id
+        WHERE r.usuario_id = $1 AND r.recorrencia = 'unica'
+        UNION ALL
+        SELECT 'renda', o.data, COALESCE(NULLIF(r.descricao, ''), r.fonte), '', r.valor, COALESCE(ct.nome, ''), r.id
+        FROM rendas r
+        CROSS JOIN limite
+        CROSS JOIN LATERAL (
+            SELECT (m::date + LEAST(EXTRACT(DAY FROM r.data_efetiva)::int,
+                                    EXTRACT(DAY FROM m + interval '1 month' - interval '1 day')::int) - 1) AS data
+            FROM generate_series(date_trunc('month', r.data_efetiva)::timestamp,
+                                 LEAST(COALESCE(r.data_fim, limite.fim), limite.fim - 1)::timestamp, interval '1 month') m
+        ) o
+        LEFT JOIN contas ct ON ct.id = r.conta_id
+        WHERE r.usuario_id = $1 AND r.recorrencia = 'mensal'
+          AND (r.data_fim IS NULL OR o.data <= r.data_fim) AND o.data < limite.fim
+        UNION ALL
+        SELECT 'gasto_fixo', o.data, g.nome, COALESCE(cat.nome, ''), g.valor, COALESCE(ct.nome, ''), g.id
+        FROM gastos_fixos g
+        CROSS JOIN limite
+        CROSS JOIN LATERAL (
+            SELECT (m::date + LEAST(COALESCE(g.dia, 1),
+                                    EXTRACT(DAY FROM m + interval '1 month' - interval '1 day')::int) - 1) AS data
+            FROM generate_series(g.inicio::timestamp, LEAST(COALESCE(g.fim, limite.fim), limite.fim - 1)::timestamp,
+                                 make_interval(months => CASE g.frequencia
+                                     WHEN 'bimestral' THEN 2 WHEN 'trimestral' THEN 3 WHEN 'anual' THEN 12 ELSE 1 END)) m
+        ) o
+        LEFT JOIN categorias cat ON cat.id = g.categoria_id
+        LEFT JOIN contas ct ON ct.id = g.conta_id
+        WHERE g.usuario_id = $1 AND o.data < limite.fim
+        UNION ALL
+        SELECT 'gasto_variavel', g.data, g.nome, COALESCE(cat.nome, ''), g.valor, COALESCE(ct.nome, ''), g.id
+        FROM gastos_variaveis g
+        LEFT JOIN categorias cat ON cat.id = g.categoria_id
+        LEFT JOIN contas ct ON ct.id = g.conta_id
+        WHERE g.usuario_id = $1
+    ) lancamentos
+    WHERE ($2::date IS NULL OR data >= $2::date) AND ($3::date IS NULL OR data < $3::date)
+    ORDER BY data, tipo, id
+`
+
+// protegerCelula evita que o texto seja interpretado como fórmula pelas planilhas
+func protegerCelula(texto string) string {
+	if texto != "" && strings.ContainsRune("=+-@\t\r", rune(texto[0])) {
+		return "'" + texto
+	}
+	return texto
+}
+
+// ExportarDados exporta rendas, gastos fixos e gastos variáveis do usuário em CSV
+// (?formato=csv, ?modo=pt-BR|local, ?de=&ate= opcionais), com uma linha por ocorrência das
+// rendas mensais e dos gastos fixos. As linhas são enviadas conforme são lidas do banco, sem
+// carregar tudo em memória.
+func ExportarDados(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	if formato := c.DefaultQuery("formato", "csv"); formato != "csv" {
+		erros.Responder(c, erros.NoCampo("formato", erros.CampoOpcao, []string{"csv"}))
+		return
+	}
+
+	modo := c.DefaultQuery("modo", modoExportacaoInternacional)
+	modos := []string{modoExportacaoInternacional, modoExportacaoPtBR, modoExportacaoLocal}
+	if !slices.Contains(modos, modo) {
+		erros.Responder(c, erros.NoCampo("modo", erros.CampoOpcao, modos))
+		return
+	}
+
+	var de, ate *time.Time
+	if texto := c.Query("de"); texto != "" {
+		t, err := interpretarLimite(texto, false)
+		if err != nil {
+			erros.Responder(c, erros.NoCampo("de", erros.CampoFormatoDataOuMes))
+			return
+		}
+		de = &t
+	}
+	if texto := c.Query("ate"); texto != "" {
+		t, err := interpretarLimite(texto, true)
+		if err != nil {
+			erros.Responder(c, erros.NoCampo("ate", erros.CampoFormatoDataOuMes))
+			return
+		}
+		ate = &t
+	}
+
+	rows, err := database.DB.Query(c.Request.Context(), consultaExportacao, usuarioID, de, ate)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao exportar os dados")
+		return
+	}
+	defer rows.Close()
+
+	nomeArquivo := fmt.Sprintf("quantogasto_%s.csv", time.Now().Format("20060102"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", nomeArquivo))
+	c.Status(http.StatusOK)
+
+	// No modo internacional, datas ISO, ponto decimal e textos sem tradução
+	w := csv.NewWriter(c.Writer)
+	formatarData := func(t time.Time) string { return t.Format(formatoData) }
+	formatarValor := models.Dinheiro.String
+	traduzir := func(texto string) string { return texto }
+	separadorDecimal := "."
+	switch modo {
+	case modoExportacaoPtBR:
+		formatarData, formatarValor = idioma.PortuguesBR.FormatarData, idioma.PortuguesBR.FormatarValor
+		separadorDecimal = idioma.PortuguesBR.SeparadorDecimal()
+	case modoExportacaoLocal:
+		i := idioma.DaRequisicao(c)
+		formatarData, formatarValor = i.FormatarData, i.FormatarValor
+		separadorDecimal = i.SeparadorDecimal()
+		traduzir = func(texto string) string { return textosExportacao.Texto(i, texto) }
+	}
+	// Com vírgula decimal, as colunas são separadas por ponto e vírgula, como o Excel espera
+	if separadorDecimal == "," {
+		c.Writer.WriteString(bomUTF8)
+		w.Comma = ';'
+	}
+
+	cabecalho := make([]string, len(cabecalhoExportacao))
+	for n, coluna := range cabecalhoExportacao {
+		cabecalho[n] = traduzir(coluna)
+	}
+	w.Write(cabecalho)
+	linhas := 0
+	for rows.Next() {
+		var tipo, nome, categoria, conta string
+		var data time.Time
+		var valor models.Dinheiro
+		if err := rows.Scan(&tipo, &data, &nome, &categoria, &valor, &conta); err != nil {
+			// A resposta já começou; só resta interromper o arquivo
+			log.Printf("Erro ao ler dados da exportação: %v", err)
+			break
+		}
+
+		w.Write([]string{
+			traduzir(tipo), formatarData(data), protegerCelula(nome), protegerCelula(categoria), formatarValor(valor), protegerCelula(conta),
+		})
+
+		linhas++
+		if linhas%linhasPorEnvioExportacao == 0 {
+			w.Flush()
+			c.Writer.Flush()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Erro ao exportar dados: %v", err)
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Printf("Erro ao escrever exportação: %v", err)
+	}
+}
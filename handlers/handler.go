@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/repositorio"
+)
+
+// Handler reúne os repositórios usados pelos handlers de usuários, rendas, gastos e contas e pelos
+// cálculos feitos a partir deles. Recebê-los no construtor, em vez de usar o database.DB
+// global, permite testar esses handlers com a implementação em memória.
+type Handler struct {
+	usuarios        repositorio.Usuarios
+	rendas          repositorio.Rendas
+	gastosFixos     repositorio.GastosFixos
+	gastosVariaveis repositorio.GastosVariaveis
+	categorias      repositorio.Categorias
+	contas          repositorio.Contas
+	transferencias  repositorio.Transferencias
+	cartoes         repositorio.Cartoes
+	metas           repositorio.Metas
+
+	// novosTokens emite os tokens do cadastro; os testes o substituem para não gravar no banco
+	novosTokens func(ctx context.Context, usuarioID int) (gin.H, error)
+}
+
+// NovoHandler cria os handlers com os repositórios informados
+func NovoHandler(r repositorio.Repositorios) *Handler {
+	return &Handler{
+		usuarios:        r.Usuarios,
+		rendas:          r.Rendas,
+		gastosFixos:     r.GastosFixos,
+		gastosVariaveis: r.GastosVariaveis,
+		categorias:      r.Categorias,
+		contas:          r.Contas,
+		transferencias:  r.Transferencias,
+		cartoes:         r.Cartoes,
+		metas:           r.Metas,
+		novosTokens:     emitirTokensNovaFamilia,
+	}
+}
+
+// idDaURL lê o parâmetro :id da URL; um ID inválido é tratado como registro não encontrado
+func idDaURL(c *gin.Context, naoEncontrado erros.Codigo) (int, bool) {
+	return idDoParametro(c, "id", naoEncontrado)
+}
+
+// idDoParametro lê um ID do parâmetro da URL informado, como idDaURL
+func idDoParametro(c *gin.Context, parametro string, naoEncontrado erros.Codigo) (int, bool) {
+	id, err := strconv.Atoi(c.Param(parametro))
+	if err != nil {
+		erros.Responder(c, erros.Novo(naoEncontrado))
+		return 0, false
+	}
+	return id, true
+}
@@ -1,471 +1,693 @@
 package handlers
 
 import (
-	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jpeccia/quantogasto_app_server/auth"
-	"github.com/jpeccia/quantogasto_app_server/database"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/idioma"
 	"github.com/jpeccia/quantogasto_app_server/models"
+	"github.com/jpeccia/quantogasto_app_server/repositorio"
 )
 
-// AdicionarRenda adiciona a renda mensal do usuário
-func AdicionarRenda(c *gin.Context) {
-	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto (middleware de autenticação)
+// AdicionarGastoFixo adiciona um gasto fixo do usuário
+func (h *Handler) AdicionarGastoFixo(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
 
 	var input struct {
-		Valor float64 `json:"valor" binding:"required"`
+		Nome        string          `json:"nome" binding:"required"`
+		Valor       models.Dinheiro `json:"valor" binding:"required"`
+		CategoriaID *int            `json:"categoria_id"`
+		ContaID     *int            `json:"conta_id"`
+		entradaRecorrencia
 	}
+
 	// Valida o JSON recebido
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "O campo 'valor' é obrigatório e deve ser um número válido"})
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
 		return
 	}
 
 	// Verifica se o valor é positivo
 	if input.Valor <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "O valor deve ser maior que zero"})
+		erros.Responder(c, erros.NoCampo("valor", erros.CampoMaiorQueZero))
 		return
 	}
 
-	// Insere a renda no banco de dados
-	query := `
-        INSERT INTO rendas (usuario_id, valor)
-        VALUES ($1, $2)
-    `
-	_, err := database.DB.Exec(context.Background(), query, usuarioID, input.Valor)
+	// Valida início, fim e frequência da recorrência
+	inicio, fim, frequencia, err := input.interpretar()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao adicionar renda"})
+		erros.Responder(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Renda adicionada com sucesso!"})
-}
-
-// AdicionarGastoFixo adiciona um gasto fixo do usuário
-func AdicionarGastoFixo(c *gin.Context) {
-	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
-
-	var input struct {
-		Nome  string  `json:"nome" binding:"required"`
-		Valor float64 `json:"valor" binding:"required"`
-	}
-
-	// Valida o JSON recebido
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Os campos 'nome' e 'valor' são obrigatórios"})
+	// Verifica se a categoria pode ser usada pelo usuário
+	if !h.validarCategoriaDoGasto(c, usuarioID, input.CategoriaID) {
 		return
 	}
 
-	// Verifica se o valor é positivo
-	if input.Valor <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "O valor deve ser maior que zero"})
+	// Verifica se a conta pertence ao usuário
+	if !h.validarContaDoLancamento(c, usuarioID, "conta_id", input.ContaID) {
 		return
 	}
 
 	// Insere o gasto fixo no banco de dados
-	query := `
-        INSERT INTO gastos_fixos (usuario_id, nome, valor)
-        VALUES ($1, $2, $3)
-    `
-	_, err := database.DB.Exec(context.Background(), query, usuarioID, input.Nome, input.Valor)
+	_, err = h.gastosFixos.Criar(c.Request.Context(), usuarioID, repositorio.DadosGastoFixo{
+		Nome: input.Nome, Valor: input.Valor, CategoriaID: input.CategoriaID, ContaID: input.ContaID,
+		Inicio: inicio, Fim: fim, Frequencia: frequencia, Dia: input.Dia,
+	})
+	if errors.Is(err, repositorio.ErrIntervaloInvalido) {
+		erros.Responder(c, erros.NoCampo("fim", erros.CampoNaoAnteriorA, "inicio"))
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao adicionar gasto fixo"})
+		responderErroInterno(c, err, "Erro ao adicionar gasto fixo")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Gasto fixo adicionado com sucesso!"})
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgGastoFixoAdicionado)})
+}
+
+// validarGastoVariavel aplica as regras de um gasto variável: nome preenchido, valor positivo
+// e data no formato YYYY-MM-DD. Também é usada na importação de extratos CSV.
+func validarGastoVariavel(nome string, valor models.Dinheiro, data string) error {
+	if strings.TrimSpace(nome) == "" {
+		return erros.NoCampo("nome", erros.CampoObrigatorio)
+	}
+	if valor <= 0 {
+		return erros.NoCampo("valor", erros.CampoMaiorQueZero)
+	}
+	if _, err := time.Parse(formatoData, data); err != nil {
+		return erros.NoCampo("data", erros.CampoFormatoData)
+	}
+	return nil
 }
 
 // AdicionarGastoVariavel adiciona um gasto variável do usuário
-func AdicionarGastoVariavel(c *gin.Context) {
+func (h *Handler) AdicionarGastoVariavel(c *gin.Context) {
 	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
 
 	var input struct {
-		Nome  string  `json:"nome" binding:"required"`
-		Valor float64 `json:"valor" binding:"required"`
-		Data  string  `json:"data" binding:"required"`
+		Nome        string          `json:"nome" binding:"required"`
+		Valor       models.Dinheiro `json:"valor" binding:"required"`
+		Data        string          `json:"data" binding:"required"`
+		CategoriaID *int            `json:"categoria_id"`
+		CartaoID    *int            `json:"cartao_id"`
+		ContaID     *int            `json:"conta_id"`
 	}
 
 	// Valida o JSON recebido
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Os campos 'nome', 'valor' e 'data' são obrigatórios"})
-		return
-	}
-
-	// Verifica se o valor é positivo
-	if input.Valor <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "O valor deve ser maior que zero"})
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
 		return
 	}
 
-	// Valida a data no formato esperado (YYYY-MM-DD)
-	if _, err := time.Parse("2006-01-02", input.Data); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "A data deve estar no formato YYYY-MM-DD"})
+	// Valida nome, valor e data do gasto
+	if err := validarGastoVariavel(input.Nome, input.Valor, input.Data); err != nil {
+		erros.Responder(c, err)
 		return
 	}
 
-	// Insere o gasto variável no banco de dados
-	query := `
-        INSERT INTO gastos_variaveis (usuario_id, nome, valor, data)
-        VALUES ($1, $2, $3, $4)
-    `
-	_, err := database.DB.Exec(context.Background(), query, usuarioID, input.Nome, input.Valor, input.Data)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao adicionar gasto variável"})
+	// Verifica se a categoria pode ser usada pelo usuário
+	if !h.validarCategoriaDoGasto(c, usuarioID, input.CategoriaID) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Gasto variável adicionado com sucesso!"})
-}
-
-// ObterResumo retorna um resumo financeiro do usuário
-func ObterResumo(c *gin.Context) {
-	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
-
-	// Obtém a renda total do usuário
-	var rendaTotal float64
-	queryRenda := `SELECT COALESCE(SUM(valor), 0) FROM rendas WHERE usuario_id = $1`
-	err := database.DB.QueryRow(context.Background(), queryRenda, usuarioID).Scan(&rendaTotal)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar renda"})
+	// Verifica se a conta pertence ao usuário
+	if !h.validarContaDoLancamento(c, usuarioID, "conta_id", input.ContaID) {
 		return
 	}
 
-	// Obtém o total de gastos fixos do usuário
-	var gastosFixosTotal float64
-	queryGastosFixos := `SELECT COALESCE(SUM(valor), 0) FROM gastos_fixos WHERE usuario_id = $1`
-	err = database.DB.QueryRow(context.Background(), queryGastosFixos, usuarioID).Scan(&gastosFixosTotal)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar gastos fixos"})
+	// Compras no cartão entram na fatura conforme o dia de fechamento
+	vencimento, ok := h.calcularVencimentoDoGasto(c, usuarioID, input.CartaoID, input.Data)
+	if !ok {
 		return
 	}
 
-	// Obtém o total de gastos variáveis do usuário
-	var gastosVariaveisTotal float64
-	queryGastosVariaveis := `SELECT COALESCE(SUM(valor), 0) FROM gastos_variaveis WHERE usuario_id = $1`
-	err = database.DB.QueryRow(context.Background(), queryGastosVariaveis, usuarioID).Scan(&gastosVariaveisTotal)
+	// Insere o gasto variável no banco de dados
+	data, _ := time.Parse(formatoData, input.Data)
+	_, err := h.gastosVariaveis.Criar(c.Request.Context(), usuarioID, repositorio.DadosGastoVariavel{
+		Nome: input.Nome, Valor: input.Valor, Data: data, CategoriaID: input.CategoriaID,
+		CartaoID: input.CartaoID, VencimentoFatura: vencimento, ContaID: input.ContaID,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar gastos variáveis"})
+		responderErroInterno(c, err, "Erro ao adicionar gasto variável")
 		return
 	}
 
-	// Calcula o saldo disponível
-	saldo := rendaTotal - gastosFixosTotal - gastosVariaveisTotal
-
-	// Retorna o resumo
-	c.JSON(http.StatusOK, gin.H{
-		"renda_total":            rendaTotal,
-		"gastos_fixos_total":     gastosFixosTotal,
-		"gastos_variaveis_total": gastosVariaveisTotal,
-		"saldo_disponivel":       saldo,
-	})
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgGastoVariavelAdicionado)})
 }
 
 // EditarGastoFixo atualiza um gasto fixo do usuário
-func EditarGastoFixo(c *gin.Context) {
+func (h *Handler) EditarGastoFixo(c *gin.Context) {
 	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
-	gastoID := c.Param("id")            // Obtém o ID do gasto da URL
+	gastoID, ok := idDaURL(c, erros.GastoFixoNaoEncontrado)
+	if !ok {
+		return
+	}
 
 	var input struct {
-		Nome  string  `json:"nome" binding:"required"`
-		Valor float64 `json:"valor" binding:"required"`
+		Nome        string          `json:"nome" binding:"required"`
+		Valor       models.Dinheiro `json:"valor" binding:"required"`
+		CategoriaID *int            `json:"categoria_id"`
+		ContaID     *int            `json:"conta_id"`
+		entradaRecorrencia
 	}
 
 	// Valida o JSON recebido
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Os campos 'nome' e 'valor' são obrigatórios"})
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
 		return
 	}
 
 	// Verifica se o valor é positivo
 	if input.Valor <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "O valor deve ser maior que zero"})
+		erros.Responder(c, erros.NoCampo("valor", erros.CampoMaiorQueZero))
 		return
 	}
 
-	// Atualiza o gasto fixo no banco de dados
-	query := `
-        UPDATE gastos_fixos
-        SET nome = $1, valor = $2
-        WHERE id = $3 AND usuario_id = $4
-    `
-	result, err := database.DB.Exec(context.Background(), query, input.Nome, input.Valor, gastoID, usuarioID)
+	// Valida início, fim e frequência da recorrência
+	inicio, fim, frequencia, err := input.interpretar()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar o gasto fixo"})
+		erros.Responder(c, err)
+		return
+	}
+
+	// Verifica se a categoria pode ser usada pelo usuário
+	if !h.validarCategoriaDoGasto(c, usuarioID, input.CategoriaID) {
 		return
 	}
 
+	// Verifica se a conta pertence ao usuário
+	if !h.validarContaDoLancamento(c, usuarioID, "conta_id", input.ContaID) {
+		return
+	}
+
+	// Atualiza o gasto fixo no banco de dados
+	err = h.gastosFixos.Atualizar(c.Request.Context(), usuarioID, gastoID, repositorio.DadosGastoFixo{
+		Nome: input.Nome, Valor: input.Valor, CategoriaID: input.CategoriaID, ContaID: input.ContaID,
+		Inicio: inicio, Fim: fim, Frequencia: frequencia, Dia: input.Dia,
+	})
 	// Verifica se o gasto foi encontrado e atualizado
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Gasto fixo não encontrado ou você não tem permissão para editá-lo"})
+	if errors.Is(err, repositorio.ErrNaoEncontrado) {
+		erros.Responder(c, erros.Novo(erros.GastoFixoNaoEncontrado))
+		return
+	}
+	if errors.Is(err, repositorio.ErrIntervaloInvalido) {
+		erros.Responder(c, erros.NoCampo("fim", erros.CampoNaoAnteriorA, "inicio"))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao atualizar o gasto fixo")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Gasto fixo atualizado com sucesso!"})
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgGastoFixoAtualizado)})
 }
 
-// EditarGastoVariavel atualiza um gasto variável do usuário
-func EditarGastoVariavel(c *gin.Context) {
+// EditarGastoVariavel atualiza um gasto variável do usuário; parcelas de compras parceladas
+// só mudam pela compra
+func (h *Handler) EditarGastoVariavel(c *gin.Context) {
 	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
-	gastoID := c.Param("id")            // Obtém o ID do gasto da URL
+	gastoID, ok := idDaURL(c, erros.GastoVariavelNaoEncontrado)
+	if !ok {
+		return
+	}
 
 	var input struct {
-		Nome  string  `json:"nome" binding:"required"`
-		Valor float64 `json:"valor" binding:"required"`
-		Data  string  `json:"data" binding:"required"`
+		Nome        string          `json:"nome" binding:"required"`
+		Valor       models.Dinheiro `json:"valor" binding:"required"`
+		Data        string          `json:"data" binding:"required"`
+		CategoriaID *int            `json:"categoria_id"`
+		CartaoID    *int            `json:"cartao_id"`
+		ContaID     *int            `json:"conta_id"`
 	}
 
 	// Valida o JSON recebido
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Os campos 'nome', 'valor' e 'data' são obrigatórios"})
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
 		return
 	}
 
-	// Verifica se o valor é positivo
-	if input.Valor <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "O valor deve ser maior que zero"})
+	// Valida nome, valor e data do gasto
+	if err := validarGastoVariavel(input.Nome, input.Valor, input.Data); err != nil {
+		erros.Responder(c, err)
 		return
 	}
 
-	// Valida o formato da data
-	if _, err := time.Parse("2006-01-02", input.Data); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "A data deve estar no formato YYYY-MM-DD"})
+	// Verifica se a categoria pode ser usada pelo usuário
+	if !h.validarCategoriaDoGasto(c, usuarioID, input.CategoriaID) {
 		return
 	}
 
-	// Atualiza o gasto variável no banco de dados
-	query := `
-        UPDATE gastos_variaveis
-        SET nome = $1, valor = $2, data = $3
-        WHERE id = $4 AND usuario_id = $5
-    `
-	result, err := database.DB.Exec(context.Background(), query, input.Nome, input.Valor, input.Data, gastoID, usuarioID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar o gasto variável"})
+	// Verifica se a conta pertence ao usuário
+	if !h.validarContaDoLancamento(c, usuarioID, "conta_id", input.ContaID) {
 		return
 	}
 
+	// Compras no cartão entram na fatura conforme o dia de fechamento
+	vencimento, ok := h.calcularVencimentoDoGasto(c, usuarioID, input.CartaoID, input.Data)
+	if !ok {
+		return
+	}
+
+	// Atualiza o gasto variável no banco de dados
+	data, _ := time.Parse(formatoData, input.Data)
+	err := h.gastosVariaveis.Atualizar(c.Request.Context(), usuarioID, gastoID, repositorio.DadosGastoVariavel{
+		Nome: input.Nome, Valor: input.Valor, Data: data, CategoriaID: input.CategoriaID,
+		CartaoID: input.CartaoID, VencimentoFatura: vencimento, ContaID: input.ContaID,
+	})
 	// Verifica se o gasto foi encontrado e atualizado
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Gasto variavel não encontrado ou você não tem permissão para editá-lo"})
+	if errors.Is(err, repositorio.ErrNaoEncontrado) {
+		erros.Responder(c, erros.Novo(erros.GastoVariavelNaoEncontrado))
+		return
+	}
+	var parcela *repositorio.ErroParcelaDeCompra
+	if errors.As(err, &parcela) {
+		erros.Responder(c, erros.Novo(erros.ParcelaDeCompra, parcela.CompraParceladaID))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao atualizar o gasto variável")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Gasto variável atualizado com sucesso!"})
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgGastoVariavelAtualizado)})
 }
 
 // RemoverGastoFixo remove um gasto fixo do usuário
-func RemoverGastoFixo(c *gin.Context) {
+func (h *Handler) RemoverGastoFixo(c *gin.Context) {
 	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
-	gastoID := c.Param("id")            // Obtém o ID do gasto da URL
-
-	// Remove o gasto fixo do banco de dados
-	query := `
-        DELETE FROM gastos_fixos
-        WHERE id = $1 AND usuario_id = $2
-    `
-	result, err := database.DB.Exec(context.Background(), query, gastoID, usuarioID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover o gasto fixo"})
+	gastoID, ok := idDaURL(c, erros.GastoFixoNaoEncontrado)
+	if !ok {
 		return
 	}
 
+	// Remove o gasto fixo do banco de dados
+	err := h.gastosFixos.Remover(c.Request.Context(), usuarioID, gastoID)
+
 	// Verifica se o gasto foi encontrado e removido
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Gasto fixo não encontrado ou você não tem permissão para removê-lo"})
+	if errors.Is(err, repositorio.ErrNaoEncontrado) {
+		erros.Responder(c, erros.Novo(erros.GastoFixoNaoEncontrado))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao remover o gasto fixo")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Gasto fixo removido com sucesso!"})
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgGastoFixoRemovido)})
 }
 
-// RemoverGastoVariavel remove um gasto variável do usuário
-func RemoverGastoVariavel(c *gin.Context) {
+// RemoverGastoVariavel remove um gasto variável do usuário; parcelas de compras parceladas
+// só saem com o cancelamento da compra
+func (h *Handler) RemoverGastoVariavel(c *gin.Context) {
 	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
-	gastoID := c.Param("id")            // Obtém o ID do gasto da URL
+	gastoID, ok := idDaURL(c, erros.GastoVariavelNaoEncontrado)
+	if !ok {
+		return
+	}
 
 	// Remove o gasto variável do banco de dados
-	query := `
-        DELETE FROM gastos_variaveis
-        WHERE id = $1 AND usuario_id = $2
-    `
-	result, err := database.DB.Exec(context.Background(), query, gastoID, usuarioID)
+	err := h.gastosVariaveis.Remover(c.Request.Context(), usuarioID, gastoID)
+
+	// Verifica se o gasto foi encontrado e removido
+	if errors.Is(err, repositorio.ErrNaoEncontrado) {
+		erros.Responder(c, erros.Novo(erros.GastoVariavelNaoEncontrado))
+		return
+	}
+	var parcela *repositorio.ErroParcelaDeCompra
+	if errors.As(err, &parcela) {
+		erros.Responder(c, erros.Novo(erros.ParcelaDeCompra, parcela.CompraParceladaID))
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover o gasto variável"})
+		responderErroInterno(c, err, "Erro ao remover o gasto variável")
 		return
 	}
 
-	// Verifica se o gasto foi encontrado e removido
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Gasto variável não encontrado ou você não tem permissão para removê-lo"})
-		return
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgGastoVariavelRemovido)})
+}
+
+// Configurações das listagens paginadas de cada tipo de lançamento
+var (
+	listagemGastosFixos = configuracaoListagem{
+		ordenacoes:      repositorio.OrdenacoesGastosFixos,
+		ordenacaoPadrao: "created_at",
+		filtraCategoria: true,
+		filtraConta:     true,
+		filtraMes:       true,
+	}
+
+	listagemGastosVariaveis = configuracaoListagem{
+		ordenacoes:      repositorio.OrdenacoesGastosVariaveis,
+		ordenacaoPadrao: "data",
+		filtraCategoria: true,
+		filtraConta:     true,
+		filtraMes:       true,
 	}
+)
+
+// ListarGastosFixos lista os gastos fixos do usuário com paginação por cursor
+func (h *Handler) ListarGastosFixos(c *gin.Context) {
+	listar(c, listagemGastosFixos, h.gastosFixos.Listar)
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Gasto variável removido com sucesso!"})
+// ListarGastosVariaveis lista os gastos variáveis do usuário com paginação por cursor
+func (h *Handler) ListarGastosVariaveis(c *gin.Context) {
+	listar(c, listagemGastosVariaveis, h.gastosVariaveis.Listar)
 }
 
 // Registrar Usuário registra o Nome do usuário
-func RegistrarUsuario(c *gin.Context) {
-	var usuario models.Usuario
+func (h *Handler) RegistrarUsuario(c *gin.Context) {
+	var input struct {
+		Nome       string          `json:"nome" binding:"required,max=100"`
+		Email      string          `json:"email" binding:"required,email"`
+		Senha      string          `json:"senha" binding:"required,min=8,max=72"`
+		FotoPerfil string          `json:"foto_perfil" binding:"max=500"`
+		Cargo      string          `json:"cargo" binding:"max=100"`
+		Renda      models.Dinheiro `json:"renda"`  // Registrada como renda mensal de salário
+		Idioma     *string         `json:"idioma"` // Idioma preferido; ausente para seguir o Accept-Language
+	}
 
-	// Bind do JSON recebido para a struct Usuario
-	if err := c.ShouldBindJSON(&usuario); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos fornecidos."})
+	// Bind do JSON recebido para os dados de cadastro
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
+		return
+	}
+	preferido, ok := validarIdioma(c, input.Idioma)
+	if !ok {
 		return
 	}
 
-	// Insere o usuário no banco de dados
-	query := `
-        INSERT INTO usuarios (nome, foto_perfil, cargo, renda)
-        VALUES ($1, $2, $3, $4)
-        RETURNING id
-    `
-	var id int
-	err := database.DB.QueryRow(context.Background(), query,
-		usuario.Nome, usuario.FotoPerfil, usuario.Cargo, usuario.Renda,
-	).Scan(&id)
+	// Gera o hash da senha antes de armazenar
+	senhaHash, err := auth.GerarHashSenha(input.Senha)
 	if err != nil {
-		// Erro ao inserir usuário no banco de dados
-		log.Printf("Erro ao inserir usuário no banco de dados: %v", err) // Log do erro para depuração
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar usuário. Tente novamente mais tarde."})
+		responderErroInterno(c, err, "Erro ao registrar usuário. Tente novamente mais tarde.")
 		return
 	}
 
-	// Gera o token JWT
-	token, err := auth.GerarToken(id)
+	// Insere o usuário e, se informada, a renda mensal dele
+	ctx := c.Request.Context()
+	id, err := h.usuarios.Criar(ctx, repositorio.NovoUsuario{
+		Nome: input.Nome, Email: normalizarEmail(input.Email), SenhaHash: senhaHash,
+		FotoPerfil: input.FotoPerfil, Cargo: input.Cargo, Renda: input.Renda, Idioma: preferido,
+	})
+	if errors.Is(err, repositorio.ErrDuplicado) {
+		erros.Responder(c, erros.Novo(erros.CadastroIndisponivel))
+		return
+	}
 	if err != nil {
-		// Erro ao gerar o token
-		log.Printf("Erro ao gerar token para o usuário %d: %v", id, err) // Log do erro para depuração
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar token. Tente novamente mais tarde."})
+		responderErroInterno(c, err, "Erro ao registrar usuário. Tente novamente mais tarde.")
 		return
 	}
 
-	// Exibe o token gerado no console para depuração (remover em produção)
-	fmt.Println("Token gerado para o usuário:", token)
+	// Gera o token de acesso e o refresh token
+	tokens, err := h.novosTokens(ctx, id)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao gerar token. Tente novamente mais tarde.")
+		return
+	}
 
-	// Retorna o token e o ID do usuário
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Usuário registrado com sucesso!",
-		"id":      id,
-		"token":   token,
-	})
+	// Retorna os tokens e o ID do usuário
+	tokens["message"] = mensagem(c, msgUsuarioRegistrado)
+	tokens["id"] = id
+	c.JSON(http.StatusOK, tokens)
 }
 
-func AtualizarUsuario(c *gin.Context) {
-	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+// campoPatch é um campo de um JSON Merge Patch (RFC 7396): distingue o campo ausente
+// (Presente false) do enviado como null (Valor nil)
+type campoPatch[T any] struct {
+	Presente bool
+	Valor    *T
+}
 
-	// Estrutura para os dados de entrada
-	var input struct {
-		Cargo      *string  `json:"cargo"`       // Campo opcional
-		Renda      *float64 `json:"renda"`       // Campo opcional
-		FotoPerfil *string  `json:"foto_perfil"` // Campo opcional
-	}
+// lerCampoPatch lê o campo do patch; se o tipo for inválido, acrescenta o erro em invalidos
+func lerCampoPatch[T any](patch map[string]json.RawMessage, nome string, invalidos *[]erros.ErroCampo) campoPatch[T] {
+	var campo campoPatch[T]
+	dados, ok := patch[nome]
+	if !ok {
+		return campo
+	}
+	campo.Presente = true
+	if err := json.Unmarshal(dados, &campo.Valor); err != nil {
+		codigo := erros.CampoTipoInvalido
+		var casas *models.ErroCasasDecimais
+		if errors.As(err, &casas) {
+			codigo = erros.CampoCasasDecimais
+		}
+		*invalidos = append(*invalidos, erros.ErroCampo{Campo: nome, Codigo: codigo})
+	}
+	return campo
+}
 
-	// Valida a entrada
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
+// Tamanho máximo, em caracteres, dos campos de texto do perfil
+const (
+	tamanhoMaximoNome       = 100
+	tamanhoMaximoCargo      = 100
+	tamanhoMaximoFotoPerfil = 500
+)
 
-	// Construção dinâmica da query de atualização
-	query := `UPDATE usuarios SET`
-	params := []interface{}{}
-	paramIndex := 1
+// pastaFotosPerfil é a pasta das fotos enviadas pelo usuário em /usuarios/foto
+func pastaFotosPerfil(usuarioID int) string {
+	return fmt.Sprintf("uploads/%d/", usuarioID)
+}
 
-	if input.Cargo != nil {
-		query += ` cargo = $` + string(rune(paramIndex)) + `,`
-		params = append(params, *input.Cargo)
-		paramIndex++
+// fotoPerfilValida aceita uma URL http(s) ou o caminho de uma foto enviada pelo próprio usuário
+func fotoPerfilValida(usuarioID int, foto string) bool {
+	if u, err := url.Parse(foto); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+		return true
 	}
+	pasta := pastaFotosPerfil(usuarioID)
+	nome, ok := strings.CutPrefix(foto, pasta)
+	return ok && nome != "" && !strings.Contains(nome, "/") && path.Clean(foto) == foto
+}
 
-	if input.Renda != nil {
-		query += ` renda = $` + string(rune(paramIndex)) + `,`
-		params = append(params, *input.Renda)
-		paramIndex++
-	}
+// AtualizarUsuario altera o perfil do usuário autenticado com a semântica de JSON Merge Patch:
+// campos ausentes ficam como estão e null limpa cargo e foto_perfil. A foto deve ser uma URL ou
+// uma foto enviada pelo usuário; a renda troca o valor do salário mensal (null ou 0 o encerra).
+// Retorna o usuário atualizado.
+func (h *Handler) AtualizarUsuario(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
 
-	if input.FotoPerfil != nil {
-		query += ` foto_perfil = $` + string(rune(paramIndex)) + `,`
-		params = append(params, *input.FotoPerfil)
-		paramIndex++
+	// O corpo deve ser um objeto JSON; cada campo é lido à parte para distinguir ausente de null
+	var patch map[string]json.RawMessage
+	if err := c.ShouldBindBodyWithJSON(&patch); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &patch, err))
+		return
+	}
+	var invalidos []erros.ErroCampo
+	nome := lerCampoPatch[string](patch, "nome", &invalidos)
+	cargo := lerCampoPatch[string](patch, "cargo", &invalidos)
+	fotoPerfil := lerCampoPatch[string](patch, "foto_perfil", &invalidos)
+	renda := lerCampoPatch[models.Dinheiro](patch, "renda", &invalidos)
+	if len(invalidos) > 0 {
+		erros.Responder(c, erros.Validacao(invalidos...))
+		return
+	}
+
+	// O nome não pode ser removido nem ficar vazio
+	var alteracoes repositorio.AlteracoesUsuario
+	if nome.Presente {
+		if nome.Valor == nil || strings.TrimSpace(*nome.Valor) == "" {
+			erros.Responder(c, erros.NoCampo("nome", erros.CampoObrigatorio))
+			return
+		}
+		alteracoes.Nome = nome.Valor
+	}
+
+	// Limites de tamanho, como no cadastro
+	for _, campo := range []struct {
+		nome   string
+		valor  *string
+		maximo int
+	}{
+		{"nome", nome.Valor, tamanhoMaximoNome},
+		{"cargo", cargo.Valor, tamanhoMaximoCargo},
+		{"foto_perfil", fotoPerfil.Valor, tamanhoMaximoFotoPerfil},
+	} {
+		if campo.valor != nil && utf8.RuneCountInString(*campo.valor) > campo.maximo {
+			invalidos = append(invalidos, erros.ErroCampo{Campo: campo.nome, Codigo: erros.CampoTamanhoMaximo, Args: []any{campo.maximo}})
+		}
+	}
+
+	// A foto é uma URL ou uma das fotos que o próprio usuário enviou
+	if fotoPerfil.Valor != nil && *fotoPerfil.Valor != "" && !fotoPerfilValida(usuarioID, *fotoPerfil.Valor) {
+		invalidos = append(invalidos, erros.ErroCampo{
+			Campo: "foto_perfil", Codigo: erros.CampoFormato, Args: []any{"https://… | " + pastaFotosPerfil(usuarioID) + "…"},
+		})
+	}
+	if len(invalidos) > 0 {
+		erros.Responder(c, erros.Validacao(invalidos...))
+		return
+	}
+
+	// null limpa o campo, como o valor padrão do cadastro
+	limpar := func(campo campoPatch[string]) *string {
+		if campo.Presente && campo.Valor == nil {
+			return new(string)
+		}
+		return campo.Valor
+	}
+	alteracoes.Cargo = limpar(cargo)
+	alteracoes.FotoPerfil = limpar(fotoPerfil)
+
+	if renda.Presente {
+		salario := models.Dinheiro(0)
+		if renda.Valor != nil {
+			salario = *renda.Valor
+		}
+		if salario < 0 {
+			erros.Responder(c, erros.NoCampo("renda", erros.CampoMinimo, 0))
+			return
+		}
+		alteracoes.Renda = &salario
+	}
+
+	// Atualiza apenas os campos informados e lê o cadastro resultante
+	ctx := c.Request.Context()
+	err := h.usuarios.Atualizar(ctx, usuarioID, alteracoes)
+	var usuario models.Usuario
+	if err == nil {
+		usuario, err = h.usuarios.Obter(ctx, usuarioID)
 	}
-
-	// Remove a vírgula final e adiciona a cláusula WHERE
-	query = query[:len(query)-1] + ` WHERE id = $` + string(rune(paramIndex))
-	params = append(params, usuarioID)
-
-	// Executa a query de atualização
-	result, err := database.DB.Exec(context.Background(), query, params...)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar os dados do usuário"})
+	if errors.Is(err, repositorio.ErrNaoEncontrado) {
+		erros.Responder(c, erros.Novo(erros.UsuarioNaoEncontrado))
 		return
 	}
-
-	// Verifica se alguma linha foi afetada
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao atualizar os dados do usuário")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Dados do usuário atualizados com sucesso!"})
+	c.JSON(http.StatusOK, usuario)
 }
 
-// Obter Usuario retorna os dados do usuário
-func ObterUsuario(c *gin.Context) {
-	id := c.Param("id") // Obtém o ID do usuário da URL
+// ObterUsuario retorna os dados do usuário autenticado (GET /usuarios/me). Em /usuarios/:id,
+// o ID precisa ser o do próprio usuário: os dados de outros respondem como não encontrados,
+// para não revelar quais contas existem.
+func (h *Handler) ObterUsuario(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
 
-	var usuario models.Usuario
-	query := `SELECT id, nome, foto_perfil, cargo, renda, created_at FROM usuarios WHERE id = $1`
-	err := database.DB.QueryRow(context.Background(), query, id).Scan(
-		&usuario.ID, &usuario.Nome, &usuario.FotoPerfil, &usuario.Cargo, &usuario.Renda, &usuario.CreatedAt,
-	)
+	if c.Param("id") != "" {
+		id, ok := idDaURL(c, erros.UsuarioNaoEncontrado) // Obtém o ID do usuário da URL
+		if !ok {
+			return
+		}
+		if id != usuarioID {
+			erros.Responder(c, erros.Novo(erros.UsuarioNaoEncontrado))
+			return
+		}
+	}
+
+	usuario, err := h.usuarios.Obter(c.Request.Context(), usuarioID)
+	if errors.Is(err, repositorio.ErrNaoEncontrado) {
+		erros.Responder(c, erros.Novo(erros.UsuarioNaoEncontrado))
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		responderErroInterno(c, err, "Erro ao buscar o usuário")
 		return
 	}
 
 	c.JSON(http.StatusOK, usuario)
 }
 
-func UploadFotoPerfil(c *gin.Context) {
+func (h *Handler) UploadFotoPerfil(c *gin.Context) {
 	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
 
 	// Recebe o arquivo enviado no campo "foto"
 	file, err := c.FormFile("foto")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Nenhum arquivo enviado"})
+		erros.Responder(c, erros.NoCampo("foto", erros.CampoObrigatorio))
 		return
 	}
 
-	// Define o caminho onde a imagem será salva
-	filePath := "uploads/" + file.Filename
+	// Define o caminho onde a imagem será salva, na pasta do usuário
+	nomeArquivo := filepath.Base(file.Filename)
+	if nomeArquivo == "." || nomeArquivo == ".." || nomeArquivo == string(filepath.Separator) {
+		erros.Responder(c, erros.NoCampo("foto", erros.CampoInvalido))
+		return
+	}
+	filePath := pastaFotosPerfil(usuarioID) + nomeArquivo
 
 	// Salva o arquivo no servidor
 	if err := c.SaveUploadedFile(file, filePath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao salvar o arquivo"})
+		responderErroInterno(c, err, "Erro ao salvar o arquivo")
 		return
 	}
 
 	// Atualiza o caminho da foto no banco de dados
-	query := `UPDATE usuarios SET foto_perfil = $1 WHERE id = $2`
-	_, err = database.DB.Exec(context.Background(), query, filePath, usuarioID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar a foto de perfil"})
+	if err := h.usuarios.AtualizarFotoPerfil(c.Request.Context(), usuarioID, filePath); err != nil {
+		responderErroInterno(c, err, "Erro ao atualizar a foto de perfil")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message":     "Foto de perfil atualizada com sucesso!",
+		"message":     mensagem(c, msgFotoPerfilAtualizada),
 		"foto_perfil": filePath,
 	})
 }
+
+// validarIdioma normaliza o idioma preferido informado (ex.: "pt-PT" vira "pt-BR"),
+// respondendo se não for um dos suportados; nil continua nil
+func validarIdioma(c *gin.Context, tag *string) (*string, bool) {
+	if tag == nil {
+		return nil, true
+	}
+	i, ok := idioma.Interpretar(*tag)
+	if !ok {
+		erros.Responder(c, erros.NoCampo("idioma", erros.CampoOpcao, idioma.Suportados))
+		return nil, false
+	}
+	preferido := string(i)
+	return &preferido, true
+}
+
+// DefinirIdioma grava o idioma preferido do usuário ({"idioma": "pt-BR" | "en" | "es" | null}),
+// que passa a valer sobre o Accept-Language; null volta a seguir o cabeçalho
+func (h *Handler) DefinirIdioma(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	var input struct {
+		Idioma *string `json:"idioma"`
+	}
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
+		return
+	}
+	preferido, ok := validarIdioma(c, input.Idioma)
+	if !ok {
+		return
+	}
+
+	err := h.usuarios.AtualizarIdioma(c.Request.Context(), usuarioID, preferido)
+	if errors.Is(err, repositorio.ErrNaoEncontrado) {
+		erros.Responder(c, erros.Novo(erros.UsuarioNaoEncontrado))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao atualizar o idioma")
+		return
+	}
+
+	// A própria resposta já sai no idioma escolhido
+	i := idioma.Negociar(c.GetHeader("Accept-Language"))
+	if preferido != nil {
+		i = idioma.Idioma(*preferido)
+	}
+	c.Set(idioma.ChaveContexto, i)
+	c.Header("Content-Language", string(i))
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgIdiomaAtualizado), "idioma": preferido})
+}
@@ -0,0 +1,372 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/models"
+	"github.com/jpeccia/quantogasto_app_server/repositorio"
+)
+
+// servidorTeste monta as rotas de usuários, lançamentos, contas e resumo sobre os repositórios em
+// memória. O usuário autenticado vem do cabeçalho X-Usuario, no lugar do token.
+func servidorTeste() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NovoHandler(repositorio.NovoMemoria())
+	h.novosTokens = func(ctx context.Context, usuarioID int) (gin.H, error) {
+		return gin.H{"token": "teste"}, nil
+	}
+
+	r := gin.New()
+	r.POST("/usuarios", h.RegistrarUsuario)
+	auth := r.Group("/", func(c *gin.Context) {
+		id, _ := strconv.Atoi(c.GetHeader("X-Usuario"))
+		c.Set("usuario_id", id)
+	})
+	auth.GET("/usuarios/me", h.ObterUsuario)
+	auth.PATCH("/usuarios/me", h.AtualizarUsuario)
+	auth.GET("/rendas", h.ListarRendas)
+	auth.POST("/rendas", h.AdicionarRenda)
+	auth.PUT("/rendas/:id", h.EditarRenda)
+	auth.DELETE("/rendas/:id", h.RemoverRenda)
+	auth.POST("/gastos-variaveis", h.AdicionarGastoVariavel)
+	auth.GET("/contas", h.ListarContas)
+	auth.POST("/contas", h.CriarConta)
+	auth.DELETE("/contas/:id", h.RemoverConta)
+	auth.POST("/transferencias", h.AdicionarTransferencia)
+	auth.GET("/resumo", h.ObterResumo)
+	return r
+}
+
+// requisicao envia o corpo como JSON em nome do usuário e decodifica a resposta em destino, se informado
+func requisicao(t *testing.T, r *gin.Engine, metodo, caminho string, usuarioID int, corpo string, destino any) int {
+	t.Helper()
+	req := httptest.NewRequest(metodo, caminho, bytes.NewBufferString(corpo))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Usuario", strconv.Itoa(usuarioID))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if destino != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), destino); err != nil {
+			t.Fatalf("%s %s: resposta inválida %q: %v", metodo, caminho, w.Body.String(), err)
+		}
+	}
+	return w.Code
+}
+
+// cadastrar registra um usuário e retorna o ID
+func cadastrar(t *testing.T, r *gin.Engine, email, renda string) int {
+	t.Helper()
+	var resposta struct {
+		ID    int    `json:"id"`
+		Token string `json:"token"`
+	}
+	corpo := `{"nome": "Ana", "email": "` + email + `", "senha": "segredo123", "renda": "` + renda + `"}`
+	if status := requisicao(t, r, http.MethodPost, "/usuarios", 0, corpo, &resposta); status != http.StatusOK {
+		t.Fatalf("cadastro: status %d", status)
+	}
+	if resposta.ID == 0 || resposta.Token == "" {
+		t.Fatalf("cadastro sem ID ou token: %+v", resposta)
+	}
+	return resposta.ID
+}
+
+func TestRegistrarUsuario(t *testing.T) {
+	r := servidorTeste()
+	id := cadastrar(t, r, "Ana@Exemplo.com", "3500.00")
+
+	var usuario models.Usuario
+	if status := requisicao(t, r, http.MethodGet, "/usuarios/me", id, "", &usuario); status != http.StatusOK {
+		t.Fatalf("status %d", status)
+	}
+	if usuario.Email != "ana@exemplo.com" || usuario.Renda != 350000 {
+		t.Errorf("usuário = %+v, esperado e-mail normalizado e renda 3500.00", usuario)
+	}
+
+	var problema struct {
+		Codigo erros.Codigo `json:"code"`
+	}
+	corpo := `{"nome": "Outra", "email": "ANA@exemplo.com", "senha": "segredo123"}`
+	if status := requisicao(t, r, http.MethodPost, "/usuarios", 0, corpo, &problema); status != http.StatusConflict ||
+		problema.Codigo != erros.CadastroIndisponivel {
+		t.Errorf("e-mail repetido: status %d, código %s", status, problema.Codigo)
+	}
+
+	if status := requisicao(t, r, http.MethodPost, "/usuarios", 0, `{"nome": "Ana", "email": "x"}`, nil); status != http.StatusBadRequest {
+		t.Errorf("cadastro inválido: status %d, esperado %d", status, http.StatusBadRequest)
+	}
+}
+
+func TestAtualizarUsuario(t *testing.T) {
+	r := servidorTeste()
+	id := cadastrar(t, r, "ana@exemplo.com", "1000")
+
+	var usuario models.Usuario
+	status := requisicao(t, r, http.MethodPatch, "/usuarios/me", id, `{"cargo": "Analista", "renda": "2500.50"}`, &usuario)
+	if status != http.StatusOK {
+		t.Fatalf("status %d", status)
+	}
+	if usuario.Nome != "Ana" || usuario.Cargo != "Analista" || usuario.Renda != 250050 {
+		t.Errorf("usuário = %+v, esperado nome mantido, cargo e renda alterados", usuario)
+	}
+
+	// Repetir o mesmo salário não cria outra renda
+	requisicao(t, r, http.MethodPatch, "/usuarios/me", id, `{"renda": "2500.50"}`, &usuario)
+	if usuario.Renda != 250050 {
+		t.Errorf("renda = %s após repetir o salário, esperado 2500.50", usuario.Renda)
+	}
+
+	requisicao(t, r, http.MethodPatch, "/usuarios/me", id, `{"cargo": null, "renda": null}`, &usuario)
+	if usuario.Cargo != "" || usuario.Renda != 0 {
+		t.Errorf("usuário = %+v, esperado cargo e renda removidos", usuario)
+	}
+
+	if status := requisicao(t, r, http.MethodPatch, "/usuarios/me", id, `{"nome": null}`, nil); status != http.StatusBadRequest {
+		t.Errorf("nome nulo: status %d, esperado %d", status, http.StatusBadRequest)
+	}
+	if status := requisicao(t, r, http.MethodPatch, "/usuarios/me", id+100, `{"cargo": "X"}`, nil); status != http.StatusNotFound {
+		t.Errorf("usuário inexistente: status %d, esperado %d", status, http.StatusNotFound)
+	}
+}
+
+// listagemRendasTeste é a resposta de GET /rendas
+type listagemRendasTeste struct {
+	Itens         []models.Renda `json:"itens"`
+	ProximoCursor *string        `json:"proximo_cursor"`
+}
+
+func TestCRUDRendas(t *testing.T) {
+	r := servidorTeste()
+	id := cadastrar(t, r, "ana@exemplo.com", "0")
+
+	var criada struct {
+		ID int `json:"id"`
+	}
+	corpo := `{"valor": "800", "fonte": "freelance", "data_efetiva": "2025-03-10", "descricao": "Site"}`
+	if status := requisicao(t, r, http.MethodPost, "/rendas", id, corpo, &criada); status != http.StatusOK {
+		t.Fatalf("criação: status %d", status)
+	}
+	requisicao(t, r, http.MethodPost, "/rendas", id, `{"valor": "200", "data_efetiva": "2025-04-01"}`, nil)
+
+	var lista listagemRendasTeste
+	requisicao(t, r, http.MethodGet, "/rendas?ordem=asc&limite=1", id, "", &lista)
+	if len(lista.Itens) != 1 || lista.Itens[0].ID != criada.ID || lista.Itens[0].Valor != 80000 || lista.ProximoCursor == nil {
+		t.Fatalf("primeira página = %+v, esperado a renda de março e um cursor", lista)
+	}
+	requisicao(t, r, http.MethodGet, "/rendas?ordem=asc&limite=1&cursor="+*lista.ProximoCursor, id, "", &lista)
+	if len(lista.Itens) != 1 || lista.Itens[0].DataEfetiva != "2025-04-01" || lista.ProximoCursor != nil {
+		t.Fatalf("segunda página = %+v, esperado a renda de abril e nenhum cursor", lista)
+	}
+
+	caminho := "/rendas/" + strconv.Itoa(criada.ID)
+	corpo = `{"valor": "900", "recorrencia": "mensal", "data_efetiva": "2025-03-10", "data_fim": "2025-12-10"}`
+	if status := requisicao(t, r, http.MethodPut, caminho, id, corpo, nil); status != http.StatusOK {
+		t.Fatalf("edição: status %d", status)
+	}
+	requisicao(t, r, http.MethodGet, "/rendas?valor_min=900", id, "", &lista)
+	if len(lista.Itens) != 1 || lista.Itens[0].Recorrencia != models.RendaMensal || lista.Itens[0].DataFim == nil {
+		t.Fatalf("renda editada = %+v", lista.Itens)
+	}
+
+	if status := requisicao(t, r, http.MethodDelete, caminho, id, "", nil); status != http.StatusOK {
+		t.Fatalf("remoção: status %d", status)
+	}
+	requisicao(t, r, http.MethodGet, "/rendas", id, "", &lista)
+	if len(lista.Itens) != 1 {
+		t.Errorf("rendas após a remoção = %+v, esperado apenas a de abril", lista.Itens)
+	}
+}
+
+func TestRendaComContaInexistente(t *testing.T) {
+	r := servidorTeste()
+	id := cadastrar(t, r, "ana@exemplo.com", "0")
+
+	var problema struct {
+		Campos []struct {
+			Campo  string            `json:"field"`
+			Codigo erros.CodigoCampo `json:"code"`
+		} `json:"errors"`
+	}
+	status := requisicao(t, r, http.MethodPost, "/rendas", id, `{"valor": "10", "conta_id": 1}`, &problema)
+	if status != http.StatusBadRequest || len(problema.Campos) != 1 ||
+		problema.Campos[0].Campo != "conta_id" || problema.Campos[0].Codigo != erros.CampoNaoEncontrado {
+		t.Errorf("status %d, erros %+v, esperado conta_id não encontrada", status, problema.Campos)
+	}
+}
+
+func TestRendaDeOutroUsuarioNaoEncontrada(t *testing.T) {
+	r := servidorTeste()
+	titular := cadastrar(t, r, "ana@exemplo.com", "0")
+	outro := cadastrar(t, r, "bia@exemplo.com", "0")
+
+	var criada struct {
+		ID int `json:"id"`
+	}
+	requisicao(t, r, http.MethodPost, "/rendas", titular, `{"valor": "50", "data_efetiva": "`+time.Now().Format(formatoData)+`"}`, &criada)
+	caminho := "/rendas/" + strconv.Itoa(criada.ID)
+
+	casos := []struct {
+		nome    string
+		metodo  string
+		caminho string
+		corpo   string
+	}{
+		{"edição", http.MethodPut, caminho, `{"valor": "1"}`},
+		{"remoção", http.MethodDelete, caminho, ""},
+		{"ID inválido", http.MethodDelete, "/rendas/abc", ""},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			var problema struct {
+				Codigo erros.Codigo `json:"code"`
+			}
+			status := requisicao(t, r, caso.metodo, caso.caminho, outro, caso.corpo, &problema)
+			if status != http.StatusNotFound || problema.Codigo != erros.RendaNaoEncontrada {
+				t.Errorf("status %d, código %s, esperado %d %s", status, problema.Codigo, http.StatusNotFound, erros.RendaNaoEncontrada)
+			}
+		})
+	}
+
+	var lista listagemRendasTeste
+	requisicao(t, r, http.MethodGet, "/rendas", outro, "", &lista)
+	if len(lista.Itens) != 0 {
+		t.Errorf("rendas do outro usuário = %+v, esperado nenhuma", lista.Itens)
+	}
+	requisicao(t, r, http.MethodGet, "/rendas", titular, "", &lista)
+	if len(lista.Itens) != 1 || lista.Itens[0].Valor != 5000 {
+		t.Errorf("rendas da titular = %+v, esperado a renda intacta", lista.Itens)
+	}
+}
+
+// criar envia o corpo e retorna o ID do registro criado
+func criar(t *testing.T, r *gin.Engine, caminho string, usuarioID int, corpo string) int {
+	t.Helper()
+	var criado struct {
+		ID int `json:"id"`
+	}
+	if status := requisicao(t, r, http.MethodPost, caminho, usuarioID, corpo, &criado); status != http.StatusOK {
+		t.Fatalf("POST %s %s: status %d", caminho, corpo, status)
+	}
+	return criado.ID
+}
+
+// codigoDoErro envia a requisição e retorna o status e o código do problema
+func codigoDoErro(t *testing.T, r *gin.Engine, metodo, caminho string, usuarioID int, corpo string) (int, erros.Codigo) {
+	t.Helper()
+	var problema struct {
+		Codigo erros.Codigo `json:"code"`
+	}
+	status := requisicao(t, r, metodo, caminho, usuarioID, corpo, &problema)
+	return status, problema.Codigo
+}
+
+func TestObterResumo(t *testing.T) {
+	r := servidorTeste()
+	id := cadastrar(t, r, "ana@exemplo.com", "0")
+
+	criar(t, r, "/rendas", id, `{"valor": "3000", "data_efetiva": "2025-03-05"}`)
+	criar(t, r, "/gastos-variaveis", id, `{"nome": "Mercado", "valor": "120", "data": "2025-03-10", "categoria_id": 2}`)
+	criar(t, r, "/gastos-variaveis", id, `{"nome": "Feira", "valor": "80", "data": "2025-03-20", "categoria_id": 2}`)
+	criar(t, r, "/gastos-variaveis", id, `{"nome": "Presente", "valor": "30", "data": "2025-03-15"}`)
+	criar(t, r, "/gastos-variaveis", id, `{"nome": "Cinema", "valor": "50", "data": "2025-02-28", "categoria_id": 6}`)
+
+	var resumo struct {
+		resumoFinanceiro
+		Anterior resumoFinanceiro `json:"periodo_anterior"`
+	}
+	if status := requisicao(t, r, http.MethodGet, "/resumo?mes=2025-03", id, "", &resumo); status != http.StatusOK {
+		t.Fatalf("status %d", status)
+	}
+	if resumo.RendaTotal != 300000 || resumo.GastosVariaveisTotal != 23000 || resumo.SaldoDisponivel != 277000 {
+		t.Errorf("resumo = %+v, esperado renda 3000.00, gastos 230.00 e saldo 2770.00", resumo.resumoFinanceiro)
+	}
+	if len(resumo.PorCategoria) != 2 {
+		t.Fatalf("por categoria = %+v, esperado Alimentação e sem categoria", resumo.PorCategoria)
+	}
+	if l := resumo.PorCategoria[0]; l.CategoriaID == nil || *l.CategoriaID != 2 || l.Nome != "Alimentação" || l.Total != 20000 {
+		t.Errorf("primeira linha = %+v, esperado Alimentação com 200.00", l)
+	}
+	if l := resumo.PorCategoria[1]; l.CategoriaID != nil || l.Total != 3000 {
+		t.Errorf("segunda linha = %+v, esperado sem categoria com 30.00", l)
+	}
+	if resumo.Anterior.GastosVariaveisTotal != 5000 || resumo.Anterior.SaldoDisponivel != -5000 {
+		t.Errorf("período anterior = %+v, esperado gastos 50.00 e saldo -50.00", resumo.Anterior)
+	}
+
+	status, _ := codigoDoErro(t, r, http.MethodPost, "/gastos-variaveis", id, `{"nome": "X", "valor": "1", "data": "2025-03-01", "cartao_id": 1}`)
+	if status != http.StatusBadRequest {
+		t.Errorf("cartão inexistente: status %d, esperado %d", status, http.StatusBadRequest)
+	}
+}
+
+func TestContasETransferencias(t *testing.T) {
+	r := servidorTeste()
+	id := cadastrar(t, r, "ana@exemplo.com", "0")
+
+	corrente := criar(t, r, "/contas", id, `{"nome": "Banco", "tipo": "corrente", "saldo_inicial": "1000"}`)
+	carteira := criar(t, r, "/contas", id, `{"nome": "Carteira", "tipo": "dinheiro"}`)
+	if status, codigo := codigoDoErro(t, r, http.MethodPost, "/contas", id, `{"nome": "banco", "tipo": "poupanca"}`); status != http.StatusConflict ||
+		codigo != erros.ContaDuplicada {
+		t.Errorf("nome repetido: status %d, código %s", status, codigo)
+	}
+
+	ontem := time.Now().AddDate(0, 0, -1).Format(formatoData)
+	corpo := `{"conta_origem_id": ` + strconv.Itoa(corrente) + `, "conta_destino_id": ` + strconv.Itoa(carteira) +
+		`, "valor": "200", "data": "` + ontem + `"}`
+	criar(t, r, "/transferencias", id, corpo)
+	criar(t, r, "/gastos-variaveis", id, `{"nome": "Lanche", "valor": "15", "data": "`+ontem+`", "conta_id": `+strconv.Itoa(carteira)+`}`)
+
+	var contas struct {
+		Contas     []saldoConta    `json:"contas"`
+		SaldoTotal models.Dinheiro `json:"saldo_total"`
+	}
+	requisicao(t, r, http.MethodGet, "/contas", id, "", &contas)
+	if len(contas.Contas) != 2 || contas.Contas[0].SaldoAtual != 80000 || contas.Contas[1].SaldoAtual != 18500 ||
+		contas.SaldoTotal != 98500 {
+		t.Errorf("contas = %+v, esperado Banco com 800.00 e Carteira com 185.00", contas)
+	}
+
+	caminho := "/contas/" + strconv.Itoa(corrente)
+	if status, codigo := codigoDoErro(t, r, http.MethodDelete, caminho, id, ""); codigo != erros.ContaComTransferencias {
+		t.Errorf("conta com transferências: status %d, código %s", status, codigo)
+	}
+	if status, codigo := codigoDoErro(t, r, http.MethodDelete, "/contas/abc", id, ""); status != http.StatusNotFound ||
+		codigo != erros.ContaNaoEncontrada {
+		t.Errorf("ID inválido: status %d, código %s", status, codigo)
+	}
+}
+
+func TestValorComCasasDecimaisApontaOCampo(t *testing.T) {
+	r := servidorTeste()
+	id := cadastrar(t, r, "ana@exemplo.com", "0")
+
+	casos := []struct {
+		corpo  string
+		codigo erros.CodigoCampo
+	}{
+		// A descrição repete o texto do valor; o erro deve apontar o campo que o recusou
+		{`{"descricao": "10.005", "valor": "10.005"}`, erros.CampoCasasDecimais},
+		{`{"descricao": "10.005", "valor": 10.005}`, erros.CampoCasasDecimais},
+	}
+	for _, caso := range casos {
+		var problema struct {
+			Campos []struct {
+				Campo  string            `json:"field"`
+				Codigo erros.CodigoCampo `json:"code"`
+			} `json:"errors"`
+		}
+		status := requisicao(t, r, http.MethodPost, "/rendas", id, caso.corpo, &problema)
+		if status != http.StatusBadRequest || len(problema.Campos) != 1 ||
+			problema.Campos[0].Campo != "valor" || problema.Campos[0].Codigo != caso.codigo {
+			t.Errorf("%s: status %d, erros %+v, esperado valor com %s", caso.corpo, status, problema.Campos, caso.codigo)
+		}
+	}
+}
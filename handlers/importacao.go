@@ -0,0 +1,470 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jpeccia/quantogasto_app_server/database"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/idioma"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+// Tamanho máximo do arquivo de extrato aceito na importação
+const tamanhoMaximoImportacao = 5 << 20 // 5 MB
+
+// Formatos de arquivo aceitos na importação
+const (
+	formatoImportacaoOFX = "ofx"
+	formatoImportacaoCSV = "csv"
+)
+
+var errImportacaoNaoEncontrada = errors.New("importação não encontrada")
+
+var errImportacaoConfirmada = errors.New("importação já confirmada")
+
+// lerIDDoFormulario lê um campo numérico opcional do formulário, respondendo em caso de erro
+func lerIDDoFormulario(c *gin.Context, campo string) (*int, bool) {
+	texto := c.PostForm(campo)
+	if texto == "" {
+		return nil, true
+	}
+	id, err := strconv.Atoi(texto)
+	if err != nil {
+		erros.Responder(c, erros.NoCampo(campo, erros.CampoNumero))
+		return nil, false
+	}
+	return &id, true
+}
+
+// lerDestinoImportacao lê e valida os campos opcionais conta_id (conta dos lançamentos)
+// e categoria_id (categoria dos gastos) do formulário de importação
+func (h *Handler) lerDestinoImportacao(c *gin.Context, usuarioID int) (contaID, categoriaID *int, ok bool) {
+	if contaID, ok = lerIDDoFormulario(c, "conta_id"); !ok {
+		return nil, nil, false
+	}
+	if categoriaID, ok = lerIDDoFormulario(c, "categoria_id"); !ok {
+		return nil, nil, false
+	}
+
+	// Verifica se a conta e a categoria podem ser usadas pelo usuário
+	if !h.validarContaDoLancamento(c, usuarioID, "conta_id", contaID) || !h.validarCategoriaDoGasto(c, usuarioID, categoriaID) {
+		return nil, nil, false
+	}
+	return contaID, categoriaID, true
+}
+
+// lerArquivoImportacao lê o arquivo enviado no campo "arquivo", respondendo em caso de erro
+func lerArquivoImportacao(c *gin.Context) ([]byte, bool) {
+	arquivo, err := c.FormFile("arquivo")
+	if err != nil {
+		erros.Responder(c, erros.NoCampo("arquivo", erros.CampoObrigatorio))
+		return nil, false
+	}
+	if arquivo.Size > tamanhoMaximoImportacao {
+		erros.Responder(c, erros.Novo(erros.ArquivoMuitoGrande, tamanhoMaximoImportacao>>20))
+		return nil, false
+	}
+
+	f, err := arquivo.Open()
+	if err != nil {
+		erros.Responder(c, erros.Novo(erros.ArquivoIlegivel).ComCausa(err))
+		return nil, false
+	}
+	defer f.Close()
+
+	conteudo, err := io.ReadAll(io.LimitReader(f, tamanhoMaximoImportacao))
+	if err != nil {
+		erros.Responder(c, erros.Novo(erros.ArquivoIlegivel).ComCausa(err))
+		return nil, false
+	}
+	return conteudo, true
+}
+
+// marcarDuplicados marca os lançamentos já gravados em uma importação anterior
+// e os repetidos dentro do próprio arquivo
+func marcarDuplicados(ctx context.Context, usuarioID int, lancamentos []models.LancamentoImportado) error {
+	ids := make([]string, len(lancamentos))
+	for i, l := range lancamentos {
+		ids[i] = l.IDExterno
+	}
+
+	query := `
+        SELECT id_externo FROM gastos_variaveis WHERE usuario_id = $1 AND id_externo = ANY($2)
+        UNION
+        SELECT id_externo FROM rendas WHERE usuario_id = $1 AND id_externo = ANY($2)
+    `
+	rows, err := database.DB.Query(ctx, query, usuarioID, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	vistos := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		vistos[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range lancamentos {
+		lancamentos[i].Duplicado = vistos[lancamentos[i].IDExterno]
+		vistos[lancamentos[i].IDExterno] = true
+	}
+	return nil
+}
+
+// contarDuplicados retorna quantos lançamentos são novos e quantos já foram importados
+func contarDuplicados(lancamentos []models.LancamentoImportado) (novos, duplicados int) {
+	for _, l := range lancamentos {
+		if l.Duplicado {
+			duplicados++
+		} else {
+			novos++
+		}
+	}
+	return novos, duplicados
+}
+
+// consultaImportacao seleciona uma importação do usuário pelo ID
+const consultaImportacao = `
+        SELECT id, usuario_id, formato, conta_id, lancamentos, erros, confirmada_em, created_at
+        FROM importacoes
+        WHERE id = $1 AND usuario_id = $2
+    `
+
+// carregarImportacao busca uma importação do usuário, sem bloqueá-la
+func carregarImportacao(ctx context.Context, db consultor, usuarioID, importacaoID int) (models.Importacao, error) {
+	return lerImportacao(ctx, db, consultaImportacao, usuarioID, importacaoID)
+}
+
+// bloquearImportacao busca uma importação do usuário, bloqueando-a até o fim da transação
+func bloquearImportacao(ctx context.Context, tx pgx.Tx, usuarioID, importacaoID int) (models.Importacao, error) {
+	return lerImportacao(ctx, tx, consultaImportacao+"FOR UPDATE", usuarioID, importacaoID)
+}
+
+// lerImportacao executa a consulta da importação e converte a ausência em errImportacaoNaoEncontrada
+func lerImportacao(ctx context.Context, db consultor, query string, usuarioID, importacaoID int) (models.Importacao, error) {
+	var imp models.Importacao
+	err := db.QueryRow(ctx, query, importacaoID, usuarioID).Scan(
+		&imp.ID, &imp.UsuarioID, &imp.Formato, &imp.ContaID, &imp.Lancamentos, &imp.Erros, &imp.ConfirmadaEm, &imp.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return imp, errImportacaoNaoEncontrada
+	}
+	return imp, err
+}
+
+// registrarImportacao marca os lançamentos duplicados, guarda a importação pendente
+// e responde com a pré-visualização e o relatório de linhas rejeitadas
+func registrarImportacao(c *gin.Context, imp models.Importacao) {
+	ctx := c.Request.Context()
+	if err := marcarDuplicados(ctx, imp.UsuarioID, imp.Lancamentos); err != nil {
+		responderErroInterno(c, err, "Erro ao verificar lançamentos já importados")
+		return
+	}
+
+	query := `
+        INSERT INTO importacoes (usuario_id, formato, conta_id, lancamentos, erros)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, created_at
+    `
+	err := database.DB.QueryRow(ctx, query, imp.UsuarioID, imp.Formato, imp.ContaID, imp.Lancamentos, imp.Erros).
+		Scan(&imp.ID, &imp.CreatedAt)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao registrar a importação")
+		return
+	}
+
+	novos, duplicados := contarDuplicados(imp.Lancamentos)
+	c.JSON(http.StatusOK, gin.H{"importacao": imp, "novos": novos, "duplicados": duplicados})
+}
+
+// ImportarOFX lê um extrato OFX (1.x SGML ou 2.x XML) enviado no campo "arquivo" e guarda os
+// lançamentos como uma importação pendente, sem gravar nada ainda. Débitos viram gastos
+// variáveis e créditos, rendas; os já importados antes (mesmo FITID) vêm marcados como
+// duplicados. Campos opcionais: conta_id (conta dos lançamentos) e categoria_id (dos gastos).
+// As transações com data ou valor inválido não impedem a importação das demais e são listadas
+// em "erros", com a linha do <STMTTRN> no arquivo.
+func (h *Handler) ImportarOFX(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	contaID, categoriaID, ok := h.lerDestinoImportacao(c, usuarioID)
+	if !ok {
+		return
+	}
+
+	conteudo, ok := lerArquivoImportacao(c)
+	if !ok {
+		return
+	}
+
+	extrato, err := lerOFX(conteudo)
+	if err != nil {
+		erros.Responder(c, err)
+		return
+	}
+
+	i := idioma.DaRequisicao(c)
+	lancamentos := []models.LancamentoImportado{}
+	errosTransacoes := []models.ErroImportacao{}
+	for _, t := range extrato.Transacoes {
+		l, err := t.lancamento(extrato.Conta, i)
+		if err != nil {
+			errosTransacoes = append(errosTransacoes, models.ErroImportacao{Linha: t.Linha, Erro: mensagemErroLinha(err, i)})
+			continue
+		}
+		if l.Valor == 0 {
+			continue
+		}
+		if l.Tipo == models.LancamentoGastoVariavel {
+			l.CategoriaID = categoriaID
+		}
+		lancamentos = append(lancamentos, l)
+	}
+
+	registrarImportacao(c, models.Importacao{
+		UsuarioID:   usuarioID,
+		Formato:     formatoImportacaoOFX,
+		ContaID:     contaID,
+		Lancamentos: lancamentos,
+		Erros:       errosTransacoes,
+	})
+}
+
+// ImportarCSV lê um extrato CSV enviado no campo "arquivo" com o mapeamento de colunas do perfil
+// informado em perfil_id e guarda os lançamentos como uma importação pendente, como em ImportarOFX.
+// As linhas rejeitadas não impedem a importação das demais e são listadas em "erros".
+func (h *Handler) ImportarCSV(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	perfilID, ok := lerIDDoFormulario(c, "perfil_id")
+	if !ok {
+		return
+	}
+	if perfilID == nil {
+		erros.Responder(c, erros.NoCampo("perfil_id", erros.CampoObrigatorio))
+		return
+	}
+	perfil, err := carregarPerfilImportacao(c.Request.Context(), database.DB, usuarioID, *perfilID)
+	if errors.Is(err, errPerfilImportacaoNaoEncontrado) {
+		erros.Responder(c, erros.NoCampo("perfil_id", erros.CampoNaoEncontrado))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar o perfil de importação")
+		return
+	}
+
+	contaID, categoriaID, ok := h.lerDestinoImportacao(c, usuarioID)
+	if !ok {
+		return
+	}
+
+	conteudo, ok := lerArquivoImportacao(c)
+	if !ok {
+		return
+	}
+
+	lancamentos, errosLinhas, err := lerCSV(conteudo, perfil, idioma.DaRequisicao(c))
+	if err != nil {
+		erros.Responder(c, err)
+		return
+	}
+	for i := range lancamentos {
+		if lancamentos[i].Tipo == models.LancamentoGastoVariavel {
+			lancamentos[i].CategoriaID = categoriaID
+		}
+	}
+
+	registrarImportacao(c, models.Importacao{
+		UsuarioID:   usuarioID,
+		Formato:     formatoImportacaoCSV,
+		ContaID:     contaID,
+		Lancamentos: lancamentos,
+		Erros:       errosLinhas,
+	})
+}
+
+// ObterImportacao retorna uma importação com os lançamentos para revisão.
+// Enquanto pendente, a marcação de duplicados é refeita com os dados atuais.
+func ObterImportacao(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	importacaoID, ok := idDaURL(c, erros.ImportacaoNaoEncontrada)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	imp, err := carregarImportacao(ctx, database.DB, usuarioID, importacaoID)
+	if errors.Is(err, errImportacaoNaoEncontrada) {
+		erros.Responder(c, erros.Novo(erros.ImportacaoNaoEncontrada))
+		return
+	}
+	if err == nil && imp.ConfirmadaEm == nil {
+		err = marcarDuplicados(ctx, usuarioID, imp.Lancamentos)
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar a importação")
+		return
+	}
+
+	novos, duplicados := contarDuplicados(imp.Lancamentos)
+	c.JSON(http.StatusOK, gin.H{"importacao": imp, "novos": novos, "duplicados": duplicados})
+}
+
+// ajusteLancamento é a revisão de um lançamento feita pelo usuário antes de confirmar
+type ajusteLancamento struct {
+	IDExterno   string  `json:"id_externo" binding:"required"`
+	Ignorar     bool    `json:"ignorar"`      // não grava o lançamento
+	Nome        *string `json:"nome"`         // substitui a descrição do extrato
+	CategoriaID *int    `json:"categoria_id"` // categoria do gasto
+}
+
+// ConfirmarImportacao grava os lançamentos de uma importação pendente, aplicando os ajustes
+// opcionais ({"ajustes": [{"id_externo", "ignorar", "nome", "categoria_id"}]}). Lançamentos
+// cujo FITID já foi gravado são pulados, mesmo que importados depois da pré-visualização.
+func (h *Handler) ConfirmarImportacao(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	importacaoID, ok := idDaURL(c, erros.ImportacaoNaoEncontrada)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Ajustes []ajusteLancamento `json:"ajustes" binding:"dive"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+			erros.Responder(c, erros.DaValidacao(c, &input, err))
+			return
+		}
+	}
+
+	ajustes := make(map[string]ajusteLancamento, len(input.Ajustes))
+	for i, a := range input.Ajustes {
+		if a.Nome != nil && (*a.Nome == "" || len([]rune(*a.Nome)) > tamanhoMaximoNomeImportado) {
+			campo := fmt.Sprintf("ajustes[%d].nome", i)
+			erros.Responder(c, erros.NoCampo(campo, erros.CampoTamanho, 1, tamanhoMaximoNomeImportado))
+			return
+		}
+		if !h.validarCategoriaDoGasto(c, usuarioID, a.CategoriaID) {
+			return
+		}
+		ajustes[a.IDExterno] = a
+	}
+
+	var importados, duplicados, ignorados int
+	ctx := c.Request.Context()
+	err := pgx.BeginFunc(ctx, database.DB, func(tx pgx.Tx) error {
+		imp, err := bloquearImportacao(ctx, tx, usuarioID, importacaoID)
+		if err != nil {
+			return err
+		}
+		if imp.ConfirmadaEm != nil {
+			return errImportacaoConfirmada
+		}
+
+		for _, l := range imp.Lancamentos {
+			if a, ok := ajustes[l.IDExterno]; ok {
+				if a.Ignorar {
+					ignorados++
+					continue
+				}
+				if a.Nome != nil {
+					l.Nome = *a.Nome
+				}
+				if a.CategoriaID != nil {
+					l.CategoriaID = a.CategoriaID
+				}
+			}
+
+			var query string
+			var args []any
+			if l.Tipo == models.LancamentoRenda {
+				query = `
+                    INSERT INTO rendas (usuario_id, valor, fonte, descricao, recorrencia, data_efetiva, conta_id, id_externo)
+                    VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+                    ON CONFLICT (usuario_id, id_externo) WHERE id_externo IS NOT NULL DO NOTHING
+                `
+				args = []any{usuarioID, l.Valor, models.FonteOutra, l.Nome, models.RendaUnica, l.Data, imp.ContaID, l.IDExterno}
+			} else {
+				query = `
+                    INSERT INTO gastos_variaveis (usuario_id, nome, valor, data, categoria_id, conta_id, id_externo)
+                    VALUES ($1, $2, $3, $4, $5, $6, $7)
+                    ON CONFLICT (usuario_id, id_externo) WHERE id_externo IS NOT NULL DO NOTHING
+                `
+				args = []any{usuarioID, l.Nome, l.Valor, l.Data, l.CategoriaID, imp.ContaID, l.IDExterno}
+			}
+
+			result, err := tx.Exec(ctx, query, args...)
+			if err != nil {
+				return err
+			}
+			if result.RowsAffected() == 0 {
+				duplicados++
+			} else {
+				importados++
+			}
+		}
+
+		_, err = tx.Exec(ctx, `UPDATE importacoes SET confirmada_em = $1 WHERE id = $2`, time.Now(), imp.ID)
+		return err
+	})
+	if errors.Is(err, errImportacaoNaoEncontrada) {
+		erros.Responder(c, erros.Novo(erros.ImportacaoNaoEncontrada))
+		return
+	}
+	if errors.Is(err, errImportacaoConfirmada) {
+		erros.Responder(c, erros.Novo(erros.ImportacaoConfirmada))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao confirmar a importação")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    mensagem(c, msgImportacaoConfirmada),
+		"importados": importados,
+		"duplicados": duplicados,
+		"ignorados":  ignorados,
+	})
+}
+
+// DescartarImportacao remove uma importação sem gravar os lançamentos dela.
+// Os lançamentos de uma importação já confirmada são mantidos.
+func DescartarImportacao(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	importacaoID, ok := idDaURL(c, erros.ImportacaoNaoEncontrada)
+	if !ok {
+		return
+	}
+
+	query := `DELETE FROM importacoes WHERE id = $1 AND usuario_id = $2`
+	result, err := database.DB.Exec(c.Request.Context(), query, importacaoID, usuarioID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao descartar a importação")
+		return
+	}
+
+	// Verifica se a importação foi encontrada e removida
+	if result.RowsAffected() == 0 {
+		erros.Responder(c, erros.Novo(erros.ImportacaoNaoEncontrada))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgImportacaoDescartada)})
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/models"
+	"github.com/jpeccia/quantogasto_app_server/repositorio"
+)
+
+// Limites de itens por página nas listagens
+const (
+	limiteListagemPadrao = 20
+	limiteListagemMaximo = 100
+)
+
+// configuracaoListagem descreve os parâmetros aceitos na listagem de um tipo de lançamento
+type configuracaoListagem struct {
+	ordenacoes      map[string]string // campos aceitos em 'ordenar', com o tipo de cada um
+	ordenacaoPadrao string
+	filtraCategoria bool // aceita o filtro 'categoria_id'
+	filtraConta     bool // aceita o filtro 'conta_id'
+	filtraMes       bool // aceita o filtro 'mes'
+}
+
+// cursorListagem identifica o último item entregue, para buscar a página seguinte (keyset)
+type cursorListagem struct {
+	Ordenar string `json:"o"`
+	Ordem   string `json:"d"`
+	Valor   string `json:"v"`
+	ID      int    `json:"i"`
+}
+
+// parametrosListagem são os filtros, a ordenação e a paginação de uma listagem
+type parametrosListagem struct {
+	ordenar     string
+	ordem       string
+	limite      int
+	cursor      *cursorListagem
+	de          time.Time
+	ate         time.Time // exclusivo
+	valorMin    *models.Dinheiro
+	valorMax    *models.Dinheiro
+	categoriaID *int
+	contaID     *int
+	mes         time.Time
+}
+
+// codificarCursor gera o cursor opaco enviado ao cliente
+func codificarCursor(cursor cursorListagem) string {
+	b, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodificarCursor interpreta o cursor recebido do cliente
+func decodificarCursor(valor string) (*cursorListagem, error) {
+	b, err := base64.RawURLEncoding.DecodeString(valor)
+	if err != nil {
+		return nil, err
+	}
+	var cursor cursorListagem
+	if err := json.Unmarshal(b, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// codigoErroValor é o código de validação de um valor monetário que não pôde ser lido
+func codigoErroValor(err error) erros.CodigoCampo {
+	var casas *models.ErroCasasDecimais
+	if errors.As(err, &casas) {
+		return erros.CampoCasasDecimais
+	}
+	return erros.CampoNumero
+}
+
+// interpretarValorFiltro lê um filtro numérico opcional da query string
+func interpretarValorFiltro(c *gin.Context, nome string) (*models.Dinheiro, error) {
+	texto := c.Query(nome)
+	if texto == "" {
+		return nil, nil
+	}
+	valor, err := models.ParseDinheiro(texto)
+	if err != nil {
+		return nil, erros.NoCampo(nome, codigoErroValor(err))
+	}
+	return &valor, nil
+}
+
+// interpretarParametrosListagem lê limite, cursor, ordenação e filtros da query string
+func interpretarParametrosListagem(c *gin.Context, cfg configuracaoListagem) (parametrosListagem, error) {
+	p := parametrosListagem{
+		ordenar: c.DefaultQuery("ordenar", cfg.ordenacaoPadrao),
+		ordem:   strings.ToLower(c.DefaultQuery("ordem", "desc")),
+		limite:  limiteListagemPadrao,
+	}
+
+	if _, ok := cfg.ordenacoes[p.ordenar]; !ok {
+		campos := make([]string, 0, len(cfg.ordenacoes))
+		for campo := range cfg.ordenacoes {
+			campos = append(campos, campo)
+		}
+		sort.Strings(campos)
+		return p, erros.NoCampo("ordenar", erros.CampoOpcao, campos)
+	}
+	if p.ordem != "asc" && p.ordem != "desc" {
+		return p, erros.NoCampo("ordem", erros.CampoOpcao, []string{"asc", "desc"})
+	}
+
+	if texto := c.Query("limite"); texto != "" {
+		limite, err := strconv.Atoi(texto)
+		if err != nil || limite < 1 || limite > limiteListagemMaximo {
+			return p, erros.NoCampo("limite", erros.CampoIntervalo, 1, limiteListagemMaximo)
+		}
+		p.limite = limite
+	}
+
+	if texto := c.Query("cursor"); texto != "" {
+		cursor, err := decodificarCursor(texto)
+		if err != nil || cursor.Ordenar != p.ordenar || cursor.Ordem != p.ordem ||
+			!repositorio.ValorCursorValido(cfg.ordenacoes[p.ordenar], cursor.Valor) {
+			return p, erros.NoCampo("cursor", erros.CampoInvalido)
+		}
+		p.cursor = cursor
+	}
+
+	if texto := c.Query("de"); texto != "" {
+		de, err := interpretarLimite(texto, false)
+		if err != nil {
+			return p, erros.NoCampo("de", erros.CampoFormatoDataOuMes)
+		}
+		p.de = de
+	}
+	if texto := c.Query("ate"); texto != "" {
+		ate, err := interpretarLimite(texto, true)
+		if err != nil {
+			return p, erros.NoCampo("ate", erros.CampoFormatoDataOuMes)
+		}
+		p.ate = ate
+	}
+
+	var err error
+	if p.valorMin, err = interpretarValorFiltro(c, "valor_min"); err != nil {
+		return p, err
+	}
+	if p.valorMax, err = interpretarValorFiltro(c, "valor_max"); err != nil {
+		return p, err
+	}
+
+	if texto := c.Query("categoria_id"); texto != "" && cfg.filtraCategoria {
+		categoriaID, err := strconv.Atoi(texto)
+		if err != nil {
+			return p, erros.NoCampo("categoria_id", erros.CampoNumero)
+		}
+		p.categoriaID = &categoriaID
+	}
+
+	if texto := c.Query("mes"); texto != "" && cfg.filtraMes {
+		mes, err := time.Parse(formatoMes, texto)
+		if err != nil {
+			return p, erros.NoCampo("mes", erros.CampoFormatoMes)
+		}
+		p.mes = mes
+	}
+
+	if texto := c.Query("conta_id"); texto != "" && cfg.filtraConta {
+		contaID, err := strconv.Atoi(texto)
+		if err != nil {
+			return p, erros.NoCampo("conta_id", erros.CampoNumero)
+		}
+		p.contaID = &contaID
+	}
+
+	return p, nil
+}
+
+// listagem converte os parâmetros para o repositório, pedindo um item além do limite para
+// saber se existe uma próxima página
+func (p parametrosListagem) listagem() repositorio.Listagem {
+	l := repositorio.Listagem{
+		Ordenar:     p.ordenar,
+		Crescente:   p.ordem == "asc",
+		Limite:      p.limite + 1,
+		De:          p.de,
+		Ate:         p.ate,
+		ValorMin:    p.valorMin,
+		ValorMax:    p.valorMax,
+		CategoriaID: p.categoriaID,
+		ContaID:     p.contaID,
+		Mes:         p.mes,
+	}
+	if p.cursor != nil {
+		l.Apos = &repositorio.Cursor{Valor: p.cursor.Valor, ID: p.cursor.ID}
+	}
+	return l
+}
+
+// listar executa uma listagem paginada e responde com os itens e o cursor da próxima página
+func listar[T any](c *gin.Context, cfg configuracaoListagem,
+	buscar func(ctx context.Context, usuarioID int, l repositorio.Listagem) ([]repositorio.Listado[T], error)) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	p, err := interpretarParametrosListagem(c, cfg)
+	if err != nil {
+		erros.Responder(c, err)
+		return
+	}
+
+	listados, err := buscar(c.Request.Context(), usuarioID, p.listagem())
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar os registros")
+		return
+	}
+
+	// Um item além do limite indica que existe uma próxima página
+	temMais := len(listados) > p.limite
+	if temMais {
+		listados = listados[:p.limite]
+	}
+
+	itens := make([]T, 0, len(listados))
+	for _, l := range listados {
+		itens = append(itens, l.Item)
+	}
+
+	var proximoCursor *string
+	if temMais {
+		ultimo := listados[len(listados)-1]
+		cursor := codificarCursor(cursorListagem{Ordenar: p.ordenar, Ordem: p.ordem, Valor: ultimo.ValorOrdenacao, ID: ultimo.ID})
+		proximoCursor = &cursor
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"itens":          itens,
+		"proximo_cursor": proximoCursor,
+	})
+}
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jpeccia/quantogasto_app_server/idioma"
+)
+
+// codigoSucesso identifica a mensagem de uma operação concluída no catálogo de mensagens
+type codigoSucesso string
+
+// Códigos das mensagens de sucesso
+const (
+	msgImportacaoConfirmada       codigoSucesso = "importacao_confirmada"
+	msgImportacaoDescartada       codigoSucesso = "importacao_descartada"
+	msgOrcamentoDefinido          codigoSucesso = "orcamento_definido"
+	msgOrcamentoRemovido          codigoSucesso = "orcamento_removido"
+	msgGastoFixoAdicionado        codigoSucesso = "gasto_fixo_adicionado"
+	msgGastoVariavelAdicionado    codigoSucesso = "gasto_variavel_adicionado"
+	msgGastoFixoAtualizado        codigoSucesso = "gasto_fixo_atualizado"
+	msgGastoVariavelAtualizado    codigoSucesso = "gasto_variavel_atualizado"
+	msgGastoFixoRemovido          codigoSucesso = "gasto_fixo_removido"
+	msgGastoVariavelRemovido      codigoSucesso = "gasto_variavel_removido"
+	msgUsuarioRegistrado          codigoSucesso = "usuario_registrado"
+	msgFotoPerfilAtualizada       codigoSucesso = "foto_perfil_atualizada"
+	msgIdiomaAtualizado           codigoSucesso = "idioma_atualizado"
+	msgContaCriada                codigoSucesso = "conta_criada"
+	msgContaAtualizada            codigoSucesso = "conta_atualizada"
+	msgContaRemovida              codigoSucesso = "conta_removida"
+	msgTransferenciaAdicionada    codigoSucesso = "transferencia_adicionada"
+	msgTransferenciaRemovida      codigoSucesso = "transferencia_removida"
+	msgCategoriaCriada            codigoSucesso = "categoria_criada"
+	msgCategoriaAtualizada        codigoSucesso = "categoria_atualizada"
+	msgCategoriaRemovida          codigoSucesso = "categoria_removida"
+	msgMetaCriada                 codigoSucesso = "meta_criada"
+	msgMetaAtualizada             codigoSucesso = "meta_atualizada"
+	msgMetaRemovida               codigoSucesso = "meta_removida"
+	msgAporteAdicionado           codigoSucesso = "aporte_adicionado"
+	msgAporteRemovido             codigoSucesso = "aporte_removido"
+	msgCompraParceladaAdicionada  codigoSucesso = "compra_parcelada_adicionada"
+	msgCompraParceladaAtualizada  codigoSucesso = "compra_parcelada_atualizada"
+	msgCompraParceladaCancelada   codigoSucesso = "compra_parcelada_cancelada"
+	msgPerfilImportacaoCriado     codigoSucesso = "perfil_importacao_criado"
+	msgPerfilImportacaoAtualizado codigoSucesso = "perfil_importacao_atualizado"
+	msgPerfilImportacaoRemovido   codigoSucesso = "perfil_importacao_removido"
+	msgCartaoCriado               codigoSucesso = "cartao_criado"
+	msgCartaoAtualizado           codigoSucesso = "cartao_atualizado"
+	msgCartaoRemovido             codigoSucesso = "cartao_removido"
+	msgRendaAdicionada            codigoSucesso = "renda_adicionada"
+	msgRendaAtualizada            codigoSucesso = "renda_atualizada"
+	msgRendaRemovida              codigoSucesso = "renda_removida"
+	msgLoginRealizado             codigoSucesso = "login_realizado"
+	msgLogoutRealizado            codigoSucesso = "logout_realizado"
+	msgLogoutTodos                codigoSucesso = "logout_todos"
+)
+
+// mensagensSucesso é o catálogo das mensagens de sucesso de cada idioma
+var mensagensSucesso = idioma.Catalogo{
+	idioma.PortuguesBR: {
+		string(msgImportacaoConfirmada):       "Importação confirmada com sucesso!",
+		string(msgImportacaoDescartada):       "Importação descartada com sucesso!",
+		string(msgOrcamentoDefinido):          "Orçamento definido com sucesso!",
+		string(msgOrcamentoRemovido):          "Orçamento removido com sucesso!",
+		string(msgGastoFixoAdicionado):        "Gasto fixo adicionado com sucesso!",
+		string(msgGastoVariavelAdicionado):    "Gasto variável adicionado com sucesso!",
+		string(msgGastoFixoAtualizado):        "Gasto fixo atualizado com sucesso!",
+		string(msgGastoVariavelAtualizado):    "Gasto variável atualizado com sucesso!",
+		string(msgGastoFixoRemovido):          "Gasto fixo removido com sucesso!",
+		string(msgGastoVariavelRemovido):      "Gasto variável removido com sucesso!",
+		string(msgUsuarioRegistrado):          "Usuário registrado com sucesso!",
+		string(msgFotoPerfilAtualizada):       "Foto de perfil atualizada com sucesso!",
+		string(msgIdiomaAtualizado):           "Idioma atualizado com sucesso!",
+		string(msgContaCriada):                "Conta criada com sucesso!",
+		string(msgContaAtualizada):            "Conta atualizada com sucesso!",
+		string(msgContaRemovida):              "Conta removida com sucesso!",
+		string(msgTransferenciaAdicionada):    "Transferência adicionada com sucesso!",
+		string(msgTransferenciaRemovida):      "Transferência removida com sucesso!",
+		string(msgCategoriaCriada):            "Categoria criada com sucesso!",
+		string(msgCategoriaAtualizada):        "Categoria atualizada com sucesso!",
+		string(msgCategoriaRemovida):          "Categoria removida com sucesso!",
+		string(msgMetaCriada):                 "Meta criada com sucesso!",
+		string(msgMetaAtualizada):             "Meta atualizada com sucesso!",
+		string(msgMetaRemovida):               "Meta removida com sucesso!",
+		string(msgAporteAdicionado):           "Aporte adicionado com sucesso!",
+		string(msgAporteRemovido):             "Aporte removido com sucesso!",
+		string(msgCompraParceladaAdicionada):  "Compra parcelada adicionada com sucesso!",
+		string(msgCompraParceladaAtualizada):  "Compra parcelada atualizada com sucesso!",
+		string(msgCompraParceladaCancelada):   "Compra parcelada cancelada com sucesso!",
+		string(msgPerfilImportacaoCriado):     "Perfil de importação criado com sucesso!",
+		string(msgPerfilImportacaoAtualizado): "Perfil de importação atualizado com sucesso!",
+		string(msgPerfilImportacaoRemovido):   "Perfil de importação removido com sucesso!",
+		string(msgCartaoCriado):               "Cartão criado com sucesso!",
+		string(msgCartaoAtualizado):           "Cartão atualizado com sucesso!",
+		string(msgCartaoRemovido):             "Cartão removido com sucesso!",
+		string(msgRendaAdicionada):            "Renda adicionada com sucesso!",
+		string(msgRendaAtualizada):            "Renda atualizada com sucesso!",
+		string(msgRendaRemovida):              "Renda removida com sucesso!",
+		string(msgLoginRealizado):             "Login realizado com sucesso!",
+		string(msgLogoutRealizado):            "Logout realizado com sucesso!",
+		string(msgLogoutTodos):                "Logout realizado em todos os dispositivos!",
+	},
+
+	idioma.Ingles: {
+		string(msgImportacaoConfirmada):       "Import confirmed successfully!",
+		string(msgImportacaoDescartada):       "Import discarded successfully!",
+		string(msgOrcamentoDefinido):          "Budget set successfully!",
+		string(msgOrcamentoRemovido):          "Budget removed successfully!",
+		string(msgGastoFixoAdicionado):        "Fixed expense added successfully!",
+		string(msgGastoVariavelAdicionado):    "Variable expense added successfully!",
+		string(msgGastoFixoAtualizado):        "Fixed expense updated successfully!",
+		string(msgGastoVariavelAtualizado):    "Variable expense updated successfully!",
+		string(msgGastoFixoRemovido):          "Fixed expense removed successfully!",
+		string(msgGastoVariavelRemovido):      "Variable expense removed successfully!",
+		string(msgUsuarioRegistrado):          "User registered successfully!",
+		string(msgFotoPerfilAtualizada):       "Profile picture updated successfully!",
+		string(msgIdiomaAtualizado):           "Language updated successfully!",
+		string(msgContaCriada):                "Account created successfully!",
+		string(msgContaAtualizada):            "Account updated successfully!",
+		string(msgContaRemovida):              "Account removed successfully!",
+		string(msgTransferenciaAdicionada):    "Transfer added successfully!",
+		string(msgTransferenciaRemovida):      "Transfer removed successfully!",
+		string(msgCategoriaCriada):            "Category created successfully!",
+		string(msgCategoriaAtualizada):        "Category updated successfully!",
+		string(msgCategoriaRemovida):          "Category removed successfully!",
+		string(msgMetaCriada):                 "Goal created successfully!",
+		string(msgMetaAtualizada):             "Goal updated successfully!",
+		string(msgMetaRemovida):               "Goal removed successfully!",
+		string(msgAporteAdicionado):           "Contribution added successfully!",
+		string(msgAporteRemovido):             "Contribution removed successfully!",
+		string(msgCompraParceladaAdicionada):  "Installment purchase added successfully!",
+		string(msgCompraParceladaAtualizada):  "Installment purchase updated successfully!",
+		string(msgCompraParceladaCancelada):   "Installment purchase cancelled successfully!",
+		string(msgPerfilImportacaoCriado):     "Import profile created successfully!",
+		string(msgPerfilImportacaoAtualizado): "Import profile updated successfully!",
+		string(msgPerfilImportacaoRemovido):   "Import profile removed successfully!",
+		string(msgCartaoCriado):               "Card created successfully!",
+		string(msgCartaoAtualizado):           "Card updated successfully!",
+		string(msgCartaoRemovido):             "Card removed successfully!",
+		string(msgRendaAdicionada):            "Income added successfully!",
+		string(msgRendaAtualizada):            "Income updated successfully!",
+		string(msgRendaRemovida):              "Income removed successfully!",
+		string(msgLoginRealizado):             "Logged in successfully!",
+		string(msgLogoutRealizado):            "Logged out successfully!",
+		string(msgLogoutTodos):                "Logged out of all devices!",
+	},
+
+	idioma.Espanhol: {
+		string(msgImportacaoConfirmada):       "¡Importación confirmada con éxito!",
+		string(msgImportacaoDescartada):       "¡Importación descartada con éxito!",
+		string(msgOrcamentoDefinido):          "¡Presupuesto definido con éxito!",
+		string(msgOrcamentoRemovido):          "¡Presupuesto eliminado con éxito!",
+		string(msgGastoFixoAdicionado):        "¡Gasto fijo agregado con éxito!",
+		string(msgGastoVariavelAdicionado):    "¡Gasto variable agregado con éxito!",
+		string(msgGastoFixoAtualizado):        "¡Gasto fijo actualizado con éxito!",
+		string(msgGastoVariavelAtualizado):    "¡Gasto variable actualizado con éxito!",
+		string(msgGastoFixoRemovido):          "¡Gasto fijo eliminado con éxito!",
+		string(msgGastoVariavelRemovido):      "¡Gasto variable eliminado con éxito!",
+		string(msgUsuarioRegistrado):          "¡Usuario registrado con éxito!",
+		string(msgFotoPerfilAtualizada):       "¡Foto de perfil actualizada con éxito!",
+		string(msgIdiomaAtualizado):           "¡Idioma actualizado con éxito!",
+		string(msgContaCriada):                "¡Cuenta creada con éxito!",
+		string(msgContaAtualizada):            "¡Cuenta actualizada con éxito!",
+		string(msgContaRemovida):              "¡Cuenta eliminada con éxito!",
+		string(msgTransferenciaAdicionada):    "¡Transferencia agregada con éxito!",
+		string(msgTransferenciaRemovida):      "¡Transferencia eliminada con éxito!",
+		string(msgCategoriaCriada):            "¡Categoría creada con éxito!",
+		string(msgCategoriaAtualizada):        "¡Categoría actualizada con éxito!",
+		string(msgCategoriaRemovida):          "¡Categoría eliminada con éxito!",
+		string(msgMetaCriada):                 "¡Meta creada con éxito!",
+		string(msgMetaAtualizada):             "¡Meta actualizada con éxito!",
+		string(msgMetaRemovida):               "¡Meta eliminada con éxito!",
+		string(msgAporteAdicionado):           "¡Aporte agregado con éxito!",
+		string(msgAporteRemovido):             "¡Aporte eliminado con éxito!",
+		string(msgCompraParceladaAdicionada):  "¡Compra a plazos agregada con éxito!",
+		string(msgCompraParceladaAtualizada):  "¡Compra a plazos actualizada con éxito!",
+		string(msgCompraParceladaCancelada):   "¡Compra a plazos cancelada con éxito!",
+		string(msgPerfilImportacaoCriado):     "¡Perfil de importación creado con éxito!",
+		string(msgPerfilImportacaoAtualizado): "¡Perfil de importación actualizado con éxito!",
+		string(msgPerfilImportacaoRemovido):   "¡Perfil de importación eliminado con éxito!",
+		string(msgCartaoCriado):               "¡Tarjeta creada con éxito!",
+		string(msgCartaoAtualizado):           "¡Tarjeta actualizada con éxito!",
+		string(msgCartaoRemovido):             "¡Tarjeta eliminada con éxito!",
+		string(msgRendaAdicionada):            "¡Ingreso agregado con éxito!",
+		string(msgRendaAtualizada):            "¡Ingreso actualizado con éxito!",
+		string(msgRendaRemovida):              "¡Ingreso eliminado con éxito!",
+		string(msgLoginRealizado):             "¡Inicio de sesión exitoso!",
+		string(msgLogoutRealizado):            "¡Sesión cerrada con éxito!",
+		string(msgLogoutTodos):                "¡Sesión cerrada en todos los dispositivos!",
+	},
+}
+
+// mensagem é o texto de sucesso no idioma da requisição
+func mensagem(c *gin.Context, codigo codigoSucesso) string {
+	return mensagensSucesso.Texto(idioma.DaRequisicao(c), string(codigo))
+}
+
+// codigoTexto identifica um texto gerado pela API (nomes padrão, alertas) no catálogo de textos
+type codigoTexto string
+
+// Códigos dos textos gerados
+const (
+	txtSemCategoria        codigoTexto = "sem_categoria"        // linha do resumo dos gastos sem categoria
+	txtOrcamentoGeral      codigoTexto = "orcamento_geral"      // orçamento sem categoria, sobre todos os gastos
+	txtLancamentoImportado codigoTexto = "lancamento_importado" // nome da transação importada sem descrição
+	txtSaldoNegativo       codigoTexto = "saldo_negativo"       // args: mês, saldo previsto
+)
+
+// textosGerados é o catálogo dos textos gerados de cada idioma
+var textosGerados = idioma.Catalogo{
+	idioma.PortuguesBR: {
+		string(txtSemCategoria):        "Sem categoria",
+		string(txtOrcamentoGeral):      "Geral",
+		string(txtLancamentoImportado): "Lançamento importado",
+		string(txtSaldoNegativo):       "Saldo previsto negativo em %s: %s",
+	},
+
+	idioma.Ingles: {
+		string(txtSemCategoria):        "Uncategorized",
+		string(txtOrcamentoGeral):      "Overall",
+		string(txtLancamentoImportado): "Imported transaction",
+		string(txtSaldoNegativo):       "Projected balance is negative in %s: %s",
+	},
+
+	idioma.Espanhol: {
+		string(txtSemCategoria):        "Sin categoría",
+		string(txtOrcamentoGeral):      "General",
+		string(txtLancamentoImportado): "Movimiento importado",
+		string(txtSaldoNegativo):       "Saldo previsto negativo en %s: %s",
+	},
+}
+
+// textoGerado é o texto gerado no idioma i
+func textoGerado(i idioma.Idioma, codigo codigoTexto) string {
+	return textosGerados.Texto(i, string(codigo))
+}
@@ -0,0 +1,405 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jpeccia/quantogasto_app_server/database"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/idioma"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+// Quantidade de meses completos usados na média de sobra mensal da projeção das metas
+const mesesMediaSobra = 3
+
+// errMetaNaoEncontrada indica meta inexistente ou de outro usuário
+var errMetaNaoEncontrada = errors.New("meta não encontrada")
+
+// entradaMeta são os campos aceitos na criação e edição de metas
+type entradaMeta struct {
+	Nome             string          `json:"nome" binding:"required,max=100"`
+	ValorAlvo        models.Dinheiro `json:"valor_alvo" binding:"required"`
+	Prazo            string          `json:"prazo" binding:"required"`
+	DescontarDoSaldo *bool           `json:"descontar_do_saldo"` // padrão: true
+}
+
+// validarEntradaMeta lê e valida o JSON de uma meta, respondendo em caso de erro
+func validarEntradaMeta(c *gin.Context) (entradaMeta, bool) {
+	var input entradaMeta
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
+		return input, false
+	}
+	if input.ValorAlvo <= 0 {
+		erros.Responder(c, erros.NoCampo("valor_alvo", erros.CampoMaiorQueZero))
+		return input, false
+	}
+	if _, err := time.Parse(formatoData, input.Prazo); err != nil {
+		erros.Responder(c, erros.NoCampo("prazo", erros.CampoFormatoData))
+		return input, false
+	}
+	if input.DescontarDoSaldo == nil {
+		descontar := true
+		input.DescontarDoSaldo = &descontar
+	}
+	return input, true
+}
+
+// metaComAcumulado é a meta com o total já aportado
+type metaComAcumulado struct {
+	models.Meta
+	Acumulado  models.Dinheiro `json:"acumulado"`
+	Percentual float64         `json:"percentual"`
+}
+
+// calcularPercentual preenche o percentual concluído da meta, com uma casa decimal
+func (m *metaComAcumulado) calcularPercentual() {
+	m.Percentual = math.Round(float64(m.Acumulado)*1000/float64(m.ValorAlvo)) / 10
+}
+
+// carregarMeta busca uma meta do usuário com o total aportado
+func carregarMeta(ctx context.Context, db consultor, usuarioID, metaID int) (metaComAcumulado, error) {
+	var m metaComAcumulado
+	query := `
+        SELECT m.id, m.usuario_id, m.nome, m.valor_alvo, to_char(m.prazo, 'YYYY-MM-DD'), m.descontar_do_saldo, m.created_at,
+               COALESCE((SELECT SUM(valor) FROM aportes_meta WHERE meta_id = m.id), 0)
+        FROM metas m
+        WHERE m.id = $1 AND m.usuario_id = $2
+    `
+	err := db.QueryRow(ctx, query, metaID, usuarioID).Scan(&m.ID, &m.UsuarioID, &m.Nome, &m.ValorAlvo, &m.Prazo,
+		&m.DescontarDoSaldo, &m.CreatedAt, &m.Acumulado)
+	if err == pgx.ErrNoRows {
+		return m, errMetaNaoEncontrada
+	}
+	if err != nil {
+		return m, err
+	}
+	m.calcularPercentual()
+	return m, nil
+}
+
+// ListarMetas lista as metas do usuário com o total aportado e o percentual concluído
+func ListarMetas(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	query := `
+        SELECT m.id, m.usuario_id, m.nome, m.valor_alvo, to_char(m.prazo, 'YYYY-MM-DD'), m.descontar_do_saldo, m.created_at,
+               COALESCE((SELECT SUM(valor) FROM aportes_meta WHERE meta_id = m.id), 0)
+        FROM metas m
+        WHERE m.usuario_id = $1
+        ORDER BY m.prazo, m.id
+    `
+	rows, err := database.DB.Query(c.Request.Context(), query, usuarioID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar metas")
+		return
+	}
+	defer rows.Close()
+
+	metas := []metaComAcumulado{}
+	for rows.Next() {
+		var m metaComAcumulado
+		err := rows.Scan(&m.ID, &m.UsuarioID, &m.Nome, &m.ValorAlvo, &m.Prazo, &m.DescontarDoSaldo, &m.CreatedAt, &m.Acumulado)
+		if err != nil {
+			responderErroInterno(c, err, "Erro ao buscar metas")
+			return
+		}
+		m.calcularPercentual()
+		metas = append(metas, m)
+	}
+	if err := rows.Err(); err != nil {
+		responderErroInterno(c, err, "Erro ao buscar metas")
+		return
+	}
+
+	c.JSON(http.StatusOK, metas)
+}
+
+// CriarMeta cadastra uma meta de economia do usuário
+func CriarMeta(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	input, ok := validarEntradaMeta(c)
+	if !ok {
+		return
+	}
+
+	// Insere a meta no banco de dados
+	query := `
+        INSERT INTO metas (usuario_id, nome, valor_alvo, prazo, descontar_do_saldo)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id
+    `
+	var id int
+	err := database.DB.QueryRow(c.Request.Context(), query, usuarioID, input.Nome, input.ValorAlvo, input.Prazo,
+		*input.DescontarDoSaldo).Scan(&id)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao criar meta")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgMetaCriada), "id": id})
+}
+
+// EditarMeta atualiza uma meta do usuário
+func EditarMeta(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	metaID, ok := idDaURL(c, erros.MetaNaoEncontrada)
+	if !ok {
+		return
+	}
+
+	input, ok := validarEntradaMeta(c)
+	if !ok {
+		return
+	}
+
+	// Atualiza a meta no banco de dados
+	query := `
+        UPDATE metas
+        SET nome = $1, valor_alvo = $2, prazo = $3, descontar_do_saldo = $4
+        WHERE id = $5 AND usuario_id = $6
+    `
+	result, err := database.DB.Exec(c.Request.Context(), query, input.Nome, input.ValorAlvo, input.Prazo,
+		*input.DescontarDoSaldo, metaID, usuarioID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao atualizar a meta")
+		return
+	}
+
+	// Verifica se a meta foi encontrada e atualizada
+	if result.RowsAffected() == 0 {
+		erros.Responder(c, erros.Novo(erros.MetaNaoEncontrada))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgMetaAtualizada)})
+}
+
+// RemoverMeta remove uma meta do usuário junto com os aportes dela
+func RemoverMeta(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	metaID, ok := idDaURL(c, erros.MetaNaoEncontrada)
+	if !ok {
+		return
+	}
+
+	query := `DELETE FROM metas WHERE id = $1 AND usuario_id = $2`
+	result, err := database.DB.Exec(c.Request.Context(), query, metaID, usuarioID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao remover a meta")
+		return
+	}
+
+	// Verifica se a meta foi encontrada e removida
+	if result.RowsAffected() == 0 {
+		erros.Responder(c, erros.Novo(erros.MetaNaoEncontrada))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgMetaRemovida)})
+}
+
+// AdicionarAporte registra um valor guardado para uma meta do usuário
+func AdicionarAporte(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	metaID, ok := idDaURL(c, erros.MetaNaoEncontrada)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Valor models.Dinheiro `json:"valor" binding:"required"`
+		Data  string          `json:"data"` // padrão: hoje
+	}
+
+	// Valida o JSON recebido
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
+		return
+	}
+
+	// Verifica se o valor é positivo
+	if input.Valor <= 0 {
+		erros.Responder(c, erros.NoCampo("valor", erros.CampoMaiorQueZero))
+		return
+	}
+
+	if input.Data == "" {
+		input.Data = time.Now().Format(formatoData)
+	}
+	if _, err := time.Parse(formatoData, input.Data); err != nil {
+		erros.Responder(c, erros.NoCampo("data", erros.CampoFormatoData))
+		return
+	}
+
+	// Insere o aporte apenas se a meta for do usuário
+	query := `
+        INSERT INTO aportes_meta (meta_id, valor, data)
+        SELECT id, $1, $2 FROM metas WHERE id = $3 AND usuario_id = $4
+        RETURNING id
+    `
+	var id int
+	err := database.DB.QueryRow(c.Request.Context(), query, input.Valor, input.Data, metaID, usuarioID).Scan(&id)
+	if err == pgx.ErrNoRows {
+		erros.Responder(c, erros.Novo(erros.MetaNaoEncontrada))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao adicionar aporte")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgAporteAdicionado), "id": id})
+}
+
+// RemoverAporte remove um aporte de uma meta do usuário
+func RemoverAporte(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	metaID, ok := idDaURL(c, erros.AporteNaoEncontrado)
+	if !ok {
+		return
+	}
+	aporteID, ok := idDoParametro(c, "aporte_id", erros.AporteNaoEncontrado)
+	if !ok {
+		return
+	}
+
+	query := `
+        DELETE FROM aportes_meta a
+        USING metas m
+        WHERE a.id = $1 AND a.meta_id = $2 AND m.id = a.meta_id AND m.usuario_id = $3
+    `
+	result, err := database.DB.Exec(c.Request.Context(), query, aporteID, metaID, usuarioID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao remover o aporte")
+		return
+	}
+
+	// Verifica se o aporte foi encontrado e removido
+	if result.RowsAffected() == 0 {
+		erros.Responder(c, erros.Novo(erros.AporteNaoEncontrado))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgAporteRemovido)})
+}
+
+// progressoMeta é a situação atual da meta e a projeção de conclusão
+type progressoMeta struct {
+	metaComAcumulado
+	Faltante               models.Dinheiro     `json:"faltante"`
+	MesesRestantes         int                 `json:"meses_restantes"`          // meses até o prazo, contando o atual
+	AporteMensalNecessario models.Dinheiro     `json:"aporte_mensal_necessario"` // para concluir no prazo
+	SobraMensalMedia       models.Dinheiro     `json:"sobra_mensal_media"`       // renda menos gastos, média dos últimos meses
+	PrevisaoConclusao      *string             `json:"previsao_conclusao"`       // nil se a sobra média não for positiva
+	ConcluiNoPrazo         bool                `json:"conclui_no_prazo"`
+	Aportes                []models.AporteMeta `json:"aportes"`
+}
+
+// projetar calcula o aporte mensal necessário e a data prevista de conclusão,
+// supondo que toda a sobra mensal média seja guardada na meta
+func (p *progressoMeta) projetar(hoje time.Time) {
+	p.Faltante = p.ValorAlvo - p.Acumulado
+	if p.Faltante < 0 {
+		p.Faltante = 0
+	}
+
+	prazo, _ := time.Parse(formatoData, p.Prazo)
+	mesAtual := inicioDoMes(hoje)
+	if !prazo.Before(mesAtual) {
+		p.MesesRestantes = (prazo.Year()-mesAtual.Year())*12 + int(prazo.Month()-mesAtual.Month()) + 1
+	}
+
+	switch {
+	case p.Faltante == 0:
+		concluida := hoje.Format(formatoData)
+		p.PrevisaoConclusao = &concluida
+		p.ConcluiNoPrazo = true
+		return
+	case p.MesesRestantes == 0:
+		// Prazo vencido: o necessário é o que falta, de uma vez
+		p.AporteMensalNecessario = p.Faltante
+	default:
+		// A primeira parcela da divisão fica com os centavos que sobram, então cobre a meta
+		p.AporteMensalNecessario = p.Faltante.Dividir(p.MesesRestantes)[0]
+	}
+
+	if p.SobraMensalMedia <= 0 {
+		return
+	}
+	meses := int((p.Faltante + p.SobraMensalMedia - 1) / p.SobraMensalMedia)
+	previsao := adicionarMeses(hoje, meses)
+	texto := previsao.Format(formatoData)
+	p.PrevisaoConclusao = &texto
+	p.ConcluiNoPrazo = !previsao.After(prazo)
+}
+
+// ObterProgressoMeta retorna o percentual concluído da meta, o aporte mensal necessário para
+// cumprir o prazo e a data prevista de conclusão pela sobra mensal média do usuário
+func (h *Handler) ObterProgressoMeta(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	metaID, ok := idDaURL(c, erros.MetaNaoEncontrada)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	meta, err := carregarMeta(ctx, database.DB, usuarioID, metaID)
+	if errors.Is(err, errMetaNaoEncontrada) {
+		erros.Responder(c, erros.Novo(erros.MetaNaoEncontrada))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar a meta")
+		return
+	}
+
+	progresso := progressoMeta{metaComAcumulado: meta, Aportes: []models.AporteMeta{}}
+
+	// Lista os aportes da meta
+	query := `
+        SELECT id, meta_id, valor, to_char(data, 'YYYY-MM-DD'), created_at
+        FROM aportes_meta WHERE meta_id = $1
+        ORDER BY data, id
+    `
+	rows, err := database.DB.Query(ctx, query, meta.ID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar os aportes")
+		return
+	}
+	for rows.Next() {
+		var a models.AporteMeta
+		if err := rows.Scan(&a.ID, &a.MetaID, &a.Valor, &a.Data, &a.CreatedAt); err != nil {
+			rows.Close()
+			responderErroInterno(c, err, "Erro ao buscar os aportes")
+			return
+		}
+		progresso.Aportes = append(progresso.Aportes, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		responderErroInterno(c, err, "Erro ao buscar os aportes")
+		return
+	}
+
+	// Sobra mensal média dos últimos meses completos (antes dos aportes em metas)
+	hoje := time.Now().UTC()
+	fim := inicioDoMes(hoje)
+	resumo, err := h.calcularResumo(ctx, idioma.DaRequisicao(c), usuarioID, periodo{Inicio: fim.AddDate(0, -mesesMediaSobra, 0), Fim: fim})
+	if err != nil {
+		responderErroResumo(c, err)
+		return
+	}
+	sobra := resumo.RendaTotal - resumo.GastosFixosTotal - resumo.GastosVariaveisTotal
+	progresso.SobraMensalMedia = sobra.Dividir(mesesMediaSobra)[mesesMediaSobra-1]
+
+	progresso.projetar(time.Date(hoje.Year(), hoje.Month(), hoje.Day(), 0, 0, 0, 0, time.UTC))
+	c.JSON(http.StatusOK, progresso)
+}
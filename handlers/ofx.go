@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/idioma"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+// Limite de tamanho do nome de um lançamento importado
+const tamanhoMaximoNomeImportado = 200
+
+// transacaoOFX são os campos de um <STMTTRN> usados na importação
+type transacaoOFX struct {
+	Linha    int // linha do <STMTTRN> no arquivo
+	FITID    string
+	DTPOSTED string
+	TRNAMT   string
+	NAME     string
+	MEMO     string
+}
+
+// extratoOFX é o resultado da leitura de um arquivo OFX
+type extratoOFX struct {
+	Conta      string // ACCTID da conta ou do cartão no banco
+	Transacoes []transacaoOFX
+}
+
+// converterLatin1 converte para UTF-8 arquivos gravados em ISO-8859-1/Windows-1252,
+// comuns nos extratos OFX 1.x dos bancos brasileiros
+func converterLatin1(conteudo []byte) string {
+	if utf8.Valid(conteudo) {
+		return string(conteudo)
+	}
+	var b strings.Builder
+	b.Grow(len(conteudo) * 2)
+	for _, c := range conteudo {
+		b.WriteRune(rune(c))
+	}
+	return b.String()
+}
+
+// lerOFX interpreta extratos OFX 1.x (SGML, sem tags de fechamento nos campos) e 2.x (XML).
+// Os dois formatos são lidos como uma sequência de tags: o texto após uma tag de abertura
+// é o valor do campo, com ou sem a tag de fechamento correspondente.
+func lerOFX(conteudo []byte) (extratoOFX, error) {
+	var extrato extratoOFX
+
+	texto := converterLatin1(conteudo)
+	inicio := strings.Index(strings.ToUpper(texto), "<OFX>")
+	if inicio < 0 {
+		return extrato, erros.Novo(erros.OFXInvalido)
+	}
+	linha := 1 + strings.Count(texto[:inicio], "\n")
+	texto = texto[inicio:]
+
+	var atual *transacaoOFX
+	for len(texto) > 0 {
+		abre := strings.IndexByte(texto, '<')
+		if abre < 0 {
+			break
+		}
+		fecha := strings.IndexByte(texto[abre:], '>')
+		if fecha < 0 {
+			break
+		}
+		linhaTag := linha + strings.Count(texto[:abre], "\n")
+		linha += strings.Count(texto[:abre+fecha+1], "\n")
+		tag := strings.ToUpper(strings.TrimSpace(texto[abre+1 : abre+fecha]))
+		texto = texto[abre+fecha+1:]
+
+		// Valor do campo: o texto até a próxima tag
+		proxima := strings.IndexByte(texto, '<')
+		if proxima < 0 {
+			proxima = len(texto)
+		}
+		valor := strings.TrimSpace(html.UnescapeString(texto[:proxima]))
+
+		switch tag {
+		case "STMTTRN":
+			atual = &transacaoOFX{Linha: linhaTag}
+		case "/STMTTRN":
+			if atual != nil {
+				extrato.Transacoes = append(extrato.Transacoes, *atual)
+				atual = nil
+			}
+		case "ACCTID":
+			if extrato.Conta == "" {
+				extrato.Conta = valor
+			}
+		case "FITID", "DTPOSTED", "TRNAMT", "NAME", "MEMO":
+			if atual == nil {
+				continue
+			}
+			switch tag {
+			case "FITID":
+				atual.FITID = valor
+			case "DTPOSTED":
+				atual.DTPOSTED = valor
+			case "TRNAMT":
+				atual.TRNAMT = valor
+			case "NAME":
+				atual.NAME = valor
+			case "MEMO":
+				atual.MEMO = valor
+			}
+		}
+	}
+
+	return extrato, nil
+}
+
+// lancamento converte a transação em um lançamento: débitos viram gastos variáveis e créditos, rendas.
+// Sem FITID, o identificador é montado com data, valor e descrição; sem descrição, o nome é
+// o texto padrão no idioma i. Data ou valor inválidos retornam o erro no campo do OFX
+// (DTPOSTED ou TRNAMT).
+func (t transacaoOFX) lancamento(conta string, i idioma.Idioma) (models.LancamentoImportado, error) {
+	var l models.LancamentoImportado
+
+	// DTPOSTED: AAAAMMDD[HHMMSS[.XXX]][[-3:BRT]]; só a data interessa
+	if len(t.DTPOSTED) < 8 {
+		return l, erros.NoCampo("DTPOSTED", erros.CampoFormato, "AAAAMMDD")
+	}
+	data, err := time.Parse("20060102", t.DTPOSTED[:8])
+	if err != nil {
+		return l, erros.NoCampo("DTPOSTED", erros.CampoFormato, "AAAAMMDD")
+	}
+
+	valor, err := models.ParseDinheiro(t.TRNAMT)
+	if err != nil {
+		return l, erros.NoCampo("TRNAMT", codigoErroValor(err))
+	}
+
+	l.Data = data.Format(formatoData)
+	l.Tipo = models.LancamentoRenda
+	l.Valor = valor
+	if valor < 0 {
+		l.Tipo = models.LancamentoGastoVariavel
+		l.Valor = -valor
+	}
+
+	l.Nome = t.NAME
+	if l.Nome == "" {
+		l.Nome = t.MEMO
+	}
+	if utf8.RuneCountInString(l.Nome) > tamanhoMaximoNomeImportado {
+		l.Nome = string([]rune(l.Nome)[:tamanhoMaximoNomeImportado])
+	}
+
+	// O identificador não depende do idioma: usa a descrição do extrato, mesmo vazia
+	fitid := t.FITID
+	if fitid == "" {
+		fitid = fmt.Sprintf("%s|%s|%s", l.Data, valor, l.Nome)
+	}
+	if l.Nome == "" {
+		l.Nome = textoGerado(i, txtLancamentoImportado)
+	}
+	l.IDExterno = conta + ":" + fitid
+	return l, nil
+}
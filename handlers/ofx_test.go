@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/idioma"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+const cabecalhoOFXSGML = "OFXHEADER:100\nDATA:OFXSGML\nVERSION:102\nCHARSET:1252\n\n"
+
+func TestLerOFX(t *testing.T) {
+	casos := []struct {
+		nome       string
+		conteudo   []byte
+		conta      string
+		transacoes []transacaoOFX
+	}{
+		{
+			nome: "SGML sem tags de fechamento nos campos",
+			conteudo: []byte(cabecalhoOFXSGML + "<OFX>\n<BANKACCTFROM>\n<ACCTID>12345-6\n</BANKACCTFROM>\n" +
+				"<STMTTRN>\n<TRNTYPE>DEBIT\n<DTPOSTED>20250310120000[-3:BRT]\n<TRNAMT>-45.90\n<FITID>A1\n<MEMO>PADARIA\n</STMTTRN>\n" +
+				"<STMTTRN>\n<DTPOSTED>20250311\n<TRNAMT>1500.00\n<FITID>A2\n<NAME>SALARIO\n</STMTTRN>\n</OFX>"),
+			conta: "12345-6",
+			transacoes: []transacaoOFX{
+				{Linha: 10, FITID: "A1", DTPOSTED: "20250310120000[-3:BRT]", TRNAMT: "-45.90", MEMO: "PADARIA"},
+				{Linha: 17, FITID: "A2", DTPOSTED: "20250311", TRNAMT: "1500.00", NAME: "SALARIO"},
+			},
+		},
+		{
+			nome: "XML com tags de fechamento e entidades",
+			conteudo: []byte(`<?xml version="1.0"?><OFX><CCACCTFROM><ACCTID>9999</ACCTID></CCACCTFROM>` +
+				`<STMTTRN><DTPOSTED>20250101</DTPOSTED><TRNAMT>-10</TRNAMT><FITID>X</FITID><NAME>A &amp; B</NAME></STMTTRN></OFX>`),
+			conta: "9999",
+			transacoes: []transacaoOFX{
+				{Linha: 1, FITID: "X", DTPOSTED: "20250101", TRNAMT: "-10", NAME: "A & B"},
+			},
+		},
+		{
+			nome: "Latin-1 convertido para UTF-8",
+			conteudo: append([]byte(cabecalhoOFXSGML+"<OFX>\n<STMTTRN>\n<DTPOSTED>20250105\n<TRNAMT>-8,50\n<NAME>A\xc7OUGUE S\xc3O JO\xc3O\n"),
+				[]byte("</STMTTRN>\n</OFX>")...),
+			transacoes: []transacaoOFX{
+				{Linha: 7, DTPOSTED: "20250105", TRNAMT: "-8,50", NAME: "AÇOUGUE SÃO JOÃO"},
+			},
+		},
+		{
+			nome:     "campos fora de uma transação são ignorados",
+			conteudo: []byte("<OFX><DTPOSTED>20250101<TRNAMT>5<STMTTRN><TRNAMT>1</STMTTRN></OFX>"),
+			transacoes: []transacaoOFX{
+				{Linha: 1, TRNAMT: "1"},
+			},
+		},
+		{
+			nome:     "transação sem fechamento não é lida",
+			conteudo: []byte("<OFX><STMTTRN><TRNAMT>1</OFX>"),
+		},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			extrato, err := lerOFX(caso.conteudo)
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if extrato.Conta != caso.conta {
+				t.Errorf("conta = %q, esperado %q", extrato.Conta, caso.conta)
+			}
+			if !reflect.DeepEqual(extrato.Transacoes, caso.transacoes) {
+				t.Errorf("transações = %+v, esperado %+v", extrato.Transacoes, caso.transacoes)
+			}
+		})
+	}
+}
+
+func TestLerOFXSemTagOFX(t *testing.T) {
+	_, err := lerOFX([]byte("data;valor\n2025-01-01;10"))
+	var e *erros.Erro
+	if !errors.As(err, &e) || e.Codigo != erros.OFXInvalido {
+		t.Errorf("esperado erro %s, recebido %v", erros.OFXInvalido, err)
+	}
+}
+
+func TestTransacaoOFXLancamento(t *testing.T) {
+	casos := []struct {
+		nome      string
+		transacao transacaoOFX
+		esperado  models.LancamentoImportado
+		campo     string // campo do erro esperado; vazio se válida
+		codigo    erros.CodigoCampo
+	}{
+		{
+			nome:      "débito vira gasto variável",
+			transacao: transacaoOFX{FITID: "A1", DTPOSTED: "20250310120000[-3:BRT]", TRNAMT: "-45.90", MEMO: "PADARIA"},
+			esperado:  models.LancamentoImportado{IDExterno: "c:A1", Tipo: models.LancamentoGastoVariavel, Data: "2025-03-10", Nome: "PADARIA", Valor: 4590},
+		},
+		{
+			nome:      "crédito vira renda",
+			transacao: transacaoOFX{FITID: "A2", DTPOSTED: "20250311", TRNAMT: "1.500,00", NAME: "SALARIO", MEMO: "ignorado"},
+			esperado:  models.LancamentoImportado{IDExterno: "c:A2", Tipo: models.LancamentoRenda, Data: "2025-03-11", Nome: "SALARIO", Valor: 150000},
+		},
+		{
+			nome:      "sem FITID usa data, valor e descrição",
+			transacao: transacaoOFX{DTPOSTED: "20250311", TRNAMT: "-1", NAME: "X"},
+			esperado:  models.LancamentoImportado{IDExterno: "c:2025-03-11|-1.00|X", Tipo: models.LancamentoGastoVariavel, Data: "2025-03-11", Nome: "X", Valor: 100},
+		},
+		{nome: "data curta", transacao: transacaoOFX{DTPOSTED: "202503", TRNAMT: "1"}, campo: "DTPOSTED", codigo: erros.CampoFormato},
+		{nome: "data inexistente", transacao: transacaoOFX{DTPOSTED: "20250231", TRNAMT: "1"}, campo: "DTPOSTED", codigo: erros.CampoFormato},
+		{nome: "valor inválido", transacao: transacaoOFX{DTPOSTED: "20250311", TRNAMT: "abc"}, campo: "TRNAMT", codigo: erros.CampoNumero},
+		{nome: "valor com frações de centavo", transacao: transacaoOFX{DTPOSTED: "20250311", TRNAMT: "-1.005"}, campo: "TRNAMT", codigo: erros.CampoCasasDecimais},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			l, err := caso.transacao.lancamento("c", idioma.PortuguesBR)
+			if caso.campo == "" {
+				if err != nil {
+					t.Fatalf("erro inesperado: %v", err)
+				}
+				if !reflect.DeepEqual(l, caso.esperado) {
+					t.Errorf("lançamento = %+v, esperado %+v", l, caso.esperado)
+				}
+				return
+			}
+			var e *erros.Erro
+			if !errors.As(err, &e) || len(e.Campos) != 1 {
+				t.Fatalf("esperado erro no campo %q, recebido %v", caso.campo, err)
+			}
+			if e.Campos[0].Campo != caso.campo || e.Campos[0].Codigo != caso.codigo {
+				t.Errorf("erro em %q (%s), esperado %q (%s)", e.Campos[0].Campo, e.Campos[0].Codigo, caso.campo, caso.codigo)
+			}
+		})
+	}
+}
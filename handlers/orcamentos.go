@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpeccia/quantogasto_app_server/database"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/idioma"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+// Percentual do limite a partir do qual o orçamento é considerado próximo do limite
+const percentualAlertaOrcamento = 80
+
+// Situações possíveis de um orçamento no mês
+const (
+	statusOrcamentoDentro  = "dentro_do_limite"
+	statusOrcamentoProximo = "proximo_do_limite"
+	statusOrcamentoAcima   = "acima_do_limite"
+)
+
+// situacaoOrcamento é o acompanhamento de um orçamento em um mês
+type situacaoOrcamento struct {
+	ID              int             `json:"id"`
+	CategoriaID     *int            `json:"categoria_id"` // nil para o orçamento geral
+	Nome            string          `json:"nome"`
+	LimiteDaRenda   bool            `json:"limite_da_renda"` // limite derivado da renda do mês
+	Planejado       models.Dinheiro `json:"planejado"`
+	Gasto           models.Dinheiro `json:"gasto"`
+	Restante        models.Dinheiro `json:"restante"`
+	PercentualUsado *float64        `json:"percentual_usado"` // nil quando o planejado é zero
+	Status          string          `json:"status"`
+}
+
+// calcularSituacao preenche restante, percentual e status a partir do planejado e do gasto
+func (s *situacaoOrcamento) calcularSituacao() {
+	s.Restante = s.Planejado - s.Gasto
+
+	if s.Planejado <= 0 {
+		s.Status = statusOrcamentoDentro
+		if s.Gasto > 0 {
+			s.Status = statusOrcamentoAcima
+		}
+		return
+	}
+
+	percentual := math.Round(float64(s.Gasto)*1000/float64(s.Planejado)) / 10
+	s.PercentualUsado = &percentual
+	switch {
+	case s.Gasto > s.Planejado:
+		s.Status = statusOrcamentoAcima
+	case percentual >= percentualAlertaOrcamento:
+		s.Status = statusOrcamentoProximo
+	default:
+		s.Status = statusOrcamentoDentro
+	}
+}
+
+// ObterOrcamentos compara, para o mês (?mes=YYYY-MM), o planejado com o gasto de cada orçamento.
+// O gasto vem do mesmo cálculo usado em ObterResumo.
+func (h *Handler) ObterOrcamentos(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	p, err := interpretarMes(c)
+	if err != nil {
+		erros.Responder(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	idiomaNomes := idioma.DaRequisicao(c)
+	query := `
+        SELECT o.id, o.categoria_id, cat.nome, o.valor
+        FROM orcamentos o
+        LEFT JOIN categorias cat ON cat.id = o.categoria_id
+        WHERE o.usuario_id = $1
+        ORDER BY o.categoria_id NULLS FIRST, cat.nome
+    `
+	rows, err := database.DB.Query(ctx, query, usuarioID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar orçamentos")
+		return
+	}
+	defer rows.Close()
+
+	situacoes := []situacaoOrcamento{}
+	for rows.Next() {
+		var s situacaoOrcamento
+		var nome *string
+		var valor *models.Dinheiro
+		if err := rows.Scan(&s.ID, &s.CategoriaID, &nome, &valor); err != nil {
+			responderErroInterno(c, err, "Erro ao buscar orçamentos")
+			return
+		}
+		// Sem categoria, o orçamento vale para todos os gastos do mês
+		s.Nome = textoGerado(idiomaNomes, txtOrcamentoGeral)
+		if nome != nil {
+			s.Nome = *nome
+		}
+		if valor != nil {
+			s.Planejado = *valor
+		} else {
+			s.LimiteDaRenda = true
+		}
+		situacoes = append(situacoes, s)
+	}
+	if err := rows.Err(); err != nil {
+		responderErroInterno(c, err, "Erro ao buscar orçamentos")
+		return
+	}
+
+	// Calcula o gasto do mês com os mesmos números do resumo
+	resumo, err := h.calcularResumo(ctx, idiomaNomes, usuarioID, p)
+	if err != nil {
+		responderErroResumo(c, err)
+		return
+	}
+	gastoPorCategoria := map[int]models.Dinheiro{}
+	for _, l := range resumo.PorCategoria {
+		if l.CategoriaID != nil {
+			gastoPorCategoria[*l.CategoriaID] = l.Total
+		}
+	}
+
+	for i := range situacoes {
+		s := &situacoes[i]
+		if s.CategoriaID == nil {
+			s.Gasto = resumo.GastosFixosTotal + resumo.GastosVariaveisTotal
+			if s.LimiteDaRenda {
+				s.Planejado = resumo.RendaTotal
+			}
+		} else {
+			s.Gasto = gastoPorCategoria[*s.CategoriaID]
+		}
+		s.calcularSituacao()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"periodo":    p.JSON(),
+		"orcamentos": situacoes,
+	})
+}
+
+// DefinirOrcamento cria ou atualiza o orçamento mensal de uma categoria ou o orçamento geral
+func (h *Handler) DefinirOrcamento(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	var input struct {
+		CategoriaID *int             `json:"categoria_id"` // ausente para o orçamento geral
+		Valor       *models.Dinheiro `json:"valor"`        // ausente no geral para usar a renda do mês
+	}
+
+	// Valida o JSON recebido
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
+		return
+	}
+
+	// O valor é obrigatório para categorias e, quando informado, deve ser positivo
+	if input.Valor == nil && input.CategoriaID != nil {
+		erros.Responder(c, erros.NoCampo("valor", erros.CampoObrigatorio))
+		return
+	}
+	if input.Valor != nil && *input.Valor <= 0 {
+		erros.Responder(c, erros.NoCampo("valor", erros.CampoMaiorQueZero))
+		return
+	}
+
+	// Verifica se a categoria pode ser usada pelo usuário
+	if !h.validarCategoriaDoGasto(c, usuarioID, input.CategoriaID) {
+		return
+	}
+
+	// Cria ou substitui o orçamento da categoria (ou o geral)
+	query := `
+        INSERT INTO orcamentos (usuario_id, categoria_id, valor)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (usuario_id, (COALESCE(categoria_id, 0))) DO UPDATE SET valor = EXCLUDED.valor
+        RETURNING id, usuario_id, categoria_id, valor, created_at
+    `
+	var o models.Orcamento
+	err := database.DB.QueryRow(c.Request.Context(), query, usuarioID, input.CategoriaID, input.Valor).
+		Scan(&o.ID, &o.UsuarioID, &o.CategoriaID, &o.Valor, &o.CreatedAt)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao definir orçamento")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgOrcamentoDefinido), "id": o.ID, "orcamento": o})
+}
+
+// RemoverOrcamento remove um orçamento do usuário
+func RemoverOrcamento(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	orcamentoID, ok := idDaURL(c, erros.OrcamentoNaoEncontrado)
+	if !ok {
+		return
+	}
+
+	query := `DELETE FROM orcamentos WHERE id = $1 AND usuario_id = $2`
+	result, err := database.DB.Exec(c.Request.Context(), query, orcamentoID, usuarioID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao remover o orçamento")
+		return
+	}
+
+	// Verifica se o orçamento foi encontrado e removido
+	if result.RowsAffected() == 0 {
+		erros.Responder(c, erros.Novo(erros.OrcamentoNaoEncontrado))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgOrcamentoRemovido)})
+}
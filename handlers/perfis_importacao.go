@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jpeccia/quantogasto_app_server/database"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+var errPerfilImportacaoNaoEncontrado = errors.New("perfil de importação não encontrado")
+
+// Formatos de data aceitos nos perfis de importação e o layout correspondente
+var formatosDataImportacao = map[string]string{
+	"DD/MM/AAAA": "02/01/2006",
+	"DD/MM/AA":   "02/01/06",
+	"DD-MM-AAAA": "02-01-2006",
+	"AAAA-MM-DD": "2006-01-02",
+	"MM/DD/AAAA": "01/02/2006",
+}
+
+// Delimitadores de campo aceitos nos perfis de importação
+var delimitadoresImportacao = map[string]bool{",": true, ";": true, "|": true, "\t": true}
+
+// Quantidade máxima de linhas descartadas antes do cabeçalho
+const maximoLinhasIgnoradas = 50
+
+// entradaPerfilImportacao são os campos aceitos na criação e edição de perfis de importação
+type entradaPerfilImportacao struct {
+	Nome                string  `json:"nome" binding:"required,max=50"`
+	Delimitador         string  `json:"delimitador"` // padrão: ,
+	LinhasIgnoradas     int     `json:"linhas_ignoradas"`
+	TemCabecalho        *bool   `json:"tem_cabecalho"` // padrão: true
+	ColunaData          string  `json:"coluna_data" binding:"required"`
+	FormatoData         string  `json:"formato_data" binding:"required"`
+	ColunaDescricao     string  `json:"coluna_descricao" binding:"required"`
+	ColunaValor         string  `json:"coluna_valor" binding:"required"`
+	ColunaIdentificador *string `json:"coluna_identificador"`
+	SeparadorDecimal    string  `json:"separador_decimal" binding:"required"`
+	ConvencaoSinal      string  `json:"convencao_sinal"` // padrão: debito_negativo
+}
+
+// validarEntradaPerfilImportacao lê e valida o JSON de um perfil de importação, respondendo em caso de erro
+func validarEntradaPerfilImportacao(c *gin.Context) (entradaPerfilImportacao, bool) {
+	var input entradaPerfilImportacao
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
+		return input, false
+	}
+
+	if input.Delimitador == "" {
+		input.Delimitador = ","
+	}
+	if !delimitadoresImportacao[input.Delimitador] {
+		// A tabulação aparece como \t na mensagem
+		erros.Responder(c, erros.NoCampo("delimitador", erros.CampoOpcao, []string{",", ";", "|", `\t`}))
+		return input, false
+	}
+	if input.LinhasIgnoradas < 0 || input.LinhasIgnoradas > maximoLinhasIgnoradas {
+		erros.Responder(c, erros.NoCampo("linhas_ignoradas", erros.CampoIntervalo, 0, maximoLinhasIgnoradas))
+		return input, false
+	}
+	if input.TemCabecalho == nil {
+		temCabecalho := true
+		input.TemCabecalho = &temCabecalho
+	}
+	if _, ok := formatosDataImportacao[input.FormatoData]; !ok {
+		erros.Responder(c, erros.NoCampo("formato_data", erros.CampoOpcao, []string{
+			"DD/MM/AAAA", "DD/MM/AA", "DD-MM-AAAA", "AAAA-MM-DD", "MM/DD/AAAA",
+		}))
+		return input, false
+	}
+	if input.SeparadorDecimal != "," && input.SeparadorDecimal != "." {
+		erros.Responder(c, erros.NoCampo("separador_decimal", erros.CampoOpcao, []string{",", "."}))
+		return input, false
+	}
+	if input.ConvencaoSinal == "" {
+		input.ConvencaoSinal = models.SinalDebitoNegativo
+	}
+	if input.ConvencaoSinal != models.SinalDebitoNegativo && input.ConvencaoSinal != models.SinalDebitoPositivo {
+		erros.Responder(c, erros.NoCampo("convencao_sinal", erros.CampoOpcao, []string{
+			models.SinalDebitoNegativo, models.SinalDebitoPositivo,
+		}))
+		return input, false
+	}
+	if input.ColunaIdentificador != nil && *input.ColunaIdentificador == "" {
+		input.ColunaIdentificador = nil
+	}
+	return input, true
+}
+
+// colunasPerfilImportacao são as colunas lidas de perfis_importacao, na ordem de escanearPerfilImportacao
+const colunasPerfilImportacao = `id, usuario_id, nome, delimitador, linhas_ignoradas, tem_cabecalho, coluna_data, formato_data,
+        coluna_descricao, coluna_valor, coluna_identificador, separador_decimal, convencao_sinal, created_at`
+
+// escanearPerfilImportacao lê uma linha com as colunasPerfilImportacao
+func escanearPerfilImportacao(row pgx.Row, p *models.PerfilImportacao) error {
+	return row.Scan(&p.ID, &p.UsuarioID, &p.Nome, &p.Delimitador, &p.LinhasIgnoradas, &p.TemCabecalho,
+		&p.ColunaData, &p.FormatoData, &p.ColunaDescricao, &p.ColunaValor, &p.ColunaIdentificador,
+		&p.SeparadorDecimal, &p.ConvencaoSinal, &p.CreatedAt)
+}
+
+// carregarPerfilImportacao busca um perfil de importação do usuário
+func carregarPerfilImportacao(ctx context.Context, db consultor, usuarioID int, perfilID any) (models.PerfilImportacao, error) {
+	var perfil models.PerfilImportacao
+	query := `SELECT ` + colunasPerfilImportacao + ` FROM perfis_importacao WHERE id = $1 AND usuario_id = $2`
+	err := escanearPerfilImportacao(db.QueryRow(ctx, query, perfilID, usuarioID), &perfil)
+	if err == pgx.ErrNoRows {
+		return perfil, errPerfilImportacaoNaoEncontrado
+	}
+	return perfil, err
+}
+
+// ListarPerfisImportacao lista os perfis de importação CSV do usuário
+func ListarPerfisImportacao(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	query := `SELECT ` + colunasPerfilImportacao + ` FROM perfis_importacao WHERE usuario_id = $1 ORDER BY nome`
+	rows, err := database.DB.Query(c.Request.Context(), query, usuarioID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar perfis de importação")
+		return
+	}
+	defer rows.Close()
+
+	perfis := []models.PerfilImportacao{}
+	for rows.Next() {
+		var perfil models.PerfilImportacao
+		if err := escanearPerfilImportacao(rows, &perfil); err != nil {
+			responderErroInterno(c, err, "Erro ao buscar perfis de importação")
+			return
+		}
+		perfis = append(perfis, perfil)
+	}
+	if err := rows.Err(); err != nil {
+		responderErroInterno(c, err, "Erro ao buscar perfis de importação")
+		return
+	}
+
+	c.JSON(http.StatusOK, perfis)
+}
+
+// CriarPerfilImportacao salva um mapeamento de colunas CSV com um nome
+func CriarPerfilImportacao(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	input, ok := validarEntradaPerfilImportacao(c)
+	if !ok {
+		return
+	}
+
+	// Insere o perfil no banco de dados
+	query := `
+        INSERT INTO perfis_importacao (usuario_id, nome, delimitador, linhas_ignoradas, tem_cabecalho, coluna_data,
+            formato_data, coluna_descricao, coluna_valor, coluna_identificador, separador_decimal, convencao_sinal)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+        RETURNING id
+    `
+	var id int
+	err := database.DB.QueryRow(c.Request.Context(), query, usuarioID, input.Nome, input.Delimitador, input.LinhasIgnoradas,
+		*input.TemCabecalho, input.ColunaData, input.FormatoData, input.ColunaDescricao, input.ColunaValor,
+		input.ColunaIdentificador, input.SeparadorDecimal, input.ConvencaoSinal).Scan(&id)
+	if err != nil {
+		if database.ErroViolacaoUnica(err) {
+			erros.Responder(c, erros.Novo(erros.PerfilImportacaoDuplicado))
+			return
+		}
+		responderErroInterno(c, err, "Erro ao criar perfil de importação")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgPerfilImportacaoCriado), "id": id})
+}
+
+// EditarPerfilImportacao atualiza um perfil de importação do usuário
+func EditarPerfilImportacao(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	perfilID, ok := idDaURL(c, erros.PerfilImportacaoNaoEncontrado)
+	if !ok {
+		return
+	}
+
+	input, ok := validarEntradaPerfilImportacao(c)
+	if !ok {
+		return
+	}
+
+	// Atualiza o perfil no banco de dados
+	query := `
+        UPDATE perfis_importacao
+        SET nome = $1, delimitador = $2, linhas_ignoradas = $3, tem_cabecalho = $4, coluna_data = $5, formato_data = $6,
+            coluna_descricao = $7, coluna_valor = $8, coluna_identificador = $9, separador_decimal = $10, convencao_sinal = $11
+        WHERE id = $12 AND usuario_id = $13
+    `
+	result, err := database.DB.Exec(c.Request.Context(), query, input.Nome, input.Delimitador, input.LinhasIgnoradas,
+		*input.TemCabecalho, input.ColunaData, input.FormatoData, input.ColunaDescricao, input.ColunaValor,
+		input.ColunaIdentificador, input.SeparadorDecimal, input.ConvencaoSinal, perfilID, usuarioID)
+	if err != nil {
+		if database.ErroViolacaoUnica(err) {
+			erros.Responder(c, erros.Novo(erros.PerfilImportacaoDuplicado))
+			return
+		}
+		responderErroInterno(c, err, "Erro ao atualizar o perfil de importação")
+		return
+	}
+
+	// Verifica se o perfil foi encontrado e atualizado
+	if result.RowsAffected() == 0 {
+		erros.Responder(c, erros.Novo(erros.PerfilImportacaoNaoEncontrado))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgPerfilImportacaoAtualizado)})
+}
+
+// RemoverPerfilImportacao remove um perfil de importação do usuário
+func RemoverPerfilImportacao(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	perfilID, ok := idDaURL(c, erros.PerfilImportacaoNaoEncontrado)
+	if !ok {
+		return
+	}
+
+	query := `DELETE FROM perfis_importacao WHERE id = $1 AND usuario_id = $2`
+	result, err := database.DB.Exec(c.Request.Context(), query, perfilID, usuarioID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao remover o perfil de importação")
+		return
+	}
+
+	// Verifica se o perfil foi encontrado e removido
+	if result.RowsAffected() == 0 {
+		erros.Responder(c, erros.Novo(erros.PerfilImportacaoNaoEncontrado))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgPerfilImportacaoRemovido)})
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+)
+
+// Formatos aceitos nos parâmetros de período
+const (
+	formatoData = "2006-01-02"
+	formatoMes  = "2006-01"
+)
+
+// periodo é um intervalo de datas [Inicio, Fim), sempre em dias inteiros (UTC)
+type periodo struct {
+	Inicio time.Time
+	Fim    time.Time // exclusivo
+}
+
+// periodoJSON é a representação do período nas respostas, com a data final inclusiva
+type periodoJSON struct {
+	De  string `json:"de"`
+	Ate string `json:"ate"`
+}
+
+// JSON retorna o período no formato usado nas respostas
+func (p periodo) JSON() periodoJSON {
+	return periodoJSON{
+		De:  p.Inicio.Format(formatoData),
+		Ate: p.Fim.AddDate(0, 0, -1).Format(formatoData),
+	}
+}
+
+// inicioDoMes retorna o primeiro dia do mês da data
+func inicioDoMes(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// periodoDoMes retorna o período que cobre o mês inteiro da data
+func periodoDoMes(t time.Time) periodo {
+	inicio := inicioDoMes(t)
+	return periodo{Inicio: inicio, Fim: inicio.AddDate(0, 1, 0)}
+}
+
+// mesesCompletos indica se o período começa e termina em viradas de mês, e quantos meses cobre
+func (p periodo) mesesCompletos() (int, bool) {
+	if p.Inicio.Day() != 1 || p.Fim.Day() != 1 {
+		return 0, false
+	}
+	return (p.Fim.Year()-p.Inicio.Year())*12 + int(p.Fim.Month()-p.Inicio.Month()), true
+}
+
+// Meses retorna o primeiro dia de cada mês tocado pelo período
+func (p periodo) Meses() []time.Time {
+	var meses []time.Time
+	for m := inicioDoMes(p.Inicio); m.Before(p.Fim); m = m.AddDate(0, 1, 0) {
+		meses = append(meses, m)
+	}
+	return meses
+}
+
+// Anterior retorna o período imediatamente anterior com a mesma duração
+// (em meses, quando o período é formado por meses completos; em dias, caso contrário)
+func (p periodo) Anterior() periodo {
+	if meses, ok := p.mesesCompletos(); ok {
+		return periodo{Inicio: p.Inicio.AddDate(0, -meses, 0), Fim: p.Inicio}
+	}
+	dias := int(p.Fim.Sub(p.Inicio).Hours() / 24)
+	return periodo{Inicio: p.Inicio.AddDate(0, 0, -dias), Fim: p.Inicio}
+}
+
+// interpretarLimite lê uma data YYYY-MM-DD ou um mês YYYY-MM. Para o limite final,
+// um mês significa o mês inteiro; o retorno é sempre o primeiro instante fora do intervalo.
+func interpretarLimite(valor string, final bool) (time.Time, error) {
+	if t, err := time.Parse(formatoData, valor); err == nil {
+		if final {
+			return t.AddDate(0, 0, 1), nil
+		}
+		return t, nil
+	}
+	if t, err := time.Parse(formatoMes, valor); err == nil {
+		if final {
+			return t.AddDate(0, 1, 0), nil
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("data inválida: %s", valor)
+}
+
+// interpretarPeriodo lê ?mes=YYYY-MM ou ?de=&ate= (YYYY-MM ou YYYY-MM-DD).
+// Sem parâmetros, o período é o mês atual.
+func interpretarPeriodo(c *gin.Context) (periodo, error) {
+	mes := c.Query("mes")
+	de := c.Query("de")
+	ate := c.Query("ate")
+
+	if mes != "" {
+		if de != "" || ate != "" {
+			return periodo{}, erros.NoCampo("mes", erros.CampoExclusivo, "de")
+		}
+		return interpretarMes(c)
+	}
+
+	if de == "" && ate == "" {
+		return periodoDoMes(time.Now()), nil
+	}
+	if de == "" || ate == "" {
+		campo, outro := "de", "ate"
+		if de == "" {
+			campo, outro = outro, campo
+		}
+		return periodo{}, erros.NoCampo(campo, erros.CampoExige, outro)
+	}
+
+	inicio, err := interpretarLimite(de, false)
+	if err != nil {
+		return periodo{}, erros.NoCampo("de", erros.CampoFormatoDataOuMes)
+	}
+	fim, err := interpretarLimite(ate, true)
+	if err != nil {
+		return periodo{}, erros.NoCampo("ate", erros.CampoFormatoDataOuMes)
+	}
+	if !fim.After(inicio) {
+		return periodo{}, erros.NoCampo("ate", erros.CampoNaoAnteriorA, "de")
+	}
+
+	return periodo{Inicio: inicio, Fim: fim}, nil
+}
+
+// interpretarMes lê ?mes=YYYY-MM; sem parâmetro, o período é o mês atual
+func interpretarMes(c *gin.Context) (periodo, error) {
+	mes := c.Query("mes")
+	if mes == "" {
+		return periodoDoMes(time.Now()), nil
+	}
+	t, err := time.Parse(formatoMes, mes)
+	if err != nil {
+		return periodo{}, erros.NoCampo("mes", erros.CampoFormatoMes)
+	}
+	return periodoDoMes(t), nil
+}
+
+// adicionarMeses soma meses a uma data mantendo o dia, limitado ao último dia do mês
+// (31/01 + 1 mês = 28/02 ou 29/02)
+func adicionarMeses(t time.Time, meses int) time.Time {
+	inicio := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, meses, 0)
+	ultimoDia := inicio.AddDate(0, 1, -1).Day()
+	dia := t.Day()
+	if dia > ultimoDia {
+		dia = ultimoDia
+	}
+	return time.Date(inicio.Year(), inicio.Month(), dia, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/idioma"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+// Limites da previsão de fluxo de caixa
+const (
+	mesesPrevisaoPadrao    = 6
+	mesesPrevisaoMaximo    = 24
+	mesesHistoricoPrevisao = 6 // meses completos usados para estimar os gastos variáveis
+)
+
+// Métodos aceitos para estimar os gastos variáveis de cada categoria
+const (
+	metodoPrevisaoMediana = "mediana"
+	metodoPrevisaoMedia   = "media"
+)
+
+// mesPrevisao é a projeção de um mês
+type mesPrevisao struct {
+	Mes                      string          `json:"mes"`
+	Parcial                  bool            `json:"parcial"` // só o restante do mês atual, a partir de amanhã
+	Renda                    models.Dinheiro `json:"renda"`
+	GastosFixos              models.Dinheiro `json:"gastos_fixos"`
+	GastosVariaveisEstimados models.Dinheiro `json:"gastos_variaveis_estimados"`
+	SaldoMes                 models.Dinheiro `json:"saldo_mes"`   // renda menos gastos do mês
+	SaldoFinal               models.Dinheiro `json:"saldo_final"` // saldo acumulado ao fim do mês
+	Negativo                 bool            `json:"negativo"`
+}
+
+// historicoVariaveisPorCategoria retorna, por categoria, o total de gastos variáveis de cada mês
+// do período (meses sem gasto ficam com zero). Compras no cartão contam pelo vencimento da fatura.
+func (h *Handler) historicoVariaveisPorCategoria(ctx context.Context, usuarioID int, p periodo) (map[int][]models.Dinheiro, error) {
+	totais, err := h.gastosVariaveis.TotaisPorMes(ctx, usuarioID, p.Inicio, p.Fim)
+	if err != nil {
+		return nil, err
+	}
+
+	meses := p.Meses()
+	indice := make(map[time.Time]int, len(meses))
+	for i, mes := range meses {
+		indice[mes] = i
+	}
+
+	historico := map[int][]models.Dinheiro{}
+	for _, t := range totais {
+		if historico[t.CategoriaID] == nil {
+			historico[t.CategoriaID] = make([]models.Dinheiro, len(meses))
+		}
+		historico[t.CategoriaID][indice[t.Mes]] = t.Total
+	}
+	return historico, nil
+}
+
+// estimarValor resume os totais mensais de uma categoria pela mediana ou pela média
+func estimarValor(valores []models.Dinheiro, metodo string) models.Dinheiro {
+	if len(valores) == 0 {
+		return 0
+	}
+	if metodo == metodoPrevisaoMedia {
+		var soma models.Dinheiro
+		for _, v := range valores {
+			soma += v
+		}
+		return soma / models.Dinheiro(len(valores))
+	}
+
+	ordenados := append([]models.Dinheiro(nil), valores...)
+	sort.Slice(ordenados, func(i, j int) bool { return ordenados[i] < ordenados[j] })
+	meio := len(ordenados) / 2
+	if len(ordenados)%2 == 1 {
+		return ordenados[meio]
+	}
+	return (ordenados[meio-1] + ordenados[meio]) / 2
+}
+
+// ObterPrevisao projeta o fluxo de caixa do restante do mês atual e dos próximos meses (?meses=N,
+// padrão 6). Rendas e gastos fixos vêm das recorrências cadastradas; os gastos variáveis de cada
+// categoria são estimados pela mediana (ou média, com ?metodo=media) dos últimos meses, ou pelo
+// que já está lançado no mês (parcelas, faturas), o que for maior. O saldo parte da soma dos
+// saldos das contas hoje, por isso o mês atual conta só o que falta: a partir de amanhã, com a
+// estimativa descontada do que já foi gasto no mês.
+func (h *Handler) ObterPrevisao(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	quantidade := mesesPrevisaoPadrao
+	if texto := c.Query("meses"); texto != "" {
+		n, err := strconv.Atoi(texto)
+		if err != nil || n < 1 || n > mesesPrevisaoMaximo {
+			erros.Responder(c, erros.NoCampo("meses", erros.CampoIntervalo, 1, mesesPrevisaoMaximo))
+			return
+		}
+		quantidade = n
+	}
+
+	metodo := c.DefaultQuery("metodo", metodoPrevisaoMediana)
+	if metodo != metodoPrevisaoMediana && metodo != metodoPrevisaoMedia {
+		erros.Responder(c, erros.NoCampo("metodo", erros.CampoOpcao, []string{metodoPrevisaoMediana, metodoPrevisaoMedia}))
+		return
+	}
+
+	ctx := c.Request.Context()
+	agora := time.Now().UTC()
+	amanha := time.Date(agora.Year(), agora.Month(), agora.Day()+1, 0, 0, 0, 0, time.UTC)
+	mesAtual := inicioDoMes(agora)
+
+	// Saldo de partida: soma dos saldos atuais das contas
+	contas, err := h.calcularSaldosContas(ctx, usuarioID)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao calcular o saldo atual")
+		return
+	}
+	var saldoInicial models.Dinheiro
+	for _, conta := range contas {
+		saldoInicial += conta.SaldoAtual
+	}
+
+	// Estimativa dos gastos variáveis por categoria a partir do histórico
+	historico, err := h.historicoVariaveisPorCategoria(ctx, usuarioID,
+		periodo{Inicio: mesAtual.AddDate(0, -mesesHistoricoPrevisao, 0), Fim: mesAtual})
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar o histórico de gastos")
+		return
+	}
+	estimativas := make(map[int]models.Dinheiro, len(historico))
+	for categoriaID, valores := range historico {
+		estimativas[categoriaID] = estimarValor(valores, metodo)
+	}
+
+	// Gastos variáveis já lançados nos meses da previsão (parcelas, compras no cartão). O horizonte
+	// começa amanhã: o que vence até hoje já está no saldo das contas.
+	horizonte := periodo{Inicio: amanha, Fim: mesAtual.AddDate(0, quantidade+1, 0)}
+	agendados, err := h.historicoVariaveisPorCategoria(ctx, usuarioID, horizonte)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao buscar os gastos lançados")
+		return
+	}
+
+	// No mês atual, a estimativa é descontada do que já foi gasto até hoje
+	parcial := amanha.Day() != 1
+	gastosNoMes := map[int][]models.Dinheiro{}
+	if parcial {
+		gastosNoMes, err = h.historicoVariaveisPorCategoria(ctx, usuarioID, periodo{Inicio: mesAtual, Fim: amanha})
+		if err != nil {
+			responderErroInterno(c, err, "Erro ao buscar os gastos do mês")
+			return
+		}
+	}
+
+	rendas, err := h.materializarRendas(ctx, usuarioID, horizonte)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao projetar as rendas")
+		return
+	}
+	fixos, err := h.materializarGastosFixos(ctx, usuarioID, horizonte)
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao projetar os gastos fixos")
+		return
+	}
+
+	// Monta a projeção mês a mês; o primeiro é o restante do mês atual, se ainda houver dias
+	meses := make([]mesPrevisao, len(horizonte.Meses()))
+	indice := map[time.Time]int{}
+	for i, mes := range horizonte.Meses() {
+		meses[i].Mes = mes.Format(formatoMes)
+		meses[i].Parcial = mes.Equal(mesAtual)
+		indice[mes] = i
+	}
+	for _, r := range rendas {
+		meses[indice[inicioDoMes(r.Data)]].Renda += r.Valor
+	}
+	for _, f := range fixos {
+		meses[indice[inicioDoMes(f.Data)]].GastosFixos += f.Valor
+	}
+	categorias := map[int]bool{}
+	for categoriaID := range estimativas {
+		categorias[categoriaID] = true
+	}
+	for categoriaID := range agendados {
+		categorias[categoriaID] = true
+	}
+	for i := range meses {
+		for categoriaID := range categorias {
+			valor := estimativas[categoriaID]
+			if gastos := gastosNoMes[categoriaID]; meses[i].Parcial && gastos != nil {
+				valor = max(valor-gastos[0], 0)
+			}
+			if lancados := agendados[categoriaID]; lancados != nil && lancados[i] > valor {
+				valor = lancados[i]
+			}
+			meses[i].GastosVariaveisEstimados += valor
+		}
+	}
+
+	idiomaAlertas := idioma.DaRequisicao(c)
+	alertas := []string{}
+	saldo := saldoInicial
+	for i := range meses {
+		m := &meses[i]
+		m.SaldoMes = m.Renda - m.GastosFixos - m.GastosVariaveisEstimados
+		saldo += m.SaldoMes
+		m.SaldoFinal = saldo
+		if saldo < 0 {
+			m.Negativo = true
+			alertas = append(alertas, fmt.Sprintf(textoGerado(idiomaAlertas, txtSaldoNegativo), m.Mes, idiomaAlertas.FormatarValorTexto(saldo)))
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"saldo_inicial": saldoInicial,
+		"metodo":        metodo,
+		"meses":         meses,
+		"alertas":       alertas,
+	})
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+// Intervalo, em meses, de cada frequência de gasto fixo
+var mesesPorFrequencia = map[string]int{
+	models.FrequenciaMensal:     1,
+	models.FrequenciaBimestral:  2,
+	models.FrequenciaTrimestral: 3,
+	models.FrequenciaAnual:      12,
+}
+
+// recorrencia descreve quando um gasto fixo se repete: a partir do mês de início,
+// a cada intervalo da frequência, até o mês final (inclusive), no dia indicado (padrão: dia 1)
+type recorrencia struct {
+	Inicio     time.Time  // primeiro dia do mês inicial
+	Fim        *time.Time // primeiro dia do mês final; nil para sem fim
+	Frequencia string
+	Dia        *int
+}
+
+// ocorreNoMes indica se o gasto fixo é cobrado no mês da data
+func (r recorrencia) ocorreNoMes(mes time.Time) bool {
+	mes = inicioDoMes(mes)
+	if mes.Before(r.Inicio) || (r.Fim != nil && mes.After(*r.Fim)) {
+		return false
+	}
+	intervalo := mesesPorFrequencia[r.Frequencia]
+	if intervalo == 0 {
+		intervalo = 1
+	}
+	meses := (mes.Year()-r.Inicio.Year())*12 + int(mes.Month()-r.Inicio.Month())
+	return meses%intervalo == 0
+}
+
+// dataNoMes retorna a data de cobrança do gasto fixo dentro do mês
+func (r recorrencia) dataNoMes(mes time.Time) time.Time {
+	dia := 1
+	if r.Dia != nil {
+		dia = *r.Dia
+	}
+	return diaNoMes(mes, dia)
+}
+
+// ocorrencias retorna as datas de cobrança do gasto fixo dentro do período
+func (r recorrencia) ocorrencias(p periodo) []time.Time {
+	var datas []time.Time
+	for _, mes := range p.Meses() {
+		if !r.ocorreNoMes(mes) {
+			continue
+		}
+		if data := r.dataNoMes(mes); !data.Before(p.Inicio) && data.Before(p.Fim) {
+			datas = append(datas, data)
+		}
+	}
+	return datas
+}
+
+// ocorrenciaGastoFixo é uma cobrança de um gasto fixo em uma data
+type ocorrenciaGastoFixo struct {
+	GastoFixoID int
+	Data        time.Time
+	Valor       models.Dinheiro
+	CategoriaID *int
+	ContaID     *int
+}
+
+// materializarGastosFixos gera as cobranças dos gastos fixos do usuário dentro do período.
+// É a base do resumo, dos orçamentos, dos saldos das contas e da previsão.
+func (h *Handler) materializarGastosFixos(ctx context.Context, usuarioID int, p periodo) ([]ocorrenciaGastoFixo, error) {
+	gastos, err := h.gastosFixos.NoPeriodo(ctx, usuarioID, p.Inicio, p.Fim)
+	if err != nil {
+		return nil, err
+	}
+
+	var ocorrencias []ocorrenciaGastoFixo
+	for _, gasto := range gastos {
+		g := ocorrenciaGastoFixo{GastoFixoID: gasto.ID, Valor: gasto.Valor, CategoriaID: gasto.CategoriaID, ContaID: gasto.ContaID}
+		r := recorrencia{Inicio: *gasto.Inicio, Fim: gasto.Fim, Frequencia: gasto.Frequencia, Dia: gasto.Dia}
+		for _, data := range r.ocorrencias(p) {
+			g.Data = data
+			ocorrencias = append(ocorrencias, g)
+		}
+	}
+	return ocorrencias, nil
+}
+
+// entradaRecorrencia são os campos de recorrência aceitos na criação e edição de gastos fixos
+type entradaRecorrencia struct {
+	Inicio     *string `json:"inicio"`     // YYYY-MM; padrão: mês atual
+	Fim        *string `json:"fim"`        // YYYY-MM; omitido para sem fim
+	Frequencia string  `json:"frequencia"` // padrão: mensal
+	Dia        *int    `json:"dia"`        // dia do mês da cobrança; padrão: dia 1
+}
+
+// interpretar valida os campos de recorrência e retorna início (nil se omitido), fim e frequência
+func (e entradaRecorrencia) interpretar() (inicio, fim *time.Time, frequencia string, err error) {
+	frequencia = e.Frequencia
+	if frequencia == "" {
+		frequencia = models.FrequenciaMensal
+	}
+	if _, ok := mesesPorFrequencia[frequencia]; !ok {
+		return nil, nil, "", erros.NoCampo("frequencia", erros.CampoOpcao, []string{
+			models.FrequenciaMensal, models.FrequenciaBimestral, models.FrequenciaTrimestral, models.FrequenciaAnual,
+		})
+	}
+	if e.Dia != nil && (*e.Dia < 1 || *e.Dia > 31) {
+		return nil, nil, "", erros.NoCampo("dia", erros.CampoIntervalo, 1, 31)
+	}
+	if e.Inicio != nil {
+		t, err := time.Parse(formatoMes, *e.Inicio)
+		if err != nil {
+			return nil, nil, "", erros.NoCampo("inicio", erros.CampoFormatoMes)
+		}
+		inicio = &t
+	}
+	if e.Fim != nil {
+		t, err := time.Parse(formatoMes, *e.Fim)
+		if err != nil {
+			return nil, nil, "", erros.NoCampo("fim", erros.CampoFormatoMes)
+		}
+		fim = &t
+	}
+	if inicio != nil && fim != nil && fim.Before(*inicio) {
+		return nil, nil, "", erros.NoCampo("fim", erros.CampoNaoAnteriorA, "inicio")
+	}
+	return inicio, fim, frequencia, nil
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+func mesTeste(t *testing.T, texto string) *time.Time {
+	t.Helper()
+	m, err := time.Parse(formatoMes, texto)
+	if err != nil {
+		t.Fatalf("mês inválido %q: %v", texto, err)
+	}
+	return &m
+}
+
+func TestRecorrenciaOcorreNoMes(t *testing.T) {
+	casos := []struct {
+		nome       string
+		inicio     string
+		fim        string
+		frequencia string
+		mes        string
+		esperado   bool
+	}{
+		{"mensal no mês de início", "2025-01", "", models.FrequenciaMensal, "2025-01", true},
+		{"mensal antes do início", "2025-01", "", models.FrequenciaMensal, "2024-12", false},
+		{"mensal no mês final (inclusive)", "2025-01", "2025-06", models.FrequenciaMensal, "2025-06", true},
+		{"mensal depois do mês final", "2025-01", "2025-06", models.FrequenciaMensal, "2025-07", false},
+		{"bimestral no segundo mês", "2025-01", "", models.FrequenciaBimestral, "2025-02", false},
+		{"bimestral no terceiro mês", "2025-01", "", models.FrequenciaBimestral, "2025-03", true},
+		{"bimestral na virada do ano", "2024-11", "", models.FrequenciaBimestral, "2025-01", true},
+		{"trimestral", "2025-01", "", models.FrequenciaTrimestral, "2025-04", true},
+		{"trimestral fora do intervalo", "2025-01", "", models.FrequenciaTrimestral, "2025-05", false},
+		{"anual um ano depois", "2024-03", "", models.FrequenciaAnual, "2025-03", true},
+		{"anual no mês seguinte", "2024-03", "", models.FrequenciaAnual, "2024-04", false},
+		{"anual com fim antes do aniversário", "2024-03", "2025-02", models.FrequenciaAnual, "2025-03", false},
+		{"frequência desconhecida vale como mensal", "2025-01", "", "", "2025-02", true},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			r := recorrencia{Inicio: *mesTeste(t, caso.inicio), Frequencia: caso.frequencia}
+			if caso.fim != "" {
+				r.Fim = mesTeste(t, caso.fim)
+			}
+			// Qualquer dia do mês consultado vale
+			mes := mesTeste(t, caso.mes).AddDate(0, 0, 14)
+			if got := r.ocorreNoMes(mes); got != caso.esperado {
+				t.Errorf("ocorreNoMes(%s) = %v, esperado %v", caso.mes, got, caso.esperado)
+			}
+		})
+	}
+}
+
+func TestRecorrenciaOcorrencias(t *testing.T) {
+	dia := func(d int) *int { return &d }
+	casos := []struct {
+		nome     string
+		r        recorrencia
+		de, ate  string // período [de, ate)
+		esperado []string
+	}{
+		{
+			nome: "dia 31 em fevereiro cai no último dia",
+			r:    recorrencia{Frequencia: models.FrequenciaMensal, Dia: dia(31)},
+			de:   "2025-01-01", ate: "2025-04-01",
+			esperado: []string{"2025-01-31", "2025-02-28", "2025-03-31"},
+		},
+		{
+			nome: "dia 31 em fevereiro de ano bissexto",
+			r:    recorrencia{Inicio: *mesTeste(t, "2024-01"), Frequencia: models.FrequenciaMensal, Dia: dia(31)},
+			de:   "2024-02-01", ate: "2024-03-01",
+			esperado: []string{"2024-02-29"},
+		},
+		{
+			nome: "sem dia cobra no dia 1",
+			r:    recorrencia{Frequencia: models.FrequenciaMensal},
+			de:   "2025-01-01", ate: "2025-03-01",
+			esperado: []string{"2025-01-01", "2025-02-01"},
+		},
+		{
+			nome: "período no meio do mês deixa de fora as datas anteriores",
+			r:    recorrencia{Frequencia: models.FrequenciaMensal, Dia: dia(10)},
+			de:   "2025-01-15", ate: "2025-03-15",
+			esperado: []string{"2025-02-10", "2025-03-10"},
+		},
+		{
+			nome: "fim do período é exclusivo",
+			r:    recorrencia{Frequencia: models.FrequenciaMensal, Dia: dia(10)},
+			de:   "2025-01-01", ate: "2025-02-10",
+			esperado: []string{"2025-01-10"},
+		},
+		{
+			nome: "bimestral",
+			r:    recorrencia{Frequencia: models.FrequenciaBimestral, Dia: dia(5)},
+			de:   "2025-01-01", ate: "2025-07-01",
+			esperado: []string{"2025-01-05", "2025-03-05", "2025-05-05"},
+		},
+		{
+			nome: "anual com início no ano anterior",
+			r:    recorrencia{Inicio: *mesTeste(t, "2024-06"), Frequencia: models.FrequenciaAnual, Dia: dia(20)},
+			de:   "2025-01-01", ate: "2026-01-01",
+			esperado: []string{"2025-06-20"},
+		},
+		{
+			nome: "mês final inclusive",
+			r:    recorrencia{Fim: mesTeste(t, "2025-02"), Frequencia: models.FrequenciaMensal, Dia: dia(28)},
+			de:   "2025-01-01", ate: "2025-06-01",
+			esperado: []string{"2025-01-28", "2025-02-28"},
+		},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			r := caso.r
+			if r.Inicio.IsZero() {
+				r.Inicio = *mesTeste(t, "2025-01")
+			}
+			p := periodo{Inicio: dataTeste(t, caso.de), Fim: dataTeste(t, caso.ate)}
+			var datas []string
+			for _, d := range r.ocorrencias(p) {
+				datas = append(datas, d.Format(formatoData))
+			}
+			if !reflect.DeepEqual(datas, caso.esperado) {
+				t.Errorf("ocorrencias(%s, %s) = %v, esperado %v", caso.de, caso.ate, datas, caso.esperado)
+			}
+		})
+	}
+}
+
+func TestEntradaRecorrenciaInterpretar(t *testing.T) {
+	texto := func(s string) *string { return &s }
+	dia := func(d int) *int { return &d }
+	casos := []struct {
+		nome       string
+		entrada    entradaRecorrencia
+		frequencia string
+		campo      string // campo do erro esperado; vazio se válido
+		codigo     erros.CodigoCampo
+	}{
+		{"padrão mensal", entradaRecorrencia{}, models.FrequenciaMensal, "", ""},
+		{"anual com início e fim", entradaRecorrencia{Inicio: texto("2025-01"), Fim: texto("2026-01"), Frequencia: models.FrequenciaAnual}, models.FrequenciaAnual, "", ""},
+		{"fim igual ao início", entradaRecorrencia{Inicio: texto("2025-01"), Fim: texto("2025-01")}, models.FrequenciaMensal, "", ""},
+		{"frequência inválida", entradaRecorrencia{Frequencia: "semanal"}, "", "frequencia", erros.CampoOpcao},
+		{"dia zero", entradaRecorrencia{Dia: dia(0)}, "", "dia", erros.CampoIntervalo},
+		{"dia 32", entradaRecorrencia{Dia: dia(32)}, "", "dia", erros.CampoIntervalo},
+		{"início fora do formato", entradaRecorrencia{Inicio: texto("2025-01-01")}, "", "inicio", erros.CampoFormatoMes},
+		{"fim fora do formato", entradaRecorrencia{Fim: texto("jan/2025")}, "", "fim", erros.CampoFormatoMes},
+		{"fim antes do início", entradaRecorrencia{Inicio: texto("2025-02"), Fim: texto("2025-01")}, "", "fim", erros.CampoNaoAnteriorA},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			_, _, frequencia, err := caso.entrada.interpretar()
+			if caso.campo == "" {
+				if err != nil {
+					t.Fatalf("erro inesperado: %v", err)
+				}
+				if frequencia != caso.frequencia {
+					t.Errorf("frequência = %q, esperado %q", frequencia, caso.frequencia)
+				}
+				return
+			}
+			var e *erros.Erro
+			if !errors.As(err, &e) || len(e.Campos) != 1 {
+				t.Fatalf("esperado erro no campo %q, recebido %v", caso.campo, err)
+			}
+			if e.Campos[0].Campo != caso.campo || e.Campos[0].Codigo != caso.codigo {
+				t.Errorf("erro em %q (%s), esperado %q (%s)", e.Campos[0].Campo, e.Campos[0].Codigo, caso.campo, caso.codigo)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/models"
+	"github.com/jpeccia/quantogasto_app_server/repositorio"
+)
+
+// Fontes de renda aceitas na criação e edição
+var fontesRenda = map[string]bool{
+	models.FonteSalario:        true,
+	models.FonteFreelance:      true,
+	models.FonteAluguel:        true,
+	models.FonteDecimoTerceiro: true,
+	models.FonteFerias:         true,
+	models.FonteOutra:          true,
+}
+
+// entradaRenda são os campos aceitos na criação e edição de rendas
+type entradaRenda struct {
+	Valor       models.Dinheiro `json:"valor" binding:"required"`
+	Fonte       string          `json:"fonte"`        // padrão: outra
+	Recorrencia string          `json:"recorrencia"`  // padrão: unica
+	DataEfetiva *string         `json:"data_efetiva"` // YYYY-MM-DD; padrão: hoje
+	DataFim     *string         `json:"data_fim"`     // YYYY-MM-DD; somente para rendas mensais
+	Descricao   string          `json:"descricao" binding:"max=200"`
+	ContaID     *int            `json:"conta_id"`
+}
+
+// rendaValidada é a entrada de renda já interpretada
+type rendaValidada struct {
+	entradaRenda
+	dataEfetiva time.Time
+	dataFim     *time.Time
+}
+
+// dados converte a entrada nos campos gravados pelo repositório
+func (r rendaValidada) dados() repositorio.DadosRenda {
+	return repositorio.DadosRenda{
+		Valor: r.Valor, Fonte: r.Fonte, Descricao: r.Descricao, Recorrencia: r.Recorrencia,
+		DataEfetiva: r.dataEfetiva, DataFim: r.dataFim, ContaID: r.ContaID,
+	}
+}
+
+// validarEntradaRenda lê e valida o JSON de uma renda, respondendo em caso de erro
+func (h *Handler) validarEntradaRenda(c *gin.Context, usuarioID int) (rendaValidada, bool) {
+	var r rendaValidada
+	if err := c.ShouldBindBodyWithJSON(&r.entradaRenda); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &r.entradaRenda, err))
+		return r, false
+	}
+
+	// Verifica se o valor é positivo
+	if r.Valor <= 0 {
+		erros.Responder(c, erros.NoCampo("valor", erros.CampoMaiorQueZero))
+		return r, false
+	}
+
+	if r.Fonte == "" {
+		r.Fonte = models.FonteOutra
+	}
+	if !fontesRenda[r.Fonte] {
+		erros.Responder(c, erros.NoCampo("fonte", erros.CampoOpcao, []string{
+			models.FonteSalario, models.FonteFreelance, models.FonteAluguel,
+			models.FonteDecimoTerceiro, models.FonteFerias, models.FonteOutra,
+		}))
+		return r, false
+	}
+
+	if r.Recorrencia == "" {
+		r.Recorrencia = models.RendaUnica
+	}
+	if r.Recorrencia != models.RendaUnica && r.Recorrencia != models.RendaMensal {
+		erros.Responder(c, erros.NoCampo("recorrencia", erros.CampoOpcao, []string{models.RendaUnica, models.RendaMensal}))
+		return r, false
+	}
+
+	hoje := time.Now().UTC()
+	r.dataEfetiva = time.Date(hoje.Year(), hoje.Month(), hoje.Day(), 0, 0, 0, 0, time.UTC)
+	if r.DataEfetiva != nil {
+		t, err := time.Parse(formatoData, *r.DataEfetiva)
+		if err != nil {
+			erros.Responder(c, erros.NoCampo("data_efetiva", erros.CampoFormatoData))
+			return r, false
+		}
+		r.dataEfetiva = t
+	}
+
+	if r.DataFim != nil {
+		if r.Recorrencia != models.RendaMensal {
+			erros.Responder(c, erros.NoCampo("data_fim", erros.CampoSomenteCom, "recorrencia", models.RendaMensal))
+			return r, false
+		}
+		t, err := time.Parse(formatoData, *r.DataFim)
+		if err != nil {
+			erros.Responder(c, erros.NoCampo("data_fim", erros.CampoFormatoData))
+			return r, false
+		}
+		if t.Before(r.dataEfetiva) {
+			erros.Responder(c, erros.NoCampo("data_fim", erros.CampoNaoAnteriorA, "data_efetiva"))
+			return r, false
+		}
+		r.dataFim = &t
+	}
+
+	// Verifica se a conta pertence ao usuário
+	if !h.validarContaDoLancamento(c, usuarioID, "conta_id", r.ContaID) {
+		return r, false
+	}
+	return r, true
+}
+
+// ocorrenciaRenda é um recebimento de uma renda em uma data
+type ocorrenciaRenda struct {
+	RendaID int
+	Data    time.Time
+	Valor   models.Dinheiro
+	ContaID *int
+}
+
+// materializarRendas gera os recebimentos do usuário dentro do período: as rendas únicas
+// na data efetiva e as mensais todo mês, no dia da data efetiva, até a data final
+func (h *Handler) materializarRendas(ctx context.Context, usuarioID int, p periodo) ([]ocorrenciaRenda, error) {
+	rendas, err := h.rendas.NoPeriodo(ctx, usuarioID, p.Inicio, p.Fim)
+	if err != nil {
+		return nil, err
+	}
+
+	var ocorrencias []ocorrenciaRenda
+	for _, renda := range rendas {
+		o := ocorrenciaRenda{RendaID: renda.ID, Valor: renda.Valor, ContaID: renda.ContaID}
+		if renda.Recorrencia == models.RendaUnica {
+			o.Data = renda.DataEfetiva
+			ocorrencias = append(ocorrencias, o)
+			continue
+		}
+
+		dia := renda.DataEfetiva.Day()
+		r := recorrencia{Inicio: inicioDoMes(renda.DataEfetiva), Frequencia: models.FrequenciaMensal, Dia: &dia}
+		for _, data := range r.ocorrencias(p) {
+			if renda.DataFim != nil && data.After(*renda.DataFim) {
+				break
+			}
+			o.Data = data
+			ocorrencias = append(ocorrencias, o)
+		}
+	}
+	return ocorrencias, nil
+}
+
+// AdicionarRenda registra uma renda do usuário (única ou mensal)
+func (h *Handler) AdicionarRenda(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto (middleware de autenticação)
+
+	input, ok := h.validarEntradaRenda(c, usuarioID)
+	if !ok {
+		return
+	}
+
+	// Insere a renda no banco de dados
+	id, err := h.rendas.Criar(c.Request.Context(), usuarioID, input.dados())
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao adicionar renda")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgRendaAdicionada), "id": id})
+}
+
+// DefinirSalario troca o valor do salário mensal do usuário, como a renda do PATCH /usuarios/me.
+// Repetir a requisição com o mesmo valor não cria outra renda.
+func (h *Handler) DefinirSalario(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	var input struct {
+		Valor models.Dinheiro `json:"valor" binding:"required"`
+	}
+	if err := c.ShouldBindBodyWithJSON(&input); err != nil {
+		erros.Responder(c, erros.DaValidacao(c, &input, err))
+		return
+	}
+
+	// Verifica se o valor é positivo
+	if input.Valor <= 0 {
+		erros.Responder(c, erros.NoCampo("valor", erros.CampoMaiorQueZero))
+		return
+	}
+
+	err := h.usuarios.Atualizar(c.Request.Context(), usuarioID, repositorio.AlteracoesUsuario{Renda: &input.Valor})
+	if errors.Is(err, repositorio.ErrNaoEncontrado) {
+		erros.Responder(c, erros.Novo(erros.UsuarioNaoEncontrado))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao definir o salário")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgRendaAtualizada)})
+}
+
+// EditarRenda atualiza uma renda do usuário
+func (h *Handler) EditarRenda(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	rendaID, ok := idDaURL(c, erros.RendaNaoEncontrada)
+	if !ok {
+		return
+	}
+
+	input, ok := h.validarEntradaRenda(c, usuarioID)
+	if !ok {
+		return
+	}
+
+	// Atualiza a renda no banco de dados
+	err := h.rendas.Atualizar(c.Request.Context(), usuarioID, rendaID, input.dados())
+
+	// Verifica se a renda foi encontrada e atualizada
+	if errors.Is(err, repositorio.ErrNaoEncontrado) {
+		erros.Responder(c, erros.Novo(erros.RendaNaoEncontrada))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao atualizar a renda")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgRendaAtualizada)})
+}
+
+// RemoverRenda remove uma renda do usuário
+func (h *Handler) RemoverRenda(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+	rendaID, ok := idDaURL(c, erros.RendaNaoEncontrada)
+	if !ok {
+		return
+	}
+
+	err := h.rendas.Remover(c.Request.Context(), usuarioID, rendaID)
+
+	// Verifica se a renda foi encontrada e removida
+	if errors.Is(err, repositorio.ErrNaoEncontrado) {
+		erros.Responder(c, erros.Novo(erros.RendaNaoEncontrada))
+		return
+	}
+	if err != nil {
+		responderErroInterno(c, err, "Erro ao remover a renda")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": mensagem(c, msgRendaRemovida)})
+}
+
+// listagemRendas descreve a listagem paginada de rendas
+var listagemRendas = configuracaoListagem{
+	ordenacoes:      repositorio.OrdenacoesRendas,
+	ordenacaoPadrao: "data_efetiva",
+	filtraConta:     true,
+}
+
+// ListarRendas lista as rendas do usuário com paginação por cursor
+func (h *Handler) ListarRendas(c *gin.Context) {
+	listar(c, listagemRendas, h.rendas.Listar)
+}
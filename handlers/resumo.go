@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/idioma"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+// resumoFinanceiro reúne os totais de um período
+type resumoFinanceiro struct {
+	Periodo              periodoJSON      `json:"periodo"`
+	RendaTotal           models.Dinheiro  `json:"renda_total"`
+	GastosFixosTotal     models.Dinheiro  `json:"gastos_fixos_total"`
+	GastosVariaveisTotal models.Dinheiro  `json:"gastos_variaveis_total"`
+	AportesMetas         models.Dinheiro  `json:"aportes_metas"`
+	SaldoDisponivel      models.Dinheiro  `json:"saldo_disponivel"`
+	PorCategoria         []totalCategoria `json:"por_categoria"`
+}
+
+// erroResumo identifica qual etapa do cálculo falhou, para o log
+type erroResumo struct {
+	mensagem string
+	err      error
+}
+
+func (e *erroResumo) Error() string { return e.mensagem + ": " + e.err.Error() }
+func (e *erroResumo) Unwrap() error { return e.err }
+
+// responderErroResumo responde a uma falha no cálculo do resumo; a etapa que falhou vai para o log
+func responderErroResumo(c *gin.Context, err error) {
+	responderErroInterno(c, err, "Erro ao calcular resumo")
+}
+
+// semCategoria é a chave usada nos mapas por categoria para gastos sem categoria
+const semCategoria = 0
+
+// gastosFixosPorCategoria soma, por categoria, as cobranças dos gastos fixos dentro do período,
+// conforme a recorrência de cada um
+func (h *Handler) gastosFixosPorCategoria(ctx context.Context, usuarioID int, p periodo) (map[int]models.Dinheiro, error) {
+	ocorrencias, err := h.materializarGastosFixos(ctx, usuarioID, p)
+	if err != nil {
+		return nil, err
+	}
+
+	totais := map[int]models.Dinheiro{}
+	for _, o := range ocorrencias {
+		categoriaID := semCategoria
+		if o.CategoriaID != nil {
+			categoriaID = *o.CategoriaID
+		}
+		totais[categoriaID] += o.Valor
+	}
+	return totais, nil
+}
+
+// gastosVariaveisPorCategoria soma, por categoria, os gastos variáveis com data dentro do período.
+// Compras no cartão contam pela data de vencimento da fatura, e não pela data da compra.
+func (h *Handler) gastosVariaveisPorCategoria(ctx context.Context, usuarioID int, p periodo) (map[int]models.Dinheiro, error) {
+	meses, err := h.gastosVariaveis.TotaisPorMes(ctx, usuarioID, p.Inicio, p.Fim)
+	if err != nil {
+		return nil, err
+	}
+
+	totais := map[int]models.Dinheiro{}
+	for _, t := range meses {
+		totais[t.CategoriaID] += t.Total
+	}
+	return totais, nil
+}
+
+// totalCategoria é a linha do detalhamento de gastos por categoria
+type totalCategoria struct {
+	CategoriaID     *int            `json:"categoria_id"` // nil para gastos sem categoria
+	Nome            string          `json:"nome"`
+	Cor             string          `json:"cor"`
+	Icone           string          `json:"icone"`
+	GastosFixos     models.Dinheiro `json:"gastos_fixos"`
+	GastosVariaveis models.Dinheiro `json:"gastos_variaveis"`
+	Total           models.Dinheiro `json:"total"`
+}
+
+// montarPorCategoria junta os totais fixos e variáveis com os dados das categorias,
+// ordenando do maior para o menor gasto; a linha sem categoria tem o nome no idioma i
+func (h *Handler) montarPorCategoria(ctx context.Context, i idioma.Idioma, fixos, variaveis map[int]models.Dinheiro) ([]totalCategoria, error) {
+	linhas := map[int]*totalCategoria{}
+	linha := func(categoriaID int) *totalCategoria {
+		if l, ok := linhas[categoriaID]; ok {
+			return l
+		}
+		l := &totalCategoria{Nome: textoGerado(i, txtSemCategoria), Cor: corCategoriaPadrao}
+		if categoriaID != semCategoria {
+			id := categoriaID
+			l.CategoriaID = &id
+		}
+		linhas[categoriaID] = l
+		return l
+	}
+	for id, valor := range fixos {
+		linha(id).GastosFixos += valor
+	}
+	for id, valor := range variaveis {
+		linha(id).GastosVariaveis += valor
+	}
+
+	// Busca nome, cor e ícone das categorias envolvidas
+	ids := make([]int, 0, len(linhas))
+	for id := range linhas {
+		if id != semCategoria {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > 0 {
+		categorias, err := h.categorias.Buscar(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, categoria := range categorias {
+			l := linhas[categoria.ID]
+			l.Nome, l.Cor, l.Icone = categoria.Nome, categoria.Cor, categoria.Icone
+		}
+	}
+
+	resultado := make([]totalCategoria, 0, len(linhas))
+	for _, l := range linhas {
+		l.Total = l.GastosFixos + l.GastosVariaveis
+		resultado = append(resultado, *l)
+	}
+	sort.Slice(resultado, func(i, j int) bool {
+		if resultado[i].Total != resultado[j].Total {
+			return resultado[i].Total > resultado[j].Total
+		}
+		return resultado[i].Nome < resultado[j].Nome
+	})
+	return resultado, nil
+}
+
+// calcularResumo calcula renda, gastos e saldo do usuário dentro do período, com os nomes
+// gerados no idioma i
+func (h *Handler) calcularResumo(ctx context.Context, i idioma.Idioma, usuarioID int, p periodo) (resumoFinanceiro, error) {
+	resumo := resumoFinanceiro{Periodo: p.JSON()}
+
+	// Obtém a renda recebida no período (rendas únicas e recebimentos das mensais)
+	rendas, err := h.materializarRendas(ctx, usuarioID, p)
+	if err != nil {
+		return resumo, &erroResumo{"Erro ao buscar renda", err}
+	}
+	for _, r := range rendas {
+		resumo.RendaTotal += r.Valor
+	}
+
+	// Obtém os gastos fixos cobrados no período, por categoria
+	fixos, err := h.gastosFixosPorCategoria(ctx, usuarioID, p)
+	if err != nil {
+		return resumo, &erroResumo{"Erro ao buscar gastos fixos", err}
+	}
+
+	// Obtém os gastos variáveis do período (compras no cartão pelo vencimento da fatura), por categoria
+	variaveis, err := h.gastosVariaveisPorCategoria(ctx, usuarioID, p)
+	if err != nil {
+		return resumo, &erroResumo{"Erro ao buscar gastos variáveis", err}
+	}
+
+	resumo.PorCategoria, err = h.montarPorCategoria(ctx, i, fixos, variaveis)
+	if err != nil {
+		return resumo, &erroResumo{"Erro ao buscar categorias", err}
+	}
+	for _, l := range resumo.PorCategoria {
+		resumo.GastosFixosTotal += l.GastosFixos
+		resumo.GastosVariaveisTotal += l.GastosVariaveis
+	}
+
+	// Obtém os aportes do período nas metas que descontam do saldo
+	resumo.AportesMetas, err = h.metas.AportesDescontados(ctx, usuarioID, p.Inicio, p.Fim)
+	if err != nil {
+		return resumo, &erroResumo{"Erro ao buscar aportes em metas", err}
+	}
+
+	// Calcula o saldo disponível
+	resumo.SaldoDisponivel = resumo.RendaTotal - resumo.GastosFixosTotal - resumo.GastosVariaveisTotal - resumo.AportesMetas
+	return resumo, nil
+}
+
+// ObterResumo retorna um resumo financeiro do usuário no período (?mes=YYYY-MM ou ?de=&ate=),
+// junto com os números do período anterior para comparação
+func (h *Handler) ObterResumo(c *gin.Context) {
+	usuarioID := c.GetInt("usuario_id") // Obtém o ID do usuário do contexto
+
+	// Interpreta o período solicitado (padrão: mês atual)
+	p, err := interpretarPeriodo(c)
+	if err != nil {
+		erros.Responder(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	i := idioma.DaRequisicao(c)
+	atual, err := h.calcularResumo(ctx, i, usuarioID, p)
+	if err != nil {
+		responderErroResumo(c, err)
+		return
+	}
+
+	anterior, err := h.calcularResumo(ctx, i, usuarioID, p.Anterior())
+	if err != nil {
+		responderErroResumo(c, err)
+		return
+	}
+
+	// Retorna o resumo
+	c.JSON(http.StatusOK, gin.H{
+		"periodo":                atual.Periodo,
+		"renda_total":            atual.RendaTotal,
+		"gastos_fixos_total":     atual.GastosFixosTotal,
+		"gastos_variaveis_total": atual.GastosVariaveisTotal,
+		"aportes_metas":          atual.AportesMetas,
+		"saldo_disponivel":       atual.SaldoDisponivel,
+		"por_categoria":          atual.PorCategoria,
+		"periodo_anterior":       anterior,
+	})
+}
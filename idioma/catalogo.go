@@ -0,0 +1,15 @@
+package idioma
+
+// Catalogo guarda os textos de cada idioma por chave (códigos de erro, de sucesso etc.)
+type Catalogo map[Idioma]map[string]string
+
+// Texto retorna o texto da chave no idioma; se faltar a tradução, o texto no idioma padrão
+func (c Catalogo) Texto(i Idioma, chave string) string {
+	if texto, ok := c[i][chave]; ok {
+		return texto
+	}
+	if texto, ok := c[Padrao][chave]; ok {
+		return texto
+	}
+	return chave
+}
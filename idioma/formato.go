@@ -0,0 +1,63 @@
+package idioma
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+// formato são as convenções de números e datas de um idioma
+type formato struct {
+	decimal string // separador decimal
+	milhar  string // separador de milhar
+	data    string // layout de data do pacote time
+}
+
+var formatos = map[Idioma]formato{
+	PortuguesBR: {decimal: ",", milhar: ".", data: "02/01/2006"},
+	Espanhol:    {decimal: ",", milhar: ".", data: "02/01/2006"},
+	Ingles:      {decimal: ".", milhar: ",", data: "2006-01-02"}, // ISO 8601: sem a ambiguidade entre mês e dia
+}
+
+func (i Idioma) formato() formato {
+	if f, ok := formatos[i]; ok {
+		return f
+	}
+	return formatos[Padrao]
+}
+
+// SeparadorDecimal é a vírgula ou o ponto, conforme o idioma
+func (i Idioma) SeparadorDecimal() string {
+	return i.formato().decimal
+}
+
+// FormatarValor formata o valor com o separador decimal do idioma, sem separador de milhar
+// (ex.: "1234,50"); é o formato que as planilhas leem como número
+func (i Idioma) FormatarValor(d models.Dinheiro) string {
+	return strings.Replace(d.String(), ".", i.formato().decimal, 1)
+}
+
+// FormatarValorTexto formata o valor para leitura, com separador de milhar (ex.: "1.234,50")
+func (i Idioma) FormatarValorTexto(d models.Dinheiro) string {
+	f := i.formato()
+	inteiro, centavos, _ := strings.Cut(d.String(), ".")
+	sinal := ""
+	if strings.HasPrefix(inteiro, "-") {
+		sinal, inteiro = "-", inteiro[1:]
+	}
+
+	var b strings.Builder
+	for n, r := range inteiro {
+		if n > 0 && (len(inteiro)-n)%3 == 0 {
+			b.WriteString(f.milhar)
+		}
+		b.WriteRune(r)
+	}
+	return sinal + b.String() + f.decimal + centavos
+}
+
+// FormatarData formata a data conforme o idioma (ex.: "31/01/2025" ou "2025-01-31")
+func (i Idioma) FormatarData(t time.Time) string {
+	return t.Format(i.formato().data)
+}
@@ -0,0 +1,88 @@
+// Package idioma escolhe o idioma das mensagens da API (pelo Accept-Language ou pela preferência
+// do usuário) e formata números e datas conforme o idioma.
+package idioma
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Idioma é uma tag BCP 47 suportada pela API
+type Idioma string
+
+// Idiomas suportados
+const (
+	PortuguesBR Idioma = "pt-BR"
+	Ingles      Idioma = "en"
+	Espanhol    Idioma = "es"
+)
+
+// Padrao é o idioma usado quando o cliente não pede nenhum dos suportados
+const Padrao = PortuguesBR
+
+// Suportados lista os idiomas aceitos, na ordem das mensagens de validação
+var Suportados = []string{string(PortuguesBR), string(Ingles), string(Espanhol)}
+
+// ChaveContexto é a chave do contexto do Gin com o idioma da requisição (middleware.Idioma)
+const ChaveContexto = "idioma"
+
+// Interpretar associa uma tag de idioma a um dos suportados, pelo idioma principal:
+// "pt", "pt-BR" e "pt-PT" usam as mensagens em português; "es-AR", as em espanhol.
+func Interpretar(tag string) (Idioma, bool) {
+	principal, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	switch principal {
+	case "pt":
+		return PortuguesBR, true
+	case "en":
+		return Ingles, true
+	case "es":
+		return Espanhol, true
+	}
+	return "", false
+}
+
+// Negociar escolhe o idioma pelo cabeçalho Accept-Language (ex.: "es-AR,es;q=0.9,en;q=0.8"):
+// o suportado de maior peso, ou o padrão se nenhum for aceito
+func Negociar(acceptLanguage string) Idioma {
+	type opcao struct {
+		idioma Idioma
+		peso   float64
+	}
+	var opcoes []opcao
+	for _, item := range strings.Split(acceptLanguage, ",") {
+		tag, parametros, _ := strings.Cut(item, ";")
+		i, ok := Interpretar(tag)
+		if !ok {
+			continue
+		}
+		peso := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(parametros), "q="); ok {
+			valor, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			peso = valor
+		}
+		if peso > 0 {
+			opcoes = append(opcoes, opcao{i, peso})
+		}
+	}
+	if len(opcoes) == 0 {
+		return Padrao
+	}
+
+	// Em caso de empate, vale a ordem do cabeçalho
+	sort.SliceStable(opcoes, func(a, b int) bool { return opcoes[a].peso > opcoes[b].peso })
+	return opcoes[0].idioma
+}
+
+// DaRequisicao retorna o idioma escolhido para a requisição, ou o padrão
+func DaRequisicao(c *gin.Context) Idioma {
+	if i, ok := c.Get(ChaveContexto); ok {
+		return i.(Idioma)
+	}
+	return Padrao
+}
@@ -1,15 +1,22 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/jpeccia/quantogasto_app_server/auth"
 	"github.com/jpeccia/quantogasto_app_server/database"
+	"github.com/jpeccia/quantogasto_app_server/erros"
 	"github.com/jpeccia/quantogasto_app_server/handlers"
 	middleware "github.com/jpeccia/quantogasto_app_server/middlewares"
+	"github.com/jpeccia/quantogasto_app_server/repositorio"
 )
 
 func main() {
@@ -29,41 +36,163 @@ func main() {
 		log.Fatal("Erro ao conectar ao banco de dados: ", err)
 	}
 
+	// Subcomando "migrate up|down|status" executa as migrações e encerra
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := executarMigrate(os.Args[2:]); err != nil {
+			log.Fatal("Erro ao executar migrações: ", err)
+		}
+		return
+	}
+
+	// Aplica as migrações pendentes na inicialização (desative com MIGRACOES_AUTOMATICAS=false)
+	if os.Getenv("MIGRACOES_AUTOMATICAS") != "false" {
+		aplicadas, err := database.MigrarUp(context.Background())
+		if err != nil {
+			log.Fatal("Erro ao aplicar migrações: ", err)
+		}
+		log.Printf("Migrações aplicadas na inicialização: %d", aplicadas)
+	}
+
+	// Carrega as chaves de assinatura dos tokens (o servidor não sobe sem nenhuma chave)
+	if err := auth.CarregarChaves(); err != nil {
+		log.Fatal("Erro ao carregar chaves JWT: ", err)
+	}
+
+	// Handlers de usuários, rendas e gastos, com os repositórios sobre o Postgres
+	repos := repositorio.NovoPgx(database.DB)
+	repos.Usuarios = repositorio.ComCacheDeIdioma(repos.Usuarios) // lido a cada requisição autenticada
+	h := handlers.NovoHandler(repos)
+
+	// Campos dos erros de validação com os nomes do JSON
+	erros.ConfigurarValidacao()
+
 	// Inicializa o roteador do Gin
 	r := gin.Default()
 
+	// Identifica cada requisição (X-Request-ID), para relacionar a resposta de erro ao log
+	r.Use(middleware.IDRequisicao())
+
+	// Idioma das mensagens (Accept-Language; nas rotas autenticadas, a preferência do usuário)
+	r.Use(middleware.Idioma())
+
 	// Configura o middleware CORS
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:8081"},         // URL do seu app Expo
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE"},  // Métodos permitidos
-		AllowHeaders:     []string{"Content-Type", "Authorization"}, // Cabeçalhos permitidos
-		AllowCredentials: true,                                      // Se você estiver utilizando cookies ou autenticação
+		AllowOrigins:     []string{"http://localhost:8081"},                                            // URL do seu app Expo
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE"},                            // Métodos permitidos
+		AllowHeaders:     []string{"Content-Type", "Authorization", "X-Request-ID", "Accept-Language"}, // Cabeçalhos permitidos
+		ExposeHeaders:    []string{"X-Request-ID", "Content-Language"},                                 // Cabeçalhos lidos pelo app
+		AllowCredentials: true,                                                                         // Se você estiver utilizando cookies ou autenticação
 	}))
 
 	// Middleware global (opcional)
 	r.Use(gin.Logger())   // Log de todas as requisições
 	r.Use(gin.Recovery()) // Recupera de panics
 
+	// Prazo padrão das requisições (TEMPO_LIMITE_PADRAO); rotas mais pesadas têm prazo próprio
+	r.Use(middleware.TempoLimite("TEMPO_LIMITE_PADRAO", 5*time.Second))
+	relatorio := middleware.TempoLimite("TEMPO_LIMITE_RELATORIOS", 15*time.Second)
+	importacao := middleware.TempoLimite("TEMPO_LIMITE_IMPORTACAO", 30*time.Second)
+	exportacao := middleware.TempoLimite("TEMPO_LIMITE_EXPORTACAO", 2*time.Minute)
+
 	// Rotas de usuários
 	usuarios := r.Group("/usuarios")
 	{
-		usuarios.POST("", handlers.RegistrarUsuario) // Cadastra um novo usuário
+		usuarios.POST("", h.RegistrarUsuario) // Cadastra um novo usuário
+	}
+
+	// Rotas de autenticação
+	autenticacao := r.Group("/auth")
+	{
+		autenticacao.POST("/login", handlers.Login)          // Autentica com e-mail e senha
+		autenticacao.POST("/refresh", handlers.RenovarToken) // Renova os tokens (rotação do refresh token)
 	}
 
 	// Rotas protegidas por autenticação
 	auth := r.Group("/")
-	auth.Use(middleware.Autenticar()) // Middleware de autenticação aplicado
+	auth.Use(middleware.Autenticar())                    // Middleware de autenticação aplicado
+	auth.Use(middleware.IdiomaDoUsuario(repos.Usuarios)) // Idioma preferido do usuário, se houver
 	{
-		auth.PUT("/gastos-fixos/:id", handlers.EditarGastoFixo)             // Edita um gasto fixo
-		auth.PUT("/gastos-variaveis/:id", handlers.EditarGastoVariavel)     // Edita um gasto variável
-		auth.DELETE("/gastos-fixos/:id", handlers.RemoverGastoFixo)         // Remove um gasto fixo
-		auth.DELETE("/gastos-variaveis/:id", handlers.RemoverGastoVariavel) // Remove um gasto variável
-		auth.PUT("/renda", handlers.AdicionarRenda)                         // Adiciona renda
-		auth.POST("/gastos-fixos", handlers.AdicionarGastoFixo)             // Adiciona gasto fixo
-		auth.POST("/gastos-variaveis", handlers.AdicionarGastoVariavel)     // Adiciona gasto variável
-		auth.POST("/usuarios/foto", handlers.UploadFotoPerfil)              // Rota para upload de foto de perfil
-		auth.GET("/resumo", handlers.ObterResumo)                           // Obtém resumo financeiro
-		auth.GET("/usuarios/:id", handlers.ObterUsuario)                    // Obtém dados de um usuário
+		auth.PUT("/gastos-fixos/:id", h.EditarGastoFixo)             // Edita um gasto fixo
+		auth.PUT("/gastos-variaveis/:id", h.EditarGastoVariavel)     // Edita um gasto variável
+		auth.DELETE("/gastos-fixos/:id", h.RemoverGastoFixo)         // Remove um gasto fixo
+		auth.DELETE("/gastos-variaveis/:id", h.RemoverGastoVariavel) // Remove um gasto variável
+		auth.PUT("/renda", h.DefinirSalario)                         // Define o salário mensal (obsoleto: use PATCH /usuarios/me)
+		auth.POST("/gastos-fixos", h.AdicionarGastoFixo)             // Adiciona gasto fixo
+		auth.POST("/gastos-variaveis", h.AdicionarGastoVariavel)     // Adiciona gasto variável
+		auth.POST("/usuarios/foto", h.UploadFotoPerfil)              // Rota para upload de foto de perfil
+		auth.GET("/resumo", relatorio, h.ObterResumo)                // Obtém resumo financeiro
+		auth.GET("/gastos-fixos", h.ListarGastosFixos)               // Lista gastos fixos (paginado)
+		auth.GET("/gastos-variaveis", h.ListarGastosVariaveis)       // Lista gastos variáveis (paginado)
+		auth.GET("/rendas", h.ListarRendas)                          // Lista rendas (paginado)
+		auth.GET("/usuarios/me", h.ObterUsuario)                     // Obtém os dados do usuário autenticado
+		auth.GET("/usuarios/:id", h.ObterUsuario)                    // Idem, somente com o próprio ID (obsoleto: use /usuarios/me)
+		auth.PATCH("/usuarios/me", h.AtualizarUsuario)               // Atualiza o perfil (JSON Merge Patch)
+		auth.PUT("/usuarios/me/idioma", h.DefinirIdioma)             // Define o idioma preferido do usuário
+		auth.GET("/categorias", handlers.ListarCategorias)           // Lista categorias padrão e personalizadas
+		auth.POST("/categorias", handlers.CriarCategoria)            // Cria categoria personalizada
+		auth.PUT("/categorias/:id", handlers.EditarCategoria)        // Edita categoria personalizada
+		auth.DELETE("/categorias/:id", handlers.RemoverCategoria)    // Remove categoria personalizada
+		auth.GET("/orcamentos", h.ObterOrcamentos)                   // Planejado x gasto dos orçamentos do mês
+		auth.PUT("/orcamentos", h.DefinirOrcamento)                  // Cria ou atualiza orçamento
+		auth.DELETE("/orcamentos/:id", handlers.RemoverOrcamento)    // Remove orçamento
+		auth.POST("/auth/logout", handlers.Logout)                   // Revoga o token atual
+		auth.POST("/auth/logout-all", handlers.LogoutTodos)          // Revoga todos os tokens do usuário
+
+		// Compras parceladas
+		auth.POST("/compras-parceladas", h.AdicionarCompraParcelada)             // Registra compra e gera as parcelas
+		auth.GET("/compras-parceladas", handlers.ListarComprasParceladas)        // Lista compras parceladas
+		auth.GET("/compras-parceladas/:id", handlers.ObterCompraParcelada)       // Obtém compra com as parcelas
+		auth.PUT("/compras-parceladas/:id", h.EditarCompraParcelada)             // Edita compra e recalcula parcelas em aberto
+		auth.DELETE("/compras-parceladas/:id", handlers.CancelarCompraParcelada) // Cancela compra e remove parcelas em aberto
+
+		// Cartões de crédito
+		auth.POST("/cartoes", handlers.CriarCartao)                 // Cadastra cartão
+		auth.GET("/cartoes", handlers.ListarCartoes)                // Lista cartões com limite disponível
+		auth.PUT("/cartoes/:id", handlers.EditarCartao)             // Edita cartão e redistribui compras entre faturas
+		auth.DELETE("/cartoes/:id", handlers.RemoverCartao)         // Remove cartão
+		auth.GET("/cartoes/:id/faturas/:mes", handlers.ObterFatura) // Fatura do cartão que vence no mês
+
+		// Contas e transferências
+		auth.GET("/contas", h.ListarContas)                        // Lista contas com o saldo atual
+		auth.POST("/contas", h.CriarConta)                         // Cadastra conta
+		auth.PUT("/contas/:id", h.EditarConta)                     // Edita conta
+		auth.DELETE("/contas/:id", h.RemoverConta)                 // Remove conta sem transferências
+		auth.GET("/transferencias", h.ListarTransferencias)        // Lista transferências
+		auth.POST("/transferencias", h.AdicionarTransferencia)     // Transfere entre contas
+		auth.DELETE("/transferencias/:id", h.RemoverTransferencia) // Remove transferência
+
+		// Rendas
+		auth.POST("/rendas", h.AdicionarRenda)     // Adiciona renda única ou mensal
+		auth.PUT("/rendas/:id", h.EditarRenda)     // Edita renda
+		auth.DELETE("/rendas/:id", h.RemoverRenda) // Remove renda
+
+		// Metas de economia
+		auth.GET("/metas", handlers.ListarMetas)                             // Lista metas com o total aportado
+		auth.POST("/metas", handlers.CriarMeta)                              // Cadastra meta
+		auth.PUT("/metas/:id", handlers.EditarMeta)                          // Edita meta
+		auth.DELETE("/metas/:id", handlers.RemoverMeta)                      // Remove meta e aportes
+		auth.GET("/metas/:id/progresso", h.ObterProgressoMeta)               // Progresso e projeção de conclusão
+		auth.POST("/metas/:id/aportes", handlers.AdicionarAporte)            // Registra aporte na meta
+		auth.DELETE("/metas/:id/aportes/:aporte_id", handlers.RemoverAporte) // Remove aporte
+
+		// Previsão de fluxo de caixa
+		auth.GET("/previsao", relatorio, h.ObterPrevisao) // Projeção dos próximos meses
+
+		// Exportação
+		auth.GET("/exportar", exportacao, handlers.ExportarDados) // Exporta rendas e gastos em CSV
+
+		// Importação de extratos
+		auth.POST("/importar/ofx", importacao, h.ImportarOFX)                      // Lê um extrato OFX e gera a pré-visualização
+		auth.POST("/importar/csv", importacao, h.ImportarCSV)                      // Lê um extrato CSV com um perfil de importação
+		auth.GET("/importacoes/:id", handlers.ObterImportacao)                     // Lançamentos da importação para revisão
+		auth.POST("/importacoes/:id/confirmar", importacao, h.ConfirmarImportacao) // Grava os lançamentos revisados
+		auth.DELETE("/importacoes/:id", handlers.DescartarImportacao)              // Descarta a importação
+
+		// Perfis de importação CSV
+		auth.GET("/perfis-importacao", handlers.ListarPerfisImportacao)         // Lista os mapeamentos de colunas salvos
+		auth.POST("/perfis-importacao", handlers.CriarPerfilImportacao)         // Salva um mapeamento de colunas
+		auth.PUT("/perfis-importacao/:id", handlers.EditarPerfilImportacao)     // Edita um mapeamento de colunas
+		auth.DELETE("/perfis-importacao/:id", handlers.RemoverPerfilImportacao) // Remove um mapeamento de colunas
 	}
 
 	// Inicia o servidor
@@ -76,3 +205,52 @@ func main() {
 		log.Fatal("Erro ao iniciar o servidor: ", err)
 	}
 }
+
+// executarMigrate trata o subcomando "migrate up|down [passos]|status"
+func executarMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: migrate up|down [passos]|status")
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		aplicadas, err := database.MigrarUp(ctx)
+		if err != nil {
+			return err
+		}
+		log.Printf("Migrações aplicadas: %d", aplicadas)
+
+	case "down":
+		passos := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("quantidade de passos inválida: %s", args[1])
+			}
+			passos = n
+		}
+		revertidas, err := database.MigrarDown(ctx, passos)
+		if err != nil {
+			return err
+		}
+		log.Printf("Migrações revertidas: %d", revertidas)
+
+	case "status":
+		status, err := database.StatusMigracoes(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range status {
+			situacao := "pendente"
+			if s.AplicadaEm != nil {
+				situacao = "aplicada em " + s.AplicadaEm.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", s.Versao, s.Nome, situacao)
+		}
+
+	default:
+		return fmt.Errorf("subcomando desconhecido: %s (use up, down ou status)", args[0])
+	}
+	return nil
+}
@@ -1,11 +1,11 @@
 package middleware
 
 import (
-	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jpeccia/quantogasto_app_server/auth"
+	"github.com/jpeccia/quantogasto_app_server/erros"
 )
 
 func Autenticar() gin.HandlerFunc {
@@ -13,15 +13,13 @@ func Autenticar() gin.HandlerFunc {
 		// Obtém o token do cabeçalho Authorization
 		tokenString := c.GetHeader("Authorization")
 		if tokenString == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token não fornecido"})
-			c.Abort()
+			erros.Responder(c, erros.Novo(erros.TokenAusente))
 			return
 		}
 
 		// Verifica se o token começa com o prefixo "Bearer "
 		if !strings.HasPrefix(tokenString, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Formato do token inválido. O token deve ser precedido de 'Bearer '"})
-			c.Abort()
+			erros.Responder(c, erros.Novo(erros.TokenFormatoInvalido))
 			return
 		}
 
@@ -31,13 +29,25 @@ func Autenticar() gin.HandlerFunc {
 		// Valida o token
 		claims, err := auth.ValidarToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
-			c.Abort()
+			erros.Responder(c, erros.Novo(erros.TokenInvalido))
 			return
 		}
 
-		// Armazena o ID do usuário no contexto
+		// Verifica se o token foi revogado (logout ou logout em todos os dispositivos)
+		revogado, err := auth.TokenRevogado(c.Request.Context(), claims)
+		if err != nil {
+			// Responder troca por tempo_esgotado (504) se o prazo da requisição tiver acabado
+			erros.Responder(c, erros.Novo(erros.AutenticacaoIndisponivel).ComCausa(err))
+			return
+		}
+		if revogado {
+			erros.Responder(c, erros.Novo(erros.TokenRevogado))
+			return
+		}
+
+		// Armazena o ID do usuário e os claims no contexto
 		c.Set("usuario_id", claims.UsuarioID)
+		c.Set("claims", claims)
 
 		// Passa para o próximo handler
 		c.Next()
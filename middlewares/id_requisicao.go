@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+)
+
+// Cabeçalho com o ID da requisição, recebido do proxy ou gerado aqui
+const cabecalhoIDRequisicao = "X-Request-ID"
+
+// IDs recebidos fora deste formato são descartados, para não poluir os logs
+var formatoIDRequisicao = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// IDRequisicao identifica a requisição: reaproveita o X-Request-ID enviado pelo cliente ou
+// proxy, se válido, ou gera um novo. O ID volta no cabeçalho da resposta e no corpo dos erros.
+func IDRequisicao() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(cabecalhoIDRequisicao)
+		if !formatoIDRequisicao.MatchString(id) {
+			id = gerarIDRequisicao()
+		}
+
+		c.Set(erros.ChaveIDRequisicao, id)
+		c.Header(cabecalhoIDRequisicao, id)
+		c.Next()
+	}
+}
+
+func gerarIDRequisicao() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpeccia/quantogasto_app_server/erros"
+	"github.com/jpeccia/quantogasto_app_server/idioma"
+	"github.com/jpeccia/quantogasto_app_server/repositorio"
+)
+
+// Idioma escolhe o idioma das mensagens pelo cabeçalho Accept-Language (pt-BR, en ou es;
+// o padrão é pt-BR) e o informa na resposta em Content-Language
+func Idioma() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		definirIdioma(c, idioma.Negociar(c.GetHeader("Accept-Language")))
+		c.Header("Vary", "Accept-Language")
+		c.Next()
+	}
+}
+
+// IdiomaDoUsuario troca o idioma da requisição pelo preferido do usuário autenticado, se ele
+// tiver escolhido um. Deve vir depois de Autenticar. Se a preferência não puder ser lida,
+// segue com o idioma do Accept-Language.
+func IdiomaDoUsuario(usuarios repositorio.Usuarios) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		preferido, err := usuarios.ObterIdioma(c.Request.Context(), c.GetInt("usuario_id"))
+		if err != nil {
+			log.Printf("[%s] Erro ao buscar o idioma do usuário: %v", c.GetString(erros.ChaveIDRequisicao), err)
+		} else if preferido != nil {
+			if i, ok := idioma.Interpretar(*preferido); ok {
+				definirIdioma(c, i)
+			}
+		}
+		c.Next()
+	}
+}
+
+func definirIdioma(c *gin.Context, i idioma.Idioma) {
+	c.Set(idioma.ChaveContexto, i)
+	c.Header("Content-Language", string(i))
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Chave do contexto do Gin com o contexto da requisição antes de qualquer prazo
+const chaveContextoSemPrazo = "contexto_sem_prazo"
+
+// TempoLimite define o prazo das consultas da rota: o contexto da requisição passa a expirar
+// depois da duração lida da variável de ambiente (ex.: "5s", "2m"), ou do padrão. O contexto
+// também é cancelado se o cliente desconectar. Um TempoLimite registrado na rota substitui o
+// do grupo ou do roteador, mesmo que seja maior.
+func TempoLimite(variavel string, padrao time.Duration) gin.HandlerFunc {
+	prazo := padrao
+	if duracao, err := time.ParseDuration(os.Getenv(variavel)); err == nil && duracao > 0 {
+		prazo = duracao
+	}
+
+	return func(c *gin.Context) {
+		// Parte sempre do contexto original, já que um contexto derivado não pode ter prazo maior
+		base := c.Request.Context()
+		if original, ok := c.Get(chaveContextoSemPrazo); ok {
+			base = original.(context.Context)
+		} else {
+			c.Set(chaveContextoSemPrazo, base)
+		}
+
+		ctx, cancelar := context.WithTimeout(base, prazo)
+		defer cancelar()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,214 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Dinheiro representa um valor em reais armazenado como inteiro de centavos,
+// evitando os erros de arredondamento de float64 (ex.: 1234.5699999).
+//
+// No JSON é emitido como número de ponto fixo com duas casas (1234.57) e aceito
+// como número ou string ("1234.57", "1234,57", "1.234,57"). No banco corresponde a
+// uma coluna NUMERIC. Valores informados com frações de centavo são recusados; os lidos
+// do banco são arredondados para o centavo pela regra da ABNT NBR 5891 (meio para o
+// par), usada para o real.
+type Dinheiro int64
+
+// ErroCasasDecimais indica um valor informado com mais de duas casas decimais (ex.: 10.005)
+type ErroCasasDecimais struct {
+	Texto string // texto recebido
+}
+
+func (e *ErroCasasDecimais) Error() string {
+	return fmt.Sprintf("valor monetário com mais de duas casas decimais: %s", e.Texto)
+}
+
+// DinheiroDeCentavos cria um valor a partir de uma quantidade de centavos
+func DinheiroDeCentavos(centavos int64) Dinheiro {
+	return Dinheiro(centavos)
+}
+
+// Centavos retorna o valor em centavos
+func (d Dinheiro) Centavos() int64 {
+	return int64(d)
+}
+
+// String formata o valor com duas casas decimais e ponto como separador (ex.: -1234.50)
+func (d Dinheiro) String() string {
+	sinal := ""
+	abs := uint64(d)
+	if d < 0 {
+		sinal = "-"
+		abs = uint64(-d)
+	}
+	return fmt.Sprintf("%s%d.%02d", sinal, abs/100, abs%100)
+}
+
+// Float64 converte o valor para float64; use apenas para exibição ou proporções, nunca para somas
+func (d Dinheiro) Float64() float64 {
+	return float64(d) / 100
+}
+
+// arredondarCentavos converte r*10^deslocamento para inteiro, arredondando meio para o par
+func arredondarCentavos(r *big.Rat, deslocamento int) (int64, error) {
+	if deslocamento > 0 {
+		r = new(big.Rat).Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(deslocamento)), nil)))
+	}
+
+	num, den := r.Num(), r.Denom()
+	q, resto := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	// Compara o dobro do resto com o denominador para decidir o arredondamento
+	dobro := new(big.Int).Abs(resto)
+	dobro.Lsh(dobro, 1)
+	switch dobro.Cmp(den) {
+	case 1:
+		q.Add(q, big.NewInt(int64(num.Sign())))
+	case 0:
+		if q.Bit(0) == 1 {
+			q.Add(q, big.NewInt(int64(num.Sign())))
+		}
+	}
+
+	if !q.IsInt64() {
+		return 0, fmt.Errorf("valor monetário fora do limite suportado")
+	}
+	return q.Int64(), nil
+}
+
+// ParseDinheiro interpreta um valor decimal em texto. Aceita ponto ou vírgula como separador
+// decimal; se ambos aparecerem, o último é o decimal e o outro é separador de milhar.
+// Frações de centavo retornam *ErroCasasDecimais em vez de serem arredondadas.
+func ParseDinheiro(texto string) (Dinheiro, error) {
+	original := texto
+	texto = strings.TrimSpace(texto)
+	texto = strings.TrimPrefix(texto, "R$")
+	texto = strings.TrimSpace(texto)
+	if texto == "" {
+		return 0, fmt.Errorf("valor monetário vazio")
+	}
+
+	ponto := strings.LastIndex(texto, ".")
+	virgula := strings.LastIndex(texto, ",")
+	switch {
+	case ponto >= 0 && virgula >= 0 && virgula > ponto:
+		texto = strings.ReplaceAll(texto, ".", "")
+		texto = strings.Replace(texto, ",", ".", 1)
+	case ponto >= 0 && virgula >= 0:
+		texto = strings.ReplaceAll(texto, ",", "")
+	case virgula >= 0:
+		if strings.Count(texto, ",") > 1 {
+			return 0, fmt.Errorf("valor monetário inválido: %s", texto)
+		}
+		texto = strings.Replace(texto, ",", ".", 1)
+	}
+
+	// Dígitos, sinal, ponto decimal e expoente (JSON) são os únicos caracteres aceitos
+	for _, r := range texto {
+		if !strings.ContainsRune("0123456789+-.eE", r) {
+			return 0, fmt.Errorf("valor monetário inválido: %s", texto)
+		}
+	}
+
+	r, ok := new(big.Rat).SetString(texto)
+	if !ok {
+		return 0, fmt.Errorf("valor monetário inválido: %s", texto)
+	}
+	if !new(big.Rat).Mul(r, big.NewRat(100, 1)).IsInt() {
+		return 0, &ErroCasasDecimais{Texto: original}
+	}
+	centavos, err := arredondarCentavos(r, 2)
+	if err != nil {
+		return 0, err
+	}
+	return Dinheiro(centavos), nil
+}
+
+// MarshalJSON emite o valor como número de ponto fixo com duas casas
+func (d Dinheiro) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON aceita número ou string
+func (d *Dinheiro) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+
+	texto := string(b)
+	if len(b) > 0 && b[0] == '"' {
+		if err := json.Unmarshal(b, &texto); err != nil {
+			return err
+		}
+	}
+
+	valor, err := ParseDinheiro(texto)
+	if err != nil {
+		return err
+	}
+	*d = valor
+	return nil
+}
+
+// ScanNumeric permite ler colunas NUMERIC diretamente com o pgx
+func (d *Dinheiro) ScanNumeric(n pgtype.Numeric) error {
+	if !n.Valid {
+		return fmt.Errorf("não é possível ler NULL como valor monetário")
+	}
+	if n.NaN || n.InfinityModifier != pgtype.Finite {
+		return fmt.Errorf("valor monetário não finito")
+	}
+
+	// valor = Int * 10^Exp; em centavos, o expoente sobe duas casas
+	r := new(big.Rat).SetInt(n.Int)
+	expoente := int(n.Exp) + 2
+	if expoente < 0 {
+		divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-expoente)), nil)
+		r.Quo(r, new(big.Rat).SetInt(divisor))
+		expoente = 0
+	}
+	centavos, err := arredondarCentavos(r, expoente)
+	if err != nil {
+		return err
+	}
+	*d = Dinheiro(centavos)
+	return nil
+}
+
+// NumericValue permite gravar o valor em colunas NUMERIC com o pgx
+func (d Dinheiro) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: big.NewInt(int64(d)), Exp: -2, Valid: true}, nil
+}
+
+// garante em tempo de compilação a integração com o pgx
+var (
+	_ pgtype.NumericScanner = (*Dinheiro)(nil)
+	_ pgtype.NumericValuer  = Dinheiro(0)
+)
+
+// Dividir reparte o valor em partes iguais; os centavos que sobram vão para as primeiras partes
+func (d Dinheiro) Dividir(partes int) []Dinheiro {
+	if partes <= 0 {
+		return nil
+	}
+	base := int64(d) / int64(partes)
+	resto := int64(d) % int64(partes)
+
+	valores := make([]Dinheiro, partes)
+	for i := range valores {
+		valores[i] = Dinheiro(base)
+		if int64(i) < resto {
+			valores[i]++
+		} else if int64(i) < -resto {
+			valores[i]--
+		}
+	}
+	return valores
+}
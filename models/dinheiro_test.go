@@ -0,0 +1,140 @@
+package models
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestParseDinheiro(t *testing.T) {
+	casos := []struct {
+		texto string
+		valor Dinheiro
+	}{
+		{"1234.57", 123457},
+		{"1234,57", 123457},
+		{"1.234,57", 123457},
+		{"1,234.57", 123457},
+		{"-1.234,56", -123456},
+		{"R$ 10", 1000},
+		{" 0,5 ", 50},
+		{"1.230", 123},
+		{"1e2", 10000},
+		{"-0.01", -1},
+	}
+	for _, caso := range casos {
+		valor, err := ParseDinheiro(caso.texto)
+		if err != nil {
+			t.Errorf("ParseDinheiro(%q): erro inesperado: %v", caso.texto, err)
+			continue
+		}
+		if valor != caso.valor {
+			t.Errorf("ParseDinheiro(%q) = %d, esperado %d", caso.texto, valor, caso.valor)
+		}
+	}
+}
+
+func TestParseDinheiroInvalido(t *testing.T) {
+	casos := []struct {
+		texto string
+		casas bool // espera *ErroCasasDecimais
+	}{
+		{"", false},
+		{"abc", false},
+		{"1,2,3", false},
+		{"1/3", false},
+		{"10.005", true},
+		{"1.234,567", true},
+		{"0,001", true},
+		{"1e-3", true},
+	}
+	for _, caso := range casos {
+		_, err := ParseDinheiro(caso.texto)
+		if err == nil {
+			t.Errorf("ParseDinheiro(%q): esperado erro", caso.texto)
+			continue
+		}
+		var casas *ErroCasasDecimais
+		if errors.As(err, &casas) != caso.casas {
+			t.Errorf("ParseDinheiro(%q): erro %v, esperado ErroCasasDecimais = %v", caso.texto, err, caso.casas)
+		}
+	}
+}
+
+func TestDinheiroString(t *testing.T) {
+	casos := []struct {
+		valor Dinheiro
+		texto string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{123450, "1234.50"},
+		{-123450, "-1234.50"},
+		{-1, "-0.01"},
+	}
+	for _, caso := range casos {
+		if texto := caso.valor.String(); texto != caso.texto {
+			t.Errorf("Dinheiro(%d).String() = %q, esperado %q", caso.valor, texto, caso.texto)
+		}
+	}
+}
+
+func TestScanNumericArredondaMeioParaOPar(t *testing.T) {
+	casos := []struct {
+		inteiro int64
+		exp     int32
+		valor   Dinheiro
+	}{
+		{12345, -3, 1234},   // 12.345 -> 12.34
+		{12355, -3, 1236},   // 12.355 -> 12.36
+		{-12345, -3, -1234}, // -12.345 -> -12.34
+		{-12355, -3, -1236}, // -12.355 -> -12.36
+		{123451, -4, 1235},  // 12.3451 -> 12.35
+		{1234, -2, 1234},
+		{12, 0, 1200},
+	}
+	for _, caso := range casos {
+		var valor Dinheiro
+		n := pgtype.Numeric{Int: big.NewInt(caso.inteiro), Exp: caso.exp, Valid: true}
+		if err := valor.ScanNumeric(n); err != nil {
+			t.Errorf("ScanNumeric(%de%d): erro inesperado: %v", caso.inteiro, caso.exp, err)
+			continue
+		}
+		if valor != caso.valor {
+			t.Errorf("ScanNumeric(%de%d) = %d, esperado %d", caso.inteiro, caso.exp, valor, caso.valor)
+		}
+	}
+}
+
+func TestDividir(t *testing.T) {
+	casos := []struct {
+		valor    Dinheiro
+		partes   int
+		esperado []Dinheiro
+	}{
+		{1000, 3, []Dinheiro{334, 333, 333}},
+		{1000, 4, []Dinheiro{250, 250, 250, 250}},
+		{-1000, 3, []Dinheiro{-334, -333, -333}},
+		{-5, 2, []Dinheiro{-3, -2}},
+		{2, 3, []Dinheiro{1, 1, 0}},
+		{1000, 1, []Dinheiro{1000}},
+		{1000, 0, nil},
+		{1000, -2, nil},
+	}
+	for _, caso := range casos {
+		valores := caso.valor.Dividir(caso.partes)
+		if !reflect.DeepEqual(valores, caso.esperado) {
+			t.Errorf("Dinheiro(%d).Dividir(%d) = %v, esperado %v", caso.valor, caso.partes, valores, caso.esperado)
+		}
+		var soma Dinheiro
+		for _, v := range valores {
+			soma += v
+		}
+		if caso.partes > 0 && soma != caso.valor {
+			t.Errorf("Dinheiro(%d).Dividir(%d) soma %d", caso.valor, caso.partes, soma)
+		}
+	}
+}
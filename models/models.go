@@ -6,35 +6,235 @@ import "time"
 type Usuario struct {
     ID         int       `json:"id"`
     Nome       string    `json:"nome"`
+    Email      string    `json:"email"`       // E-mail usado no login
+    SenhaHash  string    `json:"-"`           // Hash bcrypt da senha (nunca exposto)
     FotoPerfil string    `json:"foto_perfil"` // URL ou caminho da foto (opcional)
     Cargo      string    `json:"cargo"`       // Cargo do usuário (opcional)
-    Renda      float64   `json:"renda"`       // Renda mensal do usuário
+    Renda      Dinheiro  `json:"renda"`       // Soma das rendas mensais ativas (calculada)
+    Idioma     *string   `json:"idioma"`      // Idioma preferido (pt-BR, en ou es); nil segue o Accept-Language
     CreatedAt  time.Time `json:"created_at"`  // Data de criação
 }
 
-// Renda representa a renda mensal de um usuário
+// Renda representa uma entrada de dinheiro do usuário, única ou mensal
 type Renda struct {
-    ID        int       `json:"id"`
-    UsuarioID int       `json:"usuario_id"` // ID do usuário associado
-    Valor     float64   `json:"valor"`      // Valor da renda
-    CreatedAt time.Time `json:"created_at"` // Data de criação
+    ID          int       `json:"id"`
+    UsuarioID   int       `json:"usuario_id"`   // ID do usuário associado
+    Valor       Dinheiro  `json:"valor"`        // Valor da renda
+    Fonte       string    `json:"fonte"`        // salario, freelance, aluguel, decimo_terceiro, ferias ou outra
+    Descricao   string    `json:"descricao"`    // Descrição livre (ex.: nome da empresa)
+    Recorrencia string    `json:"recorrencia"`  // unica ou mensal
+    DataEfetiva string    `json:"data_efetiva"` // Data do recebimento (ou do primeiro, se mensal) (YYYY-MM-DD)
+    DataFim     *string   `json:"data_fim"`     // Último dia em que a renda mensal é recebida; nil para sem fim
+    ContaID     *int      `json:"conta_id"`     // Conta em que a renda entrou (opcional)
+    IDExterno   *string   `json:"id_externo"`   // Identificador no extrato de origem, se importada
+    CreatedAt   time.Time `json:"created_at"`   // Data de criação
 }
 
+// Fontes e recorrências de renda aceitas
+const (
+    FonteSalario        = "salario"
+    FonteFreelance      = "freelance"
+    FonteAluguel        = "aluguel"
+    FonteDecimoTerceiro = "decimo_terceiro"
+    FonteFerias         = "ferias"
+    FonteOutra          = "outra"
+
+    RendaUnica  = "unica"
+    RendaMensal = "mensal"
+)
+
 // GastoFixo representa um gasto fixo de um usuário
 type GastoFixo struct {
-    ID        int       `json:"id"`
-    UsuarioID int       `json:"usuario_id"` // ID do usuário associado
-    Nome      string    `json:"nome"`       // Nome do gasto fixo
-    Valor     float64   `json:"valor"`      // Valor do gasto fixo
-    CreatedAt time.Time `json:"created_at"` // Data de criação
+    ID          int       `json:"id"`
+    UsuarioID   int       `json:"usuario_id"`   // ID do usuário associado
+    Nome        string    `json:"nome"`         // Nome do gasto fixo
+    Valor       Dinheiro  `json:"valor"`        // Valor do gasto fixo
+    CategoriaID *int      `json:"categoria_id"` // Categoria do gasto (opcional)
+    ContaID     *int      `json:"conta_id"`     // Conta de onde o gasto sai (opcional)
+    Inicio      string    `json:"inicio"`       // Mês da primeira cobrança (YYYY-MM)
+    Fim         *string   `json:"fim"`          // Mês da última cobrança (YYYY-MM); nil para sem fim
+    Frequencia  string    `json:"frequencia"`   // mensal, bimestral, trimestral ou anual
+    Dia         *int      `json:"dia"`          // Dia do mês da cobrança (padrão: dia 1)
+    CreatedAt   time.Time `json:"created_at"`   // Data de criação
 }
 
+// Frequências aceitas nos gastos fixos
+const (
+    FrequenciaMensal     = "mensal"
+    FrequenciaBimestral  = "bimestral"
+    FrequenciaTrimestral = "trimestral"
+    FrequenciaAnual      = "anual"
+)
+
 // GastoVariavel representa um gasto variável de um usuário
 type GastoVariavel struct {
+    ID                int       `json:"id"`
+    UsuarioID         int       `json:"usuario_id"`          // ID do usuário associado
+    Nome              string    `json:"nome"`                // Nome do gasto variável
+    Valor             Dinheiro  `json:"valor"`               // Valor do gasto variável
+    Data              string    `json:"data"`                // Data do gasto (formato YYYY-MM-DD)
+    CategoriaID       *int      `json:"categoria_id"`        // Categoria do gasto (opcional)
+    CompraParceladaID *int      `json:"compra_parcelada_id"` // Compra parcelada de origem (se for parcela)
+    ParcelaNumero     *int      `json:"parcela_numero"`      // Número da parcela (1 a N)
+    CartaoID          *int      `json:"cartao_id"`           // Cartão de crédito usado (opcional)
+    VencimentoFatura  *string   `json:"vencimento_fatura"`   // Vencimento da fatura em que a compra caiu (YYYY-MM-DD)
+    ContaID           *int      `json:"conta_id"`            // Conta de onde o gasto sai (opcional)
+    IDExterno         *string   `json:"id_externo"`          // Identificador no extrato de origem, se importado
+    CreatedAt         time.Time `json:"created_at"`          // Data de criação
+}
+
+// Categoria agrupa gastos (ex.: Moradia, Alimentação). As categorias padrão não têm usuário.
+type Categoria struct {
     ID        int       `json:"id"`
-    UsuarioID int       `json:"usuario_id"` // ID do usuário associado
-    Nome      string    `json:"nome"`       // Nome do gasto variável
-    Valor     float64   `json:"valor"`      // Valor do gasto variável
-    Data      string    `json:"data"`       // Data do gasto (formato YYYY-MM-DD)
+    UsuarioID *int      `json:"usuario_id"` // nil para as categorias padrão
+    Nome      string    `json:"nome"`       // Nome da categoria
+    Cor       string    `json:"cor"`        // Cor em hexadecimal (#RRGGBB)
+    Icone     string    `json:"icone"`      // Nome do ícone exibido no app
+    Padrao    bool      `json:"padrao"`     // Indica se é uma categoria padrão (somente leitura)
     CreatedAt time.Time `json:"created_at"` // Data de criação
-}
\ No newline at end of file
+}
+
+// Orcamento é o limite mensal de gastos de um usuário, por categoria ou geral
+type Orcamento struct {
+    ID          int       `json:"id"`
+    UsuarioID   int       `json:"usuario_id"`   // ID do usuário associado
+    CategoriaID *int      `json:"categoria_id"` // nil para o orçamento geral
+    Valor       *Dinheiro `json:"valor"`        // Limite mensal; nil no geral usa a renda do mês
+    CreatedAt   time.Time `json:"created_at"`   // Data de criação
+}
+
+// CompraParcelada é uma compra dividida em parcelas mensais, cada uma gravada como GastoVariavel
+type CompraParcelada struct {
+    ID           int             `json:"id"`
+    UsuarioID    int             `json:"usuario_id"`         // ID do usuário associado
+    Nome         string          `json:"nome"`               // Descrição da compra
+    ValorTotal   Dinheiro        `json:"valor_total"`        // Soma de todas as parcelas
+    NumParcelas  int             `json:"num_parcelas"`       // Quantidade de parcelas
+    PrimeiraData string          `json:"primeira_data"`      // Vencimento da primeira parcela (YYYY-MM-DD)
+    CategoriaID  *int            `json:"categoria_id"`       // Categoria das parcelas (opcional)
+    CartaoID     *int            `json:"cartao_id"`          // Cartão em que a compra foi feita (opcional)
+    ContaID      *int            `json:"conta_id"`           // Conta que paga as parcelas (opcional)
+    CanceladaEm  *time.Time      `json:"cancelada_em"`       // Preenchido quando a compra é cancelada
+    CreatedAt    time.Time       `json:"created_at"`         // Data de criação
+    Parcelas     []GastoVariavel `json:"parcelas,omitempty"` // Parcelas geradas
+}
+
+// Cartao é um cartão de crédito do usuário; as compras nele entram na fatura pelo dia de fechamento
+type Cartao struct {
+    ID            int       `json:"id"`
+    UsuarioID     int       `json:"usuario_id"`     // ID do usuário associado
+    Nome          string    `json:"nome"`           // Nome do cartão
+    Limite        Dinheiro  `json:"limite"`         // Limite de crédito
+    DiaFechamento int       `json:"dia_fechamento"` // Dia do mês em que a fatura fecha
+    DiaVencimento int       `json:"dia_vencimento"` // Dia do mês em que a fatura vence
+    CreatedAt     time.Time `json:"created_at"`     // Data de criação
+}
+
+// Tipos de conta aceitos
+const (
+    ContaCorrente        = "corrente"
+    ContaPoupanca        = "poupanca"
+    ContaDinheiro        = "dinheiro"
+    ContaCarteiraDigital = "carteira_digital"
+)
+
+// Conta é um lugar onde o usuário guarda dinheiro (banco, poupança, carteira)
+type Conta struct {
+    ID           int       `json:"id"`
+    UsuarioID    int       `json:"usuario_id"`    // ID do usuário associado
+    Nome         string    `json:"nome"`          // Nome da conta (ex.: banco)
+    Tipo         string    `json:"tipo"`          // corrente, poupanca, dinheiro ou carteira_digital
+    SaldoInicial Dinheiro  `json:"saldo_inicial"` // Saldo no momento do cadastro
+    CreatedAt    time.Time `json:"created_at"`    // Data de criação
+}
+
+// Transferencia move dinheiro entre duas contas do usuário; não é renda nem gasto
+type Transferencia struct {
+    ID             int       `json:"id"`
+    UsuarioID      int       `json:"usuario_id"`       // ID do usuário associado
+    ContaOrigemID  int       `json:"conta_origem_id"`  // Conta de onde o dinheiro sai
+    ContaDestinoID int       `json:"conta_destino_id"` // Conta para onde o dinheiro vai
+    Valor          Dinheiro  `json:"valor"`            // Valor transferido
+    Data           string    `json:"data"`             // Data da transferência (YYYY-MM-DD)
+    Descricao      string    `json:"descricao"`        // Descrição opcional
+    CreatedAt      time.Time `json:"created_at"`       // Data de criação
+}
+
+// Meta é um objetivo de economia do usuário, com valor alvo e prazo
+type Meta struct {
+    ID               int       `json:"id"`
+    UsuarioID        int       `json:"usuario_id"`         // ID do usuário associado
+    Nome             string    `json:"nome"`               // Objetivo (ex.: viagem, reserva de emergência)
+    ValorAlvo        Dinheiro  `json:"valor_alvo"`         // Valor a ser alcançado
+    Prazo            string    `json:"prazo"`              // Data limite (YYYY-MM-DD)
+    DescontarDoSaldo bool      `json:"descontar_do_saldo"` // Desconta os aportes do saldo disponível no resumo
+    CreatedAt        time.Time `json:"created_at"`         // Data de criação
+}
+
+// AporteMeta é um valor guardado para uma meta
+type AporteMeta struct {
+    ID        int       `json:"id"`
+    MetaID    int       `json:"meta_id"`    // Meta que recebeu o aporte
+    Valor     Dinheiro  `json:"valor"`      // Valor aportado
+    Data      string    `json:"data"`       // Data do aporte (YYYY-MM-DD)
+    CreatedAt time.Time `json:"created_at"` // Data de criação
+}
+
+// Tipos de lançamento lidos de um extrato importado
+const (
+    LancamentoGastoVariavel = "gasto_variavel" // débito
+    LancamentoRenda         = "renda"          // crédito
+)
+
+// LancamentoImportado é uma transação lida de um extrato, ainda não gravada
+type LancamentoImportado struct {
+    IDExterno   string   `json:"id_externo"`   // Conta do banco + identificador da transação no extrato
+    Tipo        string   `json:"tipo"`         // gasto_variavel ou renda
+    Data        string   `json:"data"`         // Data da transação (YYYY-MM-DD)
+    Nome        string   `json:"nome"`         // Descrição da transação no extrato
+    Valor       Dinheiro `json:"valor"`        // Valor absoluto
+    CategoriaID *int     `json:"categoria_id"` // Categoria do gasto (opcional)
+    Duplicado   bool     `json:"duplicado"`    // Já importado anteriormente
+}
+
+// ErroImportacao é uma linha do arquivo importado que foi rejeitada
+type ErroImportacao struct {
+    Linha int    `json:"linha"` // Linha do arquivo (a partir de 1)
+    Erro  string `json:"erro"`  // Motivo da rejeição
+}
+
+// Importacao é um extrato enviado pelo usuário, pendente até ser confirmado
+type Importacao struct {
+    ID           int                   `json:"id"`
+    UsuarioID    int                   `json:"usuario_id"`    // ID do usuário associado
+    Formato      string                `json:"formato"`       // Formato do arquivo (ofx ou csv)
+    ContaID      *int                  `json:"conta_id"`      // Conta dos lançamentos importados (opcional)
+    Lancamentos  []LancamentoImportado `json:"lancamentos"`   // Transações lidas do arquivo
+    Erros        []ErroImportacao      `json:"erros"`         // Linhas que não puderam ser lidas
+    ConfirmadaEm *time.Time            `json:"confirmada_em"` // Momento da confirmação; nil se pendente
+    CreatedAt    time.Time             `json:"created_at"`    // Data de criação
+}
+
+// Convenções de sinal dos valores em extratos CSV
+const (
+    SinalDebitoNegativo = "debito_negativo" // valores negativos são gastos e positivos, rendas
+    SinalDebitoPositivo = "debito_positivo" // valores positivos são gastos (comum em faturas de cartão)
+)
+
+// PerfilImportacao é o mapeamento de colunas do CSV de um banco, salvo pelo usuário
+type PerfilImportacao struct {
+    ID                  int       `json:"id"`
+    UsuarioID           int       `json:"usuario_id"`           // ID do usuário associado
+    Nome                string    `json:"nome"`                 // Nome do perfil (ex.: Nubank conta)
+    Delimitador         string    `json:"delimitador"`          // Separador de campos: , ; | ou tab
+    LinhasIgnoradas     int       `json:"linhas_ignoradas"`     // Linhas descartadas antes do cabeçalho
+    TemCabecalho        bool      `json:"tem_cabecalho"`        // A primeira linha lida é o cabeçalho
+    ColunaData          string    `json:"coluna_data"`          // Nome ou posição (a partir de 1) da coluna
+    FormatoData         string    `json:"formato_data"`         // DD/MM/AAAA, AAAA-MM-DD, MM/DD/AAAA, DD/MM/AA ou DD-MM-AAAA
+    ColunaDescricao     string    `json:"coluna_descricao"`     // Nome ou posição da coluna de descrição
+    ColunaValor         string    `json:"coluna_valor"`         // Nome ou posição da coluna de valor
+    ColunaIdentificador *string   `json:"coluna_identificador"` // Coluna com o ID da transação no banco (opcional)
+    SeparadorDecimal    string    `json:"separador_decimal"`    // , ou .
+    ConvencaoSinal      string    `json:"convencao_sinal"`      // debito_negativo ou debito_positivo
+    CreatedAt           time.Time `json:"created_at"`           // Data de criação
+}
@@ -0,0 +1,74 @@
+package repositorio
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Por quanto tempo o idioma preferido lido do banco é reaproveitado. Define o atraso máximo
+// para que uma troca de idioma feita em outra instância tenha efeito.
+const validadeCacheIdioma = 30 * time.Second
+
+// Limite de entradas no cache antes de descartar as vencidas
+const tamanhoMaximoCacheIdioma = 10000
+
+// entradaIdioma guarda a última leitura do idioma preferido de um usuário
+type entradaIdioma struct {
+	idioma       *string
+	verificadoEm time.Time
+}
+
+// usuariosComCacheDeIdioma reaproveita as leituras de ObterIdioma, feitas a cada requisição
+// autenticada; as demais operações vão direto ao repositório envolvido
+type usuariosComCacheDeIdioma struct {
+	Usuarios
+
+	mu       sync.Mutex
+	entradas map[int]entradaIdioma
+}
+
+// ComCacheDeIdioma envolve o repositório de usuários com um cache em memória do idioma preferido.
+// Trocas feitas por AtualizarIdioma nesta instância valem imediatamente.
+func ComCacheDeIdioma(usuarios Usuarios) Usuarios {
+	return &usuariosComCacheDeIdioma{Usuarios: usuarios, entradas: map[int]entradaIdioma{}}
+}
+
+func (r *usuariosComCacheDeIdioma) ObterIdioma(ctx context.Context, id int) (*string, error) {
+	r.mu.Lock()
+	entrada, ok := r.entradas[id]
+	r.mu.Unlock()
+	if ok && time.Since(entrada.verificadoEm) < validadeCacheIdioma {
+		return entrada.idioma, nil
+	}
+
+	idioma, err := r.Usuarios.ObterIdioma(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.guardar(id, idioma)
+	return idioma, nil
+}
+
+func (r *usuariosComCacheDeIdioma) AtualizarIdioma(ctx context.Context, id int, idioma *string) error {
+	if err := r.Usuarios.AtualizarIdioma(ctx, id, idioma); err != nil {
+		return err
+	}
+	r.guardar(id, idioma)
+	return nil
+}
+
+// guardar registra o idioma lido ou gravado, limpando entradas vencidas se necessário
+func (r *usuariosComCacheDeIdioma) guardar(id int, idioma *string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.entradas) >= tamanhoMaximoCacheIdioma {
+		for chave, e := range r.entradas {
+			if time.Since(e.verificadoEm) >= validadeCacheIdioma {
+				delete(r.entradas, chave)
+			}
+		}
+	}
+	r.entradas[id] = entradaIdioma{idioma: idioma, verificadoEm: time.Now()}
+}
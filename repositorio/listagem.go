@@ -0,0 +1,286 @@
+package repositorio
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+// Tipos dos campos de ordenação: definem como o valor do cursor é comparado
+const (
+	TipoData    = "date"
+	TipoNumero  = "numeric"
+	TipoTexto   = "text"
+	TipoMomento = "timestamptz"
+)
+
+// Campos pelos quais cada listagem pode ser ordenada, com o tipo de cada um
+var (
+	OrdenacoesRendas = map[string]string{
+		"data_efetiva": TipoData,
+		"created_at":   TipoMomento,
+		"valor":        TipoNumero,
+	}
+	OrdenacoesGastosFixos = map[string]string{
+		"created_at": TipoMomento,
+		"valor":      TipoNumero,
+		"nome":       TipoTexto,
+		"inicio":     TipoData,
+	}
+	OrdenacoesGastosVariaveis = map[string]string{
+		"data":       TipoData,
+		"valor":      TipoNumero,
+		"nome":       TipoTexto,
+		"created_at": TipoMomento,
+	}
+	OrdenacoesTransferencias = map[string]string{
+		"data":       TipoData,
+		"valor":      TipoNumero,
+		"created_at": TipoMomento,
+	}
+)
+
+// Listagem são os filtros, a ordenação e a página de uma listagem de lançamentos do usuário.
+// Campos zero ou nil não filtram.
+type Listagem struct {
+	Ordenar     string // campo das Ordenacoes da listagem
+	Crescente   bool
+	Limite      int     // quantidade máxima de itens retornados
+	Apos        *Cursor // continua depois deste item (keyset)
+	De          time.Time
+	Ate         time.Time // exclusivo; De e Ate filtram pela data da listagem
+	ValorMin    *models.Dinheiro
+	ValorMax    *models.Dinheiro
+	CategoriaID *int
+	ContaID     *int
+	Mes         time.Time // primeiro dia do mês; gastos fixos cobrados e gastos variáveis que pesam no mês
+}
+
+// Cursor identifica um item já entregue: o valor de ordenação, em texto, e o ID
+type Cursor struct {
+	Valor string
+	ID    int
+}
+
+// Listado é um item da listagem com o ID e o valor de ordenação em texto, usados no próximo cursor
+type Listado[T any] struct {
+	ID             int
+	Item           T
+	ValorOrdenacao string
+}
+
+// Layouts do texto de um timestamptz no Postgres (::text), com fuso em horas ou horas e minutos
+var layoutsMomento = []string{"2006-01-02 15:04:05.999999999-07", "2006-01-02 15:04:05.999999999-07:00"}
+
+// valorNumerico é o texto de um numeric (::text)
+var valorNumerico = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// ValorCursorValido confere se o valor do cursor pode ser convertido para o tipo do campo de
+// ordenação, para que um cursor adulterado não chegue ao cast da consulta
+func ValorCursorValido(tipo, valor string) bool {
+	switch tipo {
+	case TipoData:
+		_, err := time.Parse(time.DateOnly, valor)
+		return err == nil
+	case TipoNumero:
+		return valorNumerico.MatchString(valor)
+	case TipoMomento:
+		_, ok := lerMomento(valor)
+		return ok
+	}
+	return true
+}
+
+func lerMomento(valor string) (time.Time, bool) {
+	for _, layout := range layoutsMomento {
+		if t, err := time.Parse(layout, valor); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// consultaListagem descreve como listar uma tabela do usuário no Postgres
+type consultaListagem struct {
+	tabela      string            // tabela consultada
+	colunas     string            // colunas do SELECT, na ordem esperada pelo scan
+	colunaData  string            // coluna usada nos filtros De e Ate
+	condicaoMes string            // condição SQL do filtro Mes ($%[1]d é o mês); vazio se não aceita
+	ordenacoes  map[string]string // campos aceitos em Ordenar, que são também os nomes das colunas
+}
+
+// montar gera o SELECT paginado; a última coluna é o valor de ordenação em texto, usado no cursor
+func (q consultaListagem) montar(usuarioID int, l Listagem) (string, []any) {
+	condicoes := []string{"usuario_id = $1"}
+	args := []any{usuarioID}
+
+	adicionar := func(condicao string, valores ...any) {
+		indices := make([]any, len(valores))
+		for i, v := range valores {
+			args = append(args, v)
+			indices[i] = len(args)
+		}
+		condicoes = append(condicoes, fmt.Sprintf(condicao, indices...))
+	}
+
+	if !l.De.IsZero() {
+		adicionar(q.colunaData+" >= $%d", l.De)
+	}
+	if !l.Ate.IsZero() {
+		adicionar(q.colunaData+" < $%d", l.Ate)
+	}
+	if l.ValorMin != nil {
+		adicionar("valor >= $%d", *l.ValorMin)
+	}
+	if l.ValorMax != nil {
+		adicionar("valor <= $%d", *l.ValorMax)
+	}
+	if l.CategoriaID != nil {
+		adicionar("categoria_id = $%d", *l.CategoriaID)
+	}
+	if l.ContaID != nil {
+		adicionar("conta_id = $%d", *l.ContaID)
+	}
+	if !l.Mes.IsZero() && q.condicaoMes != "" {
+		adicionar(q.condicaoMes, l.Mes)
+	}
+
+	ordem := "desc"
+	if l.Crescente {
+		ordem = "asc"
+	}
+
+	// Keyset: continua a partir do par (valor de ordenação, id) do último item entregue
+	if l.Apos != nil {
+		operador := "<"
+		if l.Crescente {
+			operador = ">"
+		}
+		adicionar(fmt.Sprintf("(%s, id) %s ($%%d::text::%s, $%%d)", l.Ordenar, operador, q.ordenacoes[l.Ordenar]), l.Apos.Valor, l.Apos.ID)
+	}
+
+	query := fmt.Sprintf(
+		"SELECT %s, (%s)::text FROM %s WHERE %s ORDER BY %s %s, id %s LIMIT %d",
+		q.colunas, l.Ordenar, q.tabela, strings.Join(condicoes, " AND "),
+		l.Ordenar, ordem, ordem, l.Limite,
+	)
+	return query, args
+}
+
+// listarPgx executa a listagem. A função scan recebe o destino do item e o destino do valor
+// de ordenação (última coluna) e retorna o ID do item.
+func listarPgx[T any](ctx context.Context, db banco, q consultaListagem, usuarioID int, l Listagem,
+	scan func(rows pgx.Rows, item *T, valorOrdenacao *string) (int, error)) ([]Listado[T], error) {
+	if _, ok := q.ordenacoes[l.Ordenar]; !ok {
+		return nil, fmt.Errorf("campo de ordenação desconhecido: %q", l.Ordenar)
+	}
+
+	query, args := q.montar(usuarioID, l)
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	itens := []Listado[T]{}
+	for rows.Next() {
+		var item Listado[T]
+		id, err := scan(rows, &item.Item, &item.ValorOrdenacao)
+		if err != nil {
+			return nil, err
+		}
+		item.ID = id
+		itens = append(itens, item)
+	}
+	return itens, rows.Err()
+}
+
+// candidatoListagem é um registro em memória com os campos usados nos filtros e na ordenação
+type candidatoListagem[T any] struct {
+	id          int
+	data        time.Time // data usada nos filtros De e Ate
+	valor       models.Dinheiro
+	categoriaID *int
+	contaID     *int
+	noMes       func(mes time.Time) bool // nil se a listagem não filtra por mês
+	ordenacao   map[string]string        // valor de cada campo de ordenação, no texto do Postgres
+	item        T
+}
+
+// compararValores compara dois valores de ordenação em texto conforme o tipo do campo
+func compararValores(tipo, a, b string) int {
+	switch tipo {
+	case TipoData:
+		ta, _ := time.Parse(time.DateOnly, a)
+		tb, _ := time.Parse(time.DateOnly, b)
+		return ta.Compare(tb)
+	case TipoMomento:
+		ta, _ := lerMomento(a)
+		tb, _ := lerMomento(b)
+		return ta.Compare(tb)
+	case TipoNumero:
+		ra, _ := new(big.Rat).SetString(a)
+		rb, _ := new(big.Rat).SetString(b)
+		if ra == nil || rb == nil {
+			return strings.Compare(a, b)
+		}
+		return ra.Cmp(rb)
+	}
+	return strings.Compare(a, b)
+}
+
+// listarMemoria aplica à lista em memória os mesmos filtros, ordenação e keyset de consultaListagem.montar
+func listarMemoria[T any](candidatos []candidatoListagem[T], ordenacoes map[string]string, l Listagem) ([]Listado[T], error) {
+	tipo, ok := ordenacoes[l.Ordenar]
+	if !ok {
+		return nil, fmt.Errorf("campo de ordenação desconhecido: %q", l.Ordenar)
+	}
+
+	// comparar ordena por (valor de ordenação, id), na ordem pedida
+	comparar := func(valor string, id int, outro string, outroID int) int {
+		r := compararValores(tipo, valor, outro)
+		if r == 0 {
+			r = id - outroID
+		}
+		if !l.Crescente {
+			r = -r
+		}
+		return r
+	}
+
+	filtrados := []candidatoListagem[T]{}
+	for _, c := range candidatos {
+		switch {
+		case !l.De.IsZero() && c.data.Before(l.De),
+			!l.Ate.IsZero() && !c.data.Before(l.Ate),
+			l.ValorMin != nil && c.valor < *l.ValorMin,
+			l.ValorMax != nil && c.valor > *l.ValorMax,
+			l.CategoriaID != nil && (c.categoriaID == nil || *c.categoriaID != *l.CategoriaID),
+			l.ContaID != nil && (c.contaID == nil || *c.contaID != *l.ContaID),
+			!l.Mes.IsZero() && c.noMes != nil && !c.noMes(l.Mes),
+			l.Apos != nil && comparar(c.ordenacao[l.Ordenar], c.id, l.Apos.Valor, l.Apos.ID) <= 0:
+			continue
+		}
+		filtrados = append(filtrados, c)
+	}
+	sort.Slice(filtrados, func(i, j int) bool {
+		a, b := filtrados[i], filtrados[j]
+		return comparar(a.ordenacao[l.Ordenar], a.id, b.ordenacao[l.Ordenar], b.id) < 0
+	})
+
+	itens := []Listado[T]{}
+	for _, c := range filtrados {
+		if len(itens) == l.Limite {
+			break
+		}
+		itens = append(itens, Listado[T]{ID: c.id, Item: c.item, ValorOrdenacao: c.ordenacao[l.Ordenar]})
+	}
+	return itens, nil
+}
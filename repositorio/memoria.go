@@ -0,0 +1,729 @@
+package repositorio
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+// memoria guarda os registros de todos os repositórios em memória, compartilhados
+// entre eles como as tabelas de um mesmo banco
+type memoria struct {
+	mu              sync.Mutex
+	ultimoID        int
+	usuarios        map[int]models.Usuario
+	rendas          map[int]registroMemoria[DadosRenda]
+	gastosFixos     map[int]registroMemoria[DadosGastoFixo]
+	gastosVariaveis map[int]registroMemoria[DadosGastoVariavel]
+	contas          map[int]registroMemoria[DadosConta]
+	transferencias  map[int]registroMemoria[DadosTransferencia]
+}
+
+// registroMemoria é um registro de um usuário
+type registroMemoria[T any] struct {
+	usuarioID int
+	dados     T
+	criadoEm  time.Time
+}
+
+// NovoMemoria cria repositórios vazios em memória, para testes e desenvolvimento sem banco
+func NovoMemoria() Repositorios {
+	m := &memoria{
+		usuarios:        map[int]models.Usuario{},
+		rendas:          map[int]registroMemoria[DadosRenda]{},
+		gastosFixos:     map[int]registroMemoria[DadosGastoFixo]{},
+		gastosVariaveis: map[int]registroMemoria[DadosGastoVariavel]{},
+		contas:          map[int]registroMemoria[DadosConta]{},
+		transferencias:  map[int]registroMemoria[DadosTransferencia]{},
+	}
+	return Repositorios{
+		Usuarios:        usuariosMemoria{m},
+		Rendas:          rendasMemoria{m},
+		GastosFixos:     gastosFixosMemoria{m},
+		GastosVariaveis: gastosVariaveisMemoria{m},
+		Categorias:      categoriasMemoria{},
+		Contas:          contasMemoria{m},
+		Transferencias:  transferenciasMemoria{m},
+		Cartoes:         cartoesMemoria{},
+		Metas:           metasMemoria{},
+	}
+}
+
+// proximoID gera IDs crescentes, como uma sequência; deve ser chamado com o mutex travado
+func (m *memoria) proximoID() int {
+	m.ultimoID++
+	return m.ultimoID
+}
+
+// hoje retorna a data atual sem horário, como o CURRENT_DATE
+func hoje() time.Time {
+	agora := time.Now().UTC()
+	return time.Date(agora.Year(), agora.Month(), agora.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// layoutMomento é o texto de um timestamptz no Postgres (::text) em UTC, usado na ordenação
+const layoutMomento = "2006-01-02 15:04:05.999999-07"
+
+// formatarOpcional formata uma data opcional como as colunas lidas com to_char
+func formatarOpcional(t *time.Time, layout string) *string {
+	if t == nil {
+		return nil
+	}
+	texto := t.Format(layout)
+	return &texto
+}
+
+// registrosDoUsuario retorna os IDs dos registros do usuário em ordem crescente; deve ser chamado
+// com o mutex travado
+func registrosDoUsuario[T any](tabela map[int]registroMemoria[T], usuarioID int) []int {
+	var ids []int
+	for id, r := range tabela {
+		if r.usuarioID == usuarioID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// criarRegistro, atualizarRegistro e removerRegistro implementam o CRUD comum às tabelas
+func criarRegistro[T any](m *memoria, tabela map[int]registroMemoria[T], usuarioID int, dados T) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.proximoID()
+	tabela[id] = registroMemoria[T]{usuarioID, dados, time.Now()}
+	return id
+}
+
+func atualizarRegistro[T any](m *memoria, tabela map[int]registroMemoria[T], usuarioID, id int, dados T) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := tabela[id]
+	if !ok || r.usuarioID != usuarioID {
+		return ErrNaoEncontrado
+	}
+	r.dados = dados
+	tabela[id] = r
+	return nil
+}
+
+func removerRegistro[T any](m *memoria, tabela map[int]registroMemoria[T], usuarioID, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if r, ok := tabela[id]; !ok || r.usuarioID != usuarioID {
+		return ErrNaoEncontrado
+	}
+	delete(tabela, id)
+	return nil
+}
+
+type usuariosMemoria struct{ m *memoria }
+
+func (r usuariosMemoria) Criar(ctx context.Context, u NovoUsuario) (int, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	for _, existente := range r.m.usuarios {
+		if strings.EqualFold(existente.Email, u.Email) {
+			return 0, ErrDuplicado
+		}
+	}
+
+	id := r.m.proximoID()
+	r.m.usuarios[id] = models.Usuario{
+		ID: id, Nome: u.Nome, Email: u.Email, SenhaHash: u.SenhaHash,
+		FotoPerfil: u.FotoPerfil, Cargo: u.Cargo, Idioma: u.Idioma, CreatedAt: time.Now(),
+	}
+	if u.Renda > 0 {
+		r.m.rendas[r.m.proximoID()] = registroMemoria[DadosRenda]{id, DadosRenda{
+			Valor: u.Renda, Fonte: models.FonteSalario, Recorrencia: models.RendaMensal, DataEfetiva: hoje(),
+		}, time.Now()}
+	}
+	return id, nil
+}
+
+func (r usuariosMemoria) Obter(ctx context.Context, id int) (models.Usuario, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	usuario, ok := r.m.usuarios[id]
+	if !ok {
+		return usuario, ErrNaoEncontrado
+	}
+
+	// Renda mensal: soma das rendas mensais que estão valendo hoje
+	dia := hoje()
+	for _, renda := range r.m.rendas {
+		d := renda.dados
+		if renda.usuarioID == id && d.Recorrencia == models.RendaMensal && !d.DataEfetiva.After(dia) &&
+			(d.DataFim == nil || !d.DataFim.Before(dia)) {
+			usuario.Renda += d.Valor
+		}
+	}
+	return usuario, nil
+}
+
+func (r usuariosMemoria) Atualizar(ctx context.Context, id int, a AlteracoesUsuario) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	usuario, ok := r.m.usuarios[id]
+	if !ok {
+		return ErrNaoEncontrado
+	}
+	if a.Nome != nil {
+		usuario.Nome = *a.Nome
+	}
+	if a.Cargo != nil {
+		usuario.Cargo = *a.Cargo
+	}
+	if a.FotoPerfil != nil {
+		usuario.FotoPerfil = *a.FotoPerfil
+	}
+	r.m.usuarios[id] = usuario
+	if a.Renda != nil {
+		r.definirSalario(id, *a.Renda)
+	}
+	return nil
+}
+
+// definirSalario segue as regras de definirSalario do repositório pgx; deve ser chamado com o
+// mutex travado
+func (r usuariosMemoria) definirSalario(usuarioID int, valor models.Dinheiro) {
+	dia := hoje()
+	recente := 0
+	for rendaID, renda := range r.m.rendas {
+		d := renda.dados
+		if renda.usuarioID != usuarioID || d.Fonte != models.FonteSalario || d.Recorrencia != models.RendaMensal ||
+			d.DataEfetiva.After(dia) || (d.DataFim != nil && d.DataFim.Before(dia)) {
+			continue
+		}
+
+		switch {
+		case valor > 0:
+			atual := r.m.rendas[recente].dados
+			if recente == 0 || d.DataEfetiva.After(atual.DataEfetiva) || (d.DataEfetiva.Equal(atual.DataEfetiva) && rendaID > recente) {
+				recente = rendaID
+			}
+		case d.DataEfetiva.Equal(dia):
+			delete(r.m.rendas, rendaID)
+		default:
+			ontem := dia.AddDate(0, 0, -1)
+			d.DataFim = &ontem
+			renda.dados = d
+			r.m.rendas[rendaID] = renda
+		}
+	}
+
+	if valor == 0 {
+		return
+	}
+	if recente == 0 {
+		r.m.rendas[r.m.proximoID()] = registroMemoria[DadosRenda]{usuarioID, DadosRenda{
+			Valor: valor, Fonte: models.FonteSalario, Recorrencia: models.RendaMensal, DataEfetiva: dia,
+		}, time.Now()}
+		return
+	}
+
+	renda := r.m.rendas[recente]
+	if renda.dados.Valor == valor {
+		return
+	}
+	if renda.dados.DataEfetiva.Equal(dia) {
+		renda.dados.Valor = valor
+		r.m.rendas[recente] = renda
+		return
+	}
+	novo := renda.dados
+	novo.Valor = valor
+	novo.DataEfetiva = dia
+	ontem := dia.AddDate(0, 0, -1)
+	renda.dados.DataFim = &ontem
+	r.m.rendas[recente] = renda
+	r.m.rendas[r.m.proximoID()] = registroMemoria[DadosRenda]{usuarioID, novo, time.Now()}
+}
+
+func (r usuariosMemoria) AtualizarFotoPerfil(ctx context.Context, id int, caminho string) error {
+	return r.Atualizar(ctx, id, AlteracoesUsuario{FotoPerfil: &caminho})
+}
+
+func (r usuariosMemoria) ObterIdioma(ctx context.Context, id int) (*string, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	usuario, ok := r.m.usuarios[id]
+	if !ok {
+		return nil, ErrNaoEncontrado
+	}
+	return usuario.Idioma, nil
+}
+
+func (r usuariosMemoria) AtualizarIdioma(ctx context.Context, id int, idioma *string) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	usuario, ok := r.m.usuarios[id]
+	if !ok {
+		return ErrNaoEncontrado
+	}
+	usuario.Idioma = idioma
+	r.m.usuarios[id] = usuario
+	return nil
+}
+
+type rendasMemoria struct{ m *memoria }
+
+func (r rendasMemoria) Criar(ctx context.Context, usuarioID int, d DadosRenda) (int, error) {
+	return criarRegistro(r.m, r.m.rendas, usuarioID, d), nil
+}
+
+func (r rendasMemoria) Atualizar(ctx context.Context, usuarioID, id int, d DadosRenda) error {
+	return atualizarRegistro(r.m, r.m.rendas, usuarioID, id, d)
+}
+
+func (r rendasMemoria) Remover(ctx context.Context, usuarioID, id int) error {
+	return removerRegistro(r.m, r.m.rendas, usuarioID, id)
+}
+
+func (r rendasMemoria) Listar(ctx context.Context, usuarioID int, l Listagem) ([]Listado[models.Renda], error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	var candidatos []candidatoListagem[models.Renda]
+	for _, id := range registrosDoUsuario(r.m.rendas, usuarioID) {
+		registro := r.m.rendas[id]
+		d := registro.dados
+		renda := models.Renda{
+			ID: id, UsuarioID: usuarioID, Valor: d.Valor, Fonte: d.Fonte, Descricao: d.Descricao, Recorrencia: d.Recorrencia,
+			DataEfetiva: d.DataEfetiva.Format(time.DateOnly), DataFim: formatarOpcional(d.DataFim, time.DateOnly),
+			ContaID: d.ContaID, CreatedAt: registro.criadoEm,
+		}
+		candidatos = append(candidatos, candidatoListagem[models.Renda]{
+			id: id, data: d.DataEfetiva, valor: d.Valor, contaID: d.ContaID,
+			ordenacao: map[string]string{
+				"data_efetiva": renda.DataEfetiva,
+				"created_at":   registro.criadoEm.UTC().Format(layoutMomento),
+				"valor":        d.Valor.String(),
+			},
+			item: renda,
+		})
+	}
+	return listarMemoria(candidatos, OrdenacoesRendas, l)
+}
+
+func (r rendasMemoria) NoPeriodo(ctx context.Context, usuarioID int, inicio, fim time.Time) ([]RendaRegistrada, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	var rendas []RendaRegistrada
+	for _, id := range registrosDoUsuario(r.m.rendas, usuarioID) {
+		d := r.m.rendas[id].dados
+		if !d.DataEfetiva.Before(fim) {
+			continue
+		}
+		if (d.Recorrencia == models.RendaUnica && !d.DataEfetiva.Before(inicio)) ||
+			(d.Recorrencia == models.RendaMensal && (d.DataFim == nil || !d.DataFim.Before(inicio))) {
+			rendas = append(rendas, RendaRegistrada{ID: id, DadosRenda: d})
+		}
+	}
+	return rendas, nil
+}
+
+type gastosFixosMemoria struct{ m *memoria }
+
+func (r gastosFixosMemoria) Criar(ctx context.Context, usuarioID int, d DadosGastoFixo) (int, error) {
+	if d.Inicio == nil {
+		dia := hoje()
+		inicio := time.Date(dia.Year(), dia.Month(), 1, 0, 0, 0, 0, time.UTC)
+		d.Inicio = &inicio
+	}
+	if d.Fim != nil && d.Fim.Before(*d.Inicio) {
+		return 0, ErrIntervaloInvalido
+	}
+	return criarRegistro(r.m, r.m.gastosFixos, usuarioID, d), nil
+}
+
+func (r gastosFixosMemoria) Atualizar(ctx context.Context, usuarioID, id int, d DadosGastoFixo) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	atual, ok := r.m.gastosFixos[id]
+	if !ok || atual.usuarioID != usuarioID {
+		return ErrNaoEncontrado
+	}
+	if d.Inicio == nil {
+		d.Inicio = atual.dados.Inicio
+	}
+	if d.Fim != nil && d.Fim.Before(*d.Inicio) {
+		return ErrIntervaloInvalido
+	}
+	atual.dados = d
+	r.m.gastosFixos[id] = atual
+	return nil
+}
+
+func (r gastosFixosMemoria) Remover(ctx context.Context, usuarioID, id int) error {
+	return removerRegistro(r.m, r.m.gastosFixos, usuarioID, id)
+}
+
+// Intervalo em meses entre as cobranças de cada frequência
+var mesesFrequencia = map[string]int{
+	models.FrequenciaBimestral:  2,
+	models.FrequenciaTrimestral: 3,
+	models.FrequenciaAnual:      12,
+}
+
+// cobradoNoMes segue a regra de condicaoGastoFixoNoMes; mes é o primeiro dia do mês
+func cobradoNoMes(d DadosGastoFixo, mes time.Time) bool {
+	if d.Inicio.After(mes) || (d.Fim != nil && d.Fim.Before(mes)) {
+		return false
+	}
+	intervalo := mesesFrequencia[d.Frequencia]
+	if intervalo == 0 {
+		intervalo = 1
+	}
+	meses := (mes.Year()-d.Inicio.Year())*12 + int(mes.Month()) - int(d.Inicio.Month())
+	return meses%intervalo == 0
+}
+
+func (r gastosFixosMemoria) Listar(ctx context.Context, usuarioID int, l Listagem) ([]Listado[models.GastoFixo], error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	var candidatos []candidatoListagem[models.GastoFixo]
+	for _, id := range registrosDoUsuario(r.m.gastosFixos, usuarioID) {
+		registro := r.m.gastosFixos[id]
+		d := registro.dados
+		gasto := models.GastoFixo{
+			ID: id, UsuarioID: usuarioID, Nome: d.Nome, Valor: d.Valor, CategoriaID: d.CategoriaID, ContaID: d.ContaID,
+			Inicio: d.Inicio.Format("2006-01"), Fim: formatarOpcional(d.Fim, "2006-01"),
+			Frequencia: d.Frequencia, Dia: d.Dia, CreatedAt: registro.criadoEm,
+		}
+		candidatos = append(candidatos, candidatoListagem[models.GastoFixo]{
+			id: id, data: registro.criadoEm, valor: d.Valor, categoriaID: d.CategoriaID, contaID: d.ContaID,
+			noMes: func(mes time.Time) bool { return cobradoNoMes(d, mes) },
+			ordenacao: map[string]string{
+				"created_at": registro.criadoEm.UTC().Format(layoutMomento),
+				"valor":      d.Valor.String(),
+				"nome":       d.Nome,
+				"inicio":     d.Inicio.Format(time.DateOnly),
+			},
+			item: gasto,
+		})
+	}
+	return listarMemoria(candidatos, OrdenacoesGastosFixos, l)
+}
+
+func (r gastosFixosMemoria) NoPeriodo(ctx context.Context, usuarioID int, inicio, fim time.Time) ([]GastoFixoRegistrado, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	mesInicio := time.Date(inicio.Year(), inicio.Month(), 1, 0, 0, 0, 0, inicio.Location())
+	var gastos []GastoFixoRegistrado
+	for _, id := range registrosDoUsuario(r.m.gastosFixos, usuarioID) {
+		d := r.m.gastosFixos[id].dados
+		if d.Inicio.Before(fim) && (d.Fim == nil || !d.Fim.Before(mesInicio)) {
+			gastos = append(gastos, GastoFixoRegistrado{ID: id, DadosGastoFixo: d})
+		}
+	}
+	return gastos, nil
+}
+
+type gastosVariaveisMemoria struct{ m *memoria }
+
+func (r gastosVariaveisMemoria) Criar(ctx context.Context, usuarioID int, d DadosGastoVariavel) (int, error) {
+	return criarRegistro(r.m, r.m.gastosVariaveis, usuarioID, d), nil
+}
+
+func (r gastosVariaveisMemoria) Atualizar(ctx context.Context, usuarioID, id int, d DadosGastoVariavel) error {
+	return atualizarRegistro(r.m, r.m.gastosVariaveis, usuarioID, id, d)
+}
+
+func (r gastosVariaveisMemoria) Remover(ctx context.Context, usuarioID, id int) error {
+	return removerRegistro(r.m, r.m.gastosVariaveis, usuarioID, id)
+}
+
+func (r gastosVariaveisMemoria) Listar(ctx context.Context, usuarioID int, l Listagem) ([]Listado[models.GastoVariavel], error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	var candidatos []candidatoListagem[models.GastoVariavel]
+	for _, id := range registrosDoUsuario(r.m.gastosVariaveis, usuarioID) {
+		registro := r.m.gastosVariaveis[id]
+		d := registro.dados
+		gasto := models.GastoVariavel{
+			ID: id, UsuarioID: usuarioID, Nome: d.Nome, Valor: d.Valor, Data: d.Data.Format(time.DateOnly),
+			CategoriaID: d.CategoriaID, CartaoID: d.CartaoID, VencimentoFatura: formatarOpcional(d.VencimentoFatura, time.DateOnly),
+			ContaID: d.ContaID, CreatedAt: registro.criadoEm,
+		}
+		candidatos = append(candidatos, candidatoListagem[models.GastoVariavel]{
+			id: id, data: d.Data, valor: d.Valor, categoriaID: d.CategoriaID, contaID: d.ContaID,
+			noMes: func(mes time.Time) bool { return mesDoGastoVariavel(d).Equal(mes) },
+			ordenacao: map[string]string{
+				"data":       gasto.Data,
+				"valor":      d.Valor.String(),
+				"nome":       d.Nome,
+				"created_at": registro.criadoEm.UTC().Format(layoutMomento),
+			},
+			item: gasto,
+		})
+	}
+	return listarMemoria(candidatos, OrdenacoesGastosVariaveis, l)
+}
+
+func (r gastosVariaveisMemoria) TotaisPorMes(ctx context.Context, usuarioID int, inicio, fim time.Time) ([]TotalGastosVariaveis, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	type chave struct {
+		categoriaID int
+		mes         time.Time
+	}
+	somas := map[chave]models.Dinheiro{}
+	for _, id := range registrosDoUsuario(r.m.gastosVariaveis, usuarioID) {
+		d := r.m.gastosVariaveis[id].dados
+		data := d.Data
+		if d.VencimentoFatura != nil {
+			data = *d.VencimentoFatura
+		}
+		if data.Before(inicio) || !data.Before(fim) {
+			continue
+		}
+		k := chave{mes: mesDoGastoVariavel(d)}
+		if d.CategoriaID != nil {
+			k.categoriaID = *d.CategoriaID
+		}
+		somas[k] += d.Valor
+	}
+
+	var totais []TotalGastosVariaveis
+	for k, total := range somas {
+		totais = append(totais, TotalGastosVariaveis{CategoriaID: k.categoriaID, Mes: k.mes, Total: total})
+	}
+	return totais, nil
+}
+
+// mesDoGastoVariavel é o primeiro dia do mês em que o gasto pesa: o do vencimento da fatura,
+// nas compras no cartão, ou o da data do gasto
+func mesDoGastoVariavel(d DadosGastoVariavel) time.Time {
+	data := d.Data
+	if d.VencimentoFatura != nil {
+		data = *d.VencimentoFatura
+	}
+	return time.Date(data.Year(), data.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// categoriasPadrao são as categorias criadas pela migração 0006, com IDs de 1 em diante
+var categoriasPadrao = []models.Categoria{
+	{ID: 1, Nome: "Moradia", Cor: "#5C6BC0", Icone: "home", Padrao: true},
+	{ID: 2, Nome: "Alimentação", Cor: "#EF6C00", Icone: "food", Padrao: true},
+	{ID: 3, Nome: "Transporte", Cor: "#0288D1", Icone: "car", Padrao: true},
+	{ID: 4, Nome: "Saúde", Cor: "#E53935", Icone: "heart-pulse", Padrao: true},
+	{ID: 5, Nome: "Educação", Cor: "#8E24AA", Icone: "school", Padrao: true},
+	{ID: 6, Nome: "Lazer", Cor: "#43A047", Icone: "gamepad-variant", Padrao: true},
+	{ID: 7, Nome: "Compras", Cor: "#D81B60", Icone: "shopping", Padrao: true},
+	{ID: 8, Nome: "Contas", Cor: "#6D4C41", Icone: "file-document", Padrao: true},
+	{ID: 9, Nome: "Outros", Cor: "#757575", Icone: "dots-horizontal", Padrao: true},
+}
+
+// categoriasMemoria tem apenas as categorias padrão
+type categoriasMemoria struct{}
+
+func (categoriasMemoria) Disponivel(ctx context.Context, usuarioID, id int) (bool, error) {
+	return id >= 1 && id <= len(categoriasPadrao), nil
+}
+
+func (categoriasMemoria) Buscar(ctx context.Context, ids []int) ([]models.Categoria, error) {
+	var categorias []models.Categoria
+	for _, id := range ids {
+		if id >= 1 && id <= len(categoriasPadrao) {
+			categorias = append(categorias, categoriasPadrao[id-1])
+		}
+	}
+	return categorias, nil
+}
+
+type contasMemoria struct{ m *memoria }
+
+// nomeEmUso indica se o usuário tem outra conta com o nome, sem diferenciar maiúsculas, como o
+// índice único da migração 0010; deve ser chamado com o mutex travado
+func (r contasMemoria) nomeEmUso(usuarioID, id int, nome string) bool {
+	for _, outra := range registrosDoUsuario(r.m.contas, usuarioID) {
+		if outra != id && strings.EqualFold(r.m.contas[outra].dados.Nome, nome) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r contasMemoria) Criar(ctx context.Context, usuarioID int, d DadosConta) (int, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	if r.nomeEmUso(usuarioID, 0, d.Nome) {
+		return 0, ErrDuplicado
+	}
+	id := r.m.proximoID()
+	r.m.contas[id] = registroMemoria[DadosConta]{usuarioID, d, time.Now()}
+	return id, nil
+}
+
+func (r contasMemoria) Atualizar(ctx context.Context, usuarioID, id int, d DadosConta) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	c, ok := r.m.contas[id]
+	if !ok || c.usuarioID != usuarioID {
+		return ErrNaoEncontrado
+	}
+	if r.nomeEmUso(usuarioID, id, d.Nome) {
+		return ErrDuplicado
+	}
+	c.dados = d
+	r.m.contas[id] = c
+	return nil
+}
+
+// desvincularConta tira a conta removida dos registros da tabela, como o ON DELETE SET NULL
+func desvincularConta[T any](tabela map[int]registroMemoria[T], contaID int, conta func(*T) **int) {
+	for id, r := range tabela {
+		if c := conta(&r.dados); *c != nil && **c == contaID {
+			*c = nil
+			tabela[id] = r
+		}
+	}
+}
+
+func (r contasMemoria) Remover(ctx context.Context, usuarioID, id int) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	if c, ok := r.m.contas[id]; !ok || c.usuarioID != usuarioID {
+		return ErrNaoEncontrado
+	}
+	for _, t := range r.m.transferencias {
+		if t.dados.ContaOrigemID == id || t.dados.ContaDestinoID == id {
+			return ErrEmUso
+		}
+	}
+	delete(r.m.contas, id)
+	desvincularConta(r.m.rendas, id, func(d *DadosRenda) **int { return &d.ContaID })
+	desvincularConta(r.m.gastosFixos, id, func(d *DadosGastoFixo) **int { return &d.ContaID })
+	desvincularConta(r.m.gastosVariaveis, id, func(d *DadosGastoVariavel) **int { return &d.ContaID })
+	return nil
+}
+
+func (r contasMemoria) Pertence(ctx context.Context, usuarioID, id int) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	c, ok := r.m.contas[id]
+	return ok && c.usuarioID == usuarioID, nil
+}
+
+func (r contasMemoria) ComMovimentos(ctx context.Context, usuarioID int) ([]ContaComMovimentos, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	movimentos := map[int]*ContaComMovimentos{}
+	var contas []*ContaComMovimentos
+	for _, id := range registrosDoUsuario(r.m.contas, usuarioID) {
+		registro := r.m.contas[id]
+		c := &ContaComMovimentos{Conta: models.Conta{
+			ID: id, UsuarioID: usuarioID, Nome: registro.dados.Nome, Tipo: registro.dados.Tipo,
+			SaldoInicial: registro.dados.SaldoInicial, CreatedAt: registro.criadoEm,
+		}}
+		movimentos[id] = c
+		contas = append(contas, c)
+	}
+
+	ate := hoje()
+	for _, g := range r.m.gastosVariaveis {
+		data := g.dados.Data
+		if g.dados.VencimentoFatura != nil {
+			data = *g.dados.VencimentoFatura
+		}
+		if g.dados.ContaID != nil && movimentos[*g.dados.ContaID] != nil && !data.After(ate) {
+			movimentos[*g.dados.ContaID].GastosVariaveis += g.dados.Valor
+		}
+	}
+	for _, t := range r.m.transferencias {
+		if t.dados.Data.After(ate) {
+			continue
+		}
+		if c := movimentos[t.dados.ContaDestinoID]; c != nil {
+			c.TransferenciasRecebidas += t.dados.Valor
+		}
+		if c := movimentos[t.dados.ContaOrigemID]; c != nil {
+			c.TransferenciasEnviadas += t.dados.Valor
+		}
+	}
+
+	sort.SliceStable(contas, func(i, j int) bool { return contas[i].Nome < contas[j].Nome })
+	resultado := make([]ContaComMovimentos, len(contas))
+	for i, c := range contas {
+		resultado[i] = *c
+	}
+	return resultado, nil
+}
+
+func (r contasMemoria) InicioRecorrentes(ctx context.Context, usuarioID int) (time.Time, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	inicio := hoje()
+	for _, id := range registrosDoUsuario(r.m.rendas, usuarioID) {
+		if d := r.m.rendas[id].dados; d.ContaID != nil && d.DataEfetiva.Before(inicio) {
+			inicio = d.DataEfetiva
+		}
+	}
+	for _, id := range registrosDoUsuario(r.m.gastosFixos, usuarioID) {
+		if d := r.m.gastosFixos[id].dados; d.ContaID != nil && d.Inicio != nil && d.Inicio.Before(inicio) {
+			inicio = *d.Inicio
+		}
+	}
+	return inicio, nil
+}
+
+type transferenciasMemoria struct{ m *memoria }
+
+func (r transferenciasMemoria) Criar(ctx context.Context, usuarioID int, d DadosTransferencia) (int, error) {
+	return criarRegistro(r.m, r.m.transferencias, usuarioID, d), nil
+}
+
+func (r transferenciasMemoria) Remover(ctx context.Context, usuarioID, id int) error {
+	return removerRegistro(r.m, r.m.transferencias, usuarioID, id)
+}
+
+func (r transferenciasMemoria) Listar(ctx context.Context, usuarioID int, l Listagem) ([]Listado[models.Transferencia], error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	var candidatos []candidatoListagem[models.Transferencia]
+	for _, id := range registrosDoUsuario(r.m.transferencias, usuarioID) {
+		registro := r.m.transferencias[id]
+		d := registro.dados
+		transferencia := models.Transferencia{
+			ID: id, UsuarioID: usuarioID, ContaOrigemID: d.ContaOrigemID, ContaDestinoID: d.ContaDestinoID,
+			Valor: d.Valor, Data: d.Data.Format(time.DateOnly), Descricao: d.Descricao, CreatedAt: registro.criadoEm,
+		}
+		candidatos = append(candidatos, candidatoListagem[models.Transferencia]{
+			id: id, data: d.Data, valor: d.Valor,
+			ordenacao: map[string]string{
+				"data":       transferencia.Data,
+				"valor":      d.Valor.String(),
+				"created_at": registro.criadoEm.UTC().Format(layoutMomento),
+			},
+			item: transferencia,
+		})
+	}
+	return listarMemoria(candidatos, OrdenacoesTransferencias, l)
+}
+
+// cartoesMemoria não tem cartões: nenhum cartão é encontrado
+type cartoesMemoria struct{}
+
+func (cartoesMemoria) Obter(ctx context.Context, usuarioID, id int) (models.Cartao, error) {
+	return models.Cartao{}, ErrNaoEncontrado
+}
+
+// metasMemoria não tem metas, e portanto nenhum aporte
+type metasMemoria struct{}
+
+func (metasMemoria) AportesDescontados(ctx context.Context, usuarioID int, inicio, fim time.Time) (models.Dinheiro, error) {
+	return 0, nil
+}
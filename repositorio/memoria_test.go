@@ -0,0 +1,174 @@
+package repositorio
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+// salarios retorna as rendas de salário do usuário em ordem de ID
+func salarios(repos Repositorios, usuarioID int) []DadosRenda {
+	m := repos.Usuarios.(usuariosMemoria).m
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var rendas []DadosRenda
+	for _, id := range registrosDoUsuario(m.rendas, usuarioID) {
+		if d := m.rendas[id].dados; d.Fonte == models.FonteSalario {
+			rendas = append(rendas, d)
+		}
+	}
+	return rendas
+}
+
+// novoUsuarioComSalario cadastra um usuário sem renda e registra um salário mensal a partir de
+// diasAtras dias antes de hoje
+func novoUsuarioComSalario(t *testing.T, repos Repositorios, valor models.Dinheiro, diasAtras int) int {
+	t.Helper()
+	ctx := context.Background()
+	id, err := repos.Usuarios.Criar(ctx, NovoUsuario{Nome: "Ana", Email: "ana@exemplo.com"})
+	if err != nil {
+		t.Fatalf("erro ao criar o usuário: %v", err)
+	}
+	_, err = repos.Rendas.Criar(ctx, id, DadosRenda{
+		Valor: valor, Fonte: models.FonteSalario, Recorrencia: models.RendaMensal, DataEfetiva: hoje().AddDate(0, 0, -diasAtras),
+	})
+	if err != nil {
+		t.Fatalf("erro ao criar o salário: %v", err)
+	}
+	return id
+}
+
+func alterarSalario(t *testing.T, repos Repositorios, usuarioID int, valor models.Dinheiro) {
+	t.Helper()
+	if err := repos.Usuarios.Atualizar(context.Background(), usuarioID, AlteracoesUsuario{Renda: &valor}); err != nil {
+		t.Fatalf("erro ao alterar o salário: %v", err)
+	}
+}
+
+func TestDefinirSalarioNoMesmoDiaAtualizaOValor(t *testing.T) {
+	repos := NovoMemoria()
+	id := novoUsuarioComSalario(t, repos, 100000, 0)
+
+	alterarSalario(t, repos, id, 200000)
+
+	rendas := salarios(repos, id)
+	if len(rendas) != 1 || rendas[0].Valor != 200000 || rendas[0].DataFim != nil {
+		t.Errorf("salários = %+v, esperado apenas o de hoje com o novo valor", rendas)
+	}
+}
+
+func TestDefinirSalarioEncerraOAnteriorEComecaHoje(t *testing.T) {
+	repos := NovoMemoria()
+	id := novoUsuarioComSalario(t, repos, 100000, 40)
+
+	alterarSalario(t, repos, id, 150000)
+
+	rendas := salarios(repos, id)
+	if len(rendas) != 2 {
+		t.Fatalf("salários = %+v, esperado o anterior encerrado e um novo", rendas)
+	}
+	ontem := hoje().AddDate(0, 0, -1)
+	if rendas[0].Valor != 100000 || rendas[0].DataFim == nil || !rendas[0].DataFim.Equal(ontem) {
+		t.Errorf("salário anterior = %+v, esperado encerrado em %s", rendas[0], ontem.Format("2006-01-02"))
+	}
+	if rendas[1].Valor != 150000 || !rendas[1].DataEfetiva.Equal(hoje()) || rendas[1].DataFim != nil {
+		t.Errorf("salário novo = %+v, esperado a partir de hoje, sem fim", rendas[1])
+	}
+
+	usuario, err := repos.Usuarios.Obter(context.Background(), id)
+	if err != nil || usuario.Renda != 150000 {
+		t.Errorf("renda do usuário = %s (erro %v), esperado 1500.00", usuario.Renda, err)
+	}
+}
+
+func TestDefinirSalarioComOMesmoValorNaoMudaNada(t *testing.T) {
+	for _, diasAtras := range []int{0, 40} {
+		repos := NovoMemoria()
+		id := novoUsuarioComSalario(t, repos, 100000, diasAtras)
+		antes := salarios(repos, id)
+
+		alterarSalario(t, repos, id, 100000)
+		alterarSalario(t, repos, id, 100000)
+
+		depois := salarios(repos, id)
+		if len(depois) != 1 || depois[0].DataFim != nil || !depois[0].DataEfetiva.Equal(antes[0].DataEfetiva) {
+			t.Errorf("salário de %d dias atrás: %+v, esperado sem alteração (%+v)", diasAtras, depois, antes)
+		}
+	}
+}
+
+func TestDefinirSalarioZeroEncerra(t *testing.T) {
+	t.Run("salário anterior é encerrado ontem", func(t *testing.T) {
+		repos := NovoMemoria()
+		id := novoUsuarioComSalario(t, repos, 100000, 40)
+
+		alterarSalario(t, repos, id, 0)
+
+		rendas := salarios(repos, id)
+		if len(rendas) != 1 || rendas[0].DataFim == nil || !rendas[0].DataFim.Equal(hoje().AddDate(0, 0, -1)) {
+			t.Errorf("salários = %+v, esperado o anterior encerrado ontem", rendas)
+		}
+	})
+	t.Run("salário de hoje é removido", func(t *testing.T) {
+		repos := NovoMemoria()
+		id := novoUsuarioComSalario(t, repos, 100000, 0)
+
+		alterarSalario(t, repos, id, 0)
+
+		if rendas := salarios(repos, id); len(rendas) != 0 {
+			t.Errorf("salários = %+v, esperado nenhum", rendas)
+		}
+		usuario, err := repos.Usuarios.Obter(context.Background(), id)
+		if err != nil || usuario.Renda != 0 {
+			t.Errorf("renda do usuário = %s (erro %v), esperado zero", usuario.Renda, err)
+		}
+	})
+}
+
+func TestListarGastosVariaveisNoMesUsaOVencimentoDaFatura(t *testing.T) {
+	repos := NovoMemoria()
+	ctx := context.Background()
+	id, err := repos.Usuarios.Criar(ctx, NovoUsuario{Nome: "Ana", Email: "ana@exemplo.com"})
+	if err != nil {
+		t.Fatalf("erro ao criar o usuário: %v", err)
+	}
+
+	data := func(texto string) time.Time {
+		d, _ := time.Parse(time.DateOnly, texto)
+		return d
+	}
+	vencimento := data("2025-04-10")
+	gastos := []DadosGastoVariavel{
+		{Nome: "Mercado", Valor: 1000, Data: data("2025-03-20")},
+		{Nome: "Cartão", Valor: 2000, Data: data("2025-03-25"), VencimentoFatura: &vencimento},
+	}
+	for _, g := range gastos {
+		if _, err := repos.GastosVariaveis.Criar(ctx, id, g); err != nil {
+			t.Fatalf("erro ao criar o gasto: %v", err)
+		}
+	}
+
+	casos := []struct {
+		mes      string
+		esperado []string
+	}{
+		{"2025-03-01", []string{"Mercado"}},
+		{"2025-04-01", []string{"Cartão"}},
+	}
+	for _, caso := range casos {
+		listados, err := repos.GastosVariaveis.Listar(ctx, id, Listagem{Ordenar: "data", Limite: 10, Mes: data(caso.mes)})
+		if err != nil {
+			t.Fatalf("erro ao listar: %v", err)
+		}
+		var nomes []string
+		for _, l := range listados {
+			nomes = append(nomes, l.Item.Nome)
+		}
+		if !slices.Equal(nomes, caso.esperado) {
+			t.Errorf("gastos de %s = %v, esperado %v", caso.mes, nomes, caso.esperado)
+		}
+	}
+}
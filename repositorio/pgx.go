@@ -0,0 +1,645 @@
+package repositorio
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jpeccia/quantogasto_app_server/database"
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+// condicaoRendaMensalAtiva seleciona as rendas mensais que estão valendo hoje;
+// a soma delas é a renda mensal do usuário
+const condicaoRendaMensalAtiva = "recorrencia = 'mensal' AND data_efetiva <= CURRENT_DATE AND (data_fim IS NULL OR data_fim >= CURRENT_DATE)"
+
+// banco é satisfeito pelo pgxpool.Pool
+type banco interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// NovoPgx cria os repositórios sobre o pool de conexões do Postgres
+func NovoPgx(db banco) Repositorios {
+	return Repositorios{
+		Usuarios:        usuariosPgx{db},
+		Rendas:          rendasPgx{db},
+		GastosFixos:     gastosFixosPgx{db},
+		GastosVariaveis: gastosVariaveisPgx{db},
+		Categorias:      categoriasPgx{db},
+		Contas:          contasPgx{db},
+		Transferencias:  transferenciasPgx{db},
+		Cartoes:         cartoesPgx{db},
+		Metas:           metasPgx{db},
+	}
+}
+
+// verificarAfetadas converte a ausência de linhas afetadas em ErrNaoEncontrado
+func verificarAfetadas(tag pgconn.CommandTag, err error) error {
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNaoEncontrado
+	}
+	return nil
+}
+
+type usuariosPgx struct{ db banco }
+
+func (r usuariosPgx) Criar(ctx context.Context, u NovoUsuario) (int, error) {
+	var id int
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		query := `
+            INSERT INTO usuarios (nome, email, senha_hash, foto_perfil, cargo, idioma)
+            VALUES ($1, $2, $3, $4, $5, $6)
+            RETURNING id
+        `
+		err := tx.QueryRow(ctx, query, u.Nome, u.Email, u.SenhaHash, u.FotoPerfil, u.Cargo, u.Idioma).Scan(&id)
+		if err != nil || u.Renda <= 0 {
+			return err
+		}
+
+		query = `
+            INSERT INTO rendas (usuario_id, valor, fonte, recorrencia, data_efetiva)
+            VALUES ($1, $2, 'salario', 'mensal', CURRENT_DATE)
+        `
+		_, err = tx.Exec(ctx, query, id, u.Renda)
+		return err
+	})
+	if database.ErroViolacaoUnica(err) {
+		return 0, ErrDuplicado
+	}
+	return id, err
+}
+
+func (r usuariosPgx) Obter(ctx context.Context, id int) (models.Usuario, error) {
+	var usuario models.Usuario
+	query := `
+        SELECT id, nome, COALESCE(email, ''), foto_perfil, cargo,
+               COALESCE((SELECT SUM(valor) FROM rendas WHERE usuario_id = usuarios.id AND ` + condicaoRendaMensalAtiva + `), 0),
+               idioma, created_at
+        FROM usuarios WHERE id = $1
+    `
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&usuario.ID, &usuario.Nome, &usuario.Email, &usuario.FotoPerfil, &usuario.Cargo, &usuario.Renda,
+		&usuario.Idioma, &usuario.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return usuario, ErrNaoEncontrado
+	}
+	return usuario, err
+}
+
+func (r usuariosPgx) Atualizar(ctx context.Context, id int, a AlteracoesUsuario) error {
+	// Monta o SET apenas com os campos informados
+	var campos []string
+	var params []any
+	adicionar := func(coluna string, valor any) {
+		params = append(params, valor)
+		campos = append(campos, coluna+" = $"+strconv.Itoa(len(params)))
+	}
+	if a.Nome != nil {
+		adicionar("nome", *a.Nome)
+	}
+	if a.Cargo != nil {
+		adicionar("cargo", *a.Cargo)
+	}
+	if a.FotoPerfil != nil {
+		adicionar("foto_perfil", *a.FotoPerfil)
+	}
+	params = append(params, id)
+
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if len(campos) == 0 {
+			// Nenhum campo do cadastro: só confirma que o usuário existe
+			var existe bool
+			err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM usuarios WHERE id = $1)`, id).Scan(&existe)
+			if err == nil && !existe {
+				return ErrNaoEncontrado
+			}
+			if err != nil {
+				return err
+			}
+		} else {
+			query := `UPDATE usuarios SET ` + strings.Join(campos, ", ") + ` WHERE id = $` + strconv.Itoa(len(params))
+			if err := verificarAfetadas(tx.Exec(ctx, query, params...)); err != nil {
+				return err
+			}
+		}
+
+		if a.Renda == nil {
+			return nil
+		}
+		return definirSalario(ctx, tx, id, *a.Renda)
+	})
+}
+
+// definirSalario troca o valor do salário mensal mais recente que está valendo a partir de hoje:
+// o que começou hoje muda de valor; o que começou antes termina ontem e dá lugar a um novo, com
+// o mesmo dia de fim, conta e descrição, para que os meses passados mantenham o valor antigo.
+// Sem salário valendo, cria um a partir de hoje. Com valor zero, encerra os que estão valendo.
+func definirSalario(ctx context.Context, tx pgx.Tx, usuarioID int, valor models.Dinheiro) error {
+	salarioAtivo := `usuario_id = $1 AND fonte = 'salario' AND ` + condicaoRendaMensalAtiva
+
+	if valor == 0 {
+		// Os que começam hoje são removidos; os demais terminam ontem
+		_, err := tx.Exec(ctx, `DELETE FROM rendas WHERE `+salarioAtivo+` AND data_efetiva = CURRENT_DATE`, usuarioID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `UPDATE rendas SET data_fim = CURRENT_DATE - 1 WHERE `+salarioAtivo, usuarioID)
+		return err
+	}
+
+	var id int
+	var valorAtual models.Dinheiro
+	var comecaHoje bool
+	query := `SELECT id, valor, data_efetiva = CURRENT_DATE FROM rendas WHERE ` + salarioAtivo +
+		` ORDER BY data_efetiva DESC, id DESC LIMIT 1 FOR UPDATE`
+	err := tx.QueryRow(ctx, query, usuarioID).Scan(&id, &valorAtual, &comecaHoje)
+	if err == pgx.ErrNoRows {
+		query = `
+            INSERT INTO rendas (usuario_id, valor, fonte, recorrencia, data_efetiva)
+            VALUES ($1, $2, 'salario', 'mensal', CURRENT_DATE)
+        `
+		_, err = tx.Exec(ctx, query, usuarioID, valor)
+		return err
+	}
+	if err != nil || valorAtual == valor {
+		return err
+	}
+
+	if comecaHoje {
+		_, err = tx.Exec(ctx, `UPDATE rendas SET valor = $2 WHERE id = $1`, id, valor)
+		return err
+	}
+
+	query = `
+        INSERT INTO rendas (usuario_id, valor, fonte, descricao, recorrencia, data_efetiva, data_fim, conta_id)
+        SELECT usuario_id, $2, fonte, descricao, recorrencia, CURRENT_DATE, data_fim, conta_id
+        FROM rendas WHERE id = $1
+    `
+	if _, err := tx.Exec(ctx, query, id, valor); err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `UPDATE rendas SET data_fim = CURRENT_DATE - 1 WHERE id = $1`, id)
+	return err
+}
+
+func (r usuariosPgx) AtualizarFotoPerfil(ctx context.Context, id int, caminho string) error {
+	query := `UPDATE usuarios SET foto_perfil = $1 WHERE id = $2`
+	return verificarAfetadas(r.db.Exec(ctx, query, caminho, id))
+}
+
+func (r usuariosPgx) ObterIdioma(ctx context.Context, id int) (*string, error) {
+	var idioma *string
+	err := r.db.QueryRow(ctx, `SELECT idioma FROM usuarios WHERE id = $1`, id).Scan(&idioma)
+	if err == pgx.ErrNoRows {
+		return nil, ErrNaoEncontrado
+	}
+	return idioma, err
+}
+
+func (r usuariosPgx) AtualizarIdioma(ctx context.Context, id int, idioma *string) error {
+	query := `UPDATE usuarios SET idioma = $1 WHERE id = $2`
+	return verificarAfetadas(r.db.Exec(ctx, query, idioma, id))
+}
+
+type rendasPgx struct{ db banco }
+
+func (r rendasPgx) Criar(ctx context.Context, usuarioID int, d DadosRenda) (int, error) {
+	query := `
+        INSERT INTO rendas (usuario_id, valor, fonte, descricao, recorrencia, data_efetiva, data_fim, conta_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING id
+    `
+	var id int
+	err := r.db.QueryRow(ctx, query, usuarioID, d.Valor, d.Fonte, d.Descricao,
+		d.Recorrencia, d.DataEfetiva, d.DataFim, d.ContaID).Scan(&id)
+	return id, err
+}
+
+func (r rendasPgx) Atualizar(ctx context.Context, usuarioID, id int, d DadosRenda) error {
+	query := `
+        UPDATE rendas
+        SET valor = $1, fonte = $2, descricao = $3, recorrencia = $4, data_efetiva = $5, data_fim = $6, conta_id = $7
+        WHERE id = $8 AND usuario_id = $9
+    `
+	return verificarAfetadas(r.db.Exec(ctx, query, d.Valor, d.Fonte, d.Descricao,
+		d.Recorrencia, d.DataEfetiva, d.DataFim, d.ContaID, id, usuarioID))
+}
+
+func (r rendasPgx) Remover(ctx context.Context, usuarioID, id int) error {
+	query := `DELETE FROM rendas WHERE id = $1 AND usuario_id = $2`
+	return verificarAfetadas(r.db.Exec(ctx, query, id, usuarioID))
+}
+
+// consultaRendas descreve a listagem paginada de rendas
+var consultaRendas = consultaListagem{
+	tabela: "rendas",
+	colunas: "id, usuario_id, valor, fonte, descricao, recorrencia, to_char(data_efetiva, 'YYYY-MM-DD'), " +
+		"to_char(data_fim, 'YYYY-MM-DD'), conta_id, id_externo, created_at",
+	colunaData: "data_efetiva",
+	ordenacoes: OrdenacoesRendas,
+}
+
+func (r rendasPgx) Listar(ctx context.Context, usuarioID int, l Listagem) ([]Listado[models.Renda], error) {
+	return listarPgx(ctx, r.db, consultaRendas, usuarioID, l, func(rows pgx.Rows, renda *models.Renda, valorOrdenacao *string) (int, error) {
+		err := rows.Scan(&renda.ID, &renda.UsuarioID, &renda.Valor, &renda.Fonte, &renda.Descricao, &renda.Recorrencia,
+			&renda.DataEfetiva, &renda.DataFim, &renda.ContaID, &renda.IDExterno, &renda.CreatedAt, valorOrdenacao)
+		return renda.ID, err
+	})
+}
+
+func (r rendasPgx) NoPeriodo(ctx context.Context, usuarioID int, inicio, fim time.Time) ([]RendaRegistrada, error) {
+	query := `
+        SELECT id, valor, fonte, descricao, recorrencia, data_efetiva, data_fim, conta_id
+        FROM rendas
+        WHERE usuario_id = $1 AND data_efetiva < $2
+          AND ((recorrencia = 'unica' AND data_efetiva >= $3) OR
+               (recorrencia = 'mensal' AND (data_fim IS NULL OR data_fim >= $3)))
+    `
+	rows, err := r.db.Query(ctx, query, usuarioID, fim, inicio)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rendas []RendaRegistrada
+	for rows.Next() {
+		var renda RendaRegistrada
+		if err := rows.Scan(&renda.ID, &renda.Valor, &renda.Fonte, &renda.Descricao, &renda.Recorrencia,
+			&renda.DataEfetiva, &renda.DataFim, &renda.ContaID); err != nil {
+			return nil, err
+		}
+		rendas = append(rendas, renda)
+	}
+	return rendas, rows.Err()
+}
+
+type gastosFixosPgx struct{ db banco }
+
+func (r gastosFixosPgx) Criar(ctx context.Context, usuarioID int, d DadosGastoFixo) (int, error) {
+	query := `
+        INSERT INTO gastos_fixos (usuario_id, nome, valor, categoria_id, conta_id, inicio, fim, frequencia, dia)
+        VALUES ($1, $2, $3, $4, $5, COALESCE($6, date_trunc('month', CURRENT_DATE)::date), $7, $8, $9)
+        RETURNING id
+    `
+	var id int
+	err := r.db.QueryRow(ctx, query, usuarioID, d.Nome, d.Valor, d.CategoriaID, d.ContaID,
+		d.Inicio, d.Fim, d.Frequencia, d.Dia).Scan(&id)
+	if database.ErroViolacaoCheck(err) {
+		return 0, ErrIntervaloInvalido
+	}
+	return id, err
+}
+
+func (r gastosFixosPgx) Atualizar(ctx context.Context, usuarioID, id int, d DadosGastoFixo) error {
+	query := `
+        UPDATE gastos_fixos
+        SET nome = $1, valor = $2, categoria_id = $3, conta_id = $4,
+            inicio = COALESCE($5, inicio), fim = $6, frequencia = $7, dia = $8
+        WHERE id = $9 AND usuario_id = $10
+    `
+	err := verificarAfetadas(r.db.Exec(ctx, query, d.Nome, d.Valor, d.CategoriaID, d.ContaID,
+		d.Inicio, d.Fim, d.Frequencia, d.Dia, id, usuarioID))
+	if database.ErroViolacaoCheck(err) {
+		return ErrIntervaloInvalido
+	}
+	return err
+}
+
+func (r gastosFixosPgx) Remover(ctx context.Context, usuarioID, id int) error {
+	query := `DELETE FROM gastos_fixos WHERE id = $1 AND usuario_id = $2`
+	return verificarAfetadas(r.db.Exec(ctx, query, id, usuarioID))
+}
+
+// condicaoGastoFixoNoMes seleciona os gastos fixos cobrados no mês, com a regra de
+// recorrencia.ocorreNoMes dos handlers. É um formato de fmt.Sprintf: $%[1]d é o primeiro dia do mês.
+const condicaoGastoFixoNoMes = `(inicio <= $%[1]d::date AND (fim IS NULL OR fim >= $%[1]d::date) AND
+    ((EXTRACT(YEAR FROM $%[1]d::date) - EXTRACT(YEAR FROM inicio)) * 12
+      + EXTRACT(MONTH FROM $%[1]d::date) - EXTRACT(MONTH FROM inicio))::int
+    %% CASE frequencia WHEN 'bimestral' THEN 2 WHEN 'trimestral' THEN 3 WHEN 'anual' THEN 12 ELSE 1 END = 0)`
+
+// consultaGastosFixos descreve a listagem paginada de gastos fixos
+var consultaGastosFixos = consultaListagem{
+	tabela:      "gastos_fixos",
+	colunas:     "id, usuario_id, nome, valor, categoria_id, conta_id, to_char(inicio, 'YYYY-MM'), to_char(fim, 'YYYY-MM'), frequencia, dia, created_at",
+	colunaData:  "created_at",
+	condicaoMes: condicaoGastoFixoNoMes,
+	ordenacoes:  OrdenacoesGastosFixos,
+}
+
+func (r gastosFixosPgx) Listar(ctx context.Context, usuarioID int, l Listagem) ([]Listado[models.GastoFixo], error) {
+	return listarPgx(ctx, r.db, consultaGastosFixos, usuarioID, l, func(rows pgx.Rows, g *models.GastoFixo, valorOrdenacao *string) (int, error) {
+		err := rows.Scan(&g.ID, &g.UsuarioID, &g.Nome, &g.Valor, &g.CategoriaID, &g.ContaID,
+			&g.Inicio, &g.Fim, &g.Frequencia, &g.Dia, &g.CreatedAt, valorOrdenacao)
+		return g.ID, err
+	})
+}
+
+func (r gastosFixosPgx) NoPeriodo(ctx context.Context, usuarioID int, inicio, fim time.Time) ([]GastoFixoRegistrado, error) {
+	query := `
+        SELECT id, nome, valor, categoria_id, conta_id, inicio, fim, frequencia, dia
+        FROM gastos_fixos
+        WHERE usuario_id = $1 AND inicio < $2 AND (fim IS NULL OR fim >= date_trunc('month', $3::date))
+    `
+	rows, err := r.db.Query(ctx, query, usuarioID, fim, inicio)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var gastos []GastoFixoRegistrado
+	for rows.Next() {
+		var g GastoFixoRegistrado
+		if err := rows.Scan(&g.ID, &g.Nome, &g.Valor, &g.CategoriaID, &g.ContaID, &g.Inicio, &g.Fim, &g.Frequencia, &g.Dia); err != nil {
+			return nil, err
+		}
+		gastos = append(gastos, g)
+	}
+	return gastos, rows.Err()
+}
+
+type gastosVariaveisPgx struct{ db banco }
+
+func (r gastosVariaveisPgx) Criar(ctx context.Context, usuarioID int, d DadosGastoVariavel) (int, error) {
+	query := `
+        INSERT INTO gastos_variaveis (usuario_id, nome, valor, data, categoria_id, cartao_id, vencimento_fatura, conta_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING id
+    `
+	var id int
+	err := r.db.QueryRow(ctx, query, usuarioID, d.Nome, d.Valor, d.Data, d.CategoriaID,
+		d.CartaoID, d.VencimentoFatura, d.ContaID).Scan(&id)
+	return id, err
+}
+
+func (r gastosVariaveisPgx) Atualizar(ctx context.Context, usuarioID, id int, d DadosGastoVariavel) error {
+	query := `
+        UPDATE gastos_variaveis
+        SET nome = $1, valor = $2, data = $3, categoria_id = $4, cartao_id = $5, vencimento_fatura = $6, conta_id = $7
+        WHERE id = $8 AND usuario_id = $9 AND compra_parcelada_id IS NULL
+    `
+	err := verificarAfetadas(r.db.Exec(ctx, query, d.Nome, d.Valor, d.Data, d.CategoriaID,
+		d.CartaoID, d.VencimentoFatura, d.ContaID, id, usuarioID))
+	return r.verificarParcela(ctx, usuarioID, id, err)
+}
+
+func (r gastosVariaveisPgx) Remover(ctx context.Context, usuarioID, id int) error {
+	query := `DELETE FROM gastos_variaveis WHERE id = $1 AND usuario_id = $2 AND compra_parcelada_id IS NULL`
+	err := verificarAfetadas(r.db.Exec(ctx, query, id, usuarioID))
+	return r.verificarParcela(ctx, usuarioID, id, err)
+}
+
+// verificarParcela troca ErrNaoEncontrado por *ErroParcelaDeCompra quando o gasto existe, mas é
+// parcela de uma compra parcelada (que o WHERE de Atualizar e Remover deixa de fora)
+func (r gastosVariaveisPgx) verificarParcela(ctx context.Context, usuarioID, id int, err error) error {
+	if err != ErrNaoEncontrado {
+		return err
+	}
+	var compraParceladaID *int
+	query := `SELECT compra_parcelada_id FROM gastos_variaveis WHERE id = $1 AND usuario_id = $2`
+	err = r.db.QueryRow(ctx, query, id, usuarioID).Scan(&compraParceladaID)
+	if err == pgx.ErrNoRows {
+		return ErrNaoEncontrado
+	}
+	if err != nil {
+		return err
+	}
+	if compraParceladaID == nil {
+		return ErrNaoEncontrado
+	}
+	return &ErroParcelaDeCompra{CompraParceladaID: *compraParceladaID}
+}
+
+// condicaoGastoVariavelNoMes seleciona os gastos variáveis que pesam no mês, com a regra de
+// TotaisPorMes: compras no cartão contam no mês do vencimento da fatura. $%[1]d é o primeiro dia do mês.
+const condicaoGastoVariavelNoMes = `date_trunc('month', COALESCE(vencimento_fatura, data)) = $%[1]d::date`
+
+// consultaGastosVariaveis descreve a listagem paginada de gastos variáveis
+var consultaGastosVariaveis = consultaListagem{
+	tabela:      "gastos_variaveis",
+	colunas:     "id, usuario_id, nome, valor, to_char(data, 'YYYY-MM-DD'), categoria_id, compra_parcelada_id, parcela_numero, cartao_id, to_char(vencimento_fatura, 'YYYY-MM-DD'), conta_id, id_externo, created_at",
+	colunaData:  "data",
+	condicaoMes: condicaoGastoVariavelNoMes,
+	ordenacoes:  OrdenacoesGastosVariaveis,
+}
+
+func (r gastosVariaveisPgx) Listar(ctx context.Context, usuarioID int, l Listagem) ([]Listado[models.GastoVariavel], error) {
+	return listarPgx(ctx, r.db, consultaGastosVariaveis, usuarioID, l, func(rows pgx.Rows, g *models.GastoVariavel, valorOrdenacao *string) (int, error) {
+		err := rows.Scan(&g.ID, &g.UsuarioID, &g.Nome, &g.Valor, &g.Data, &g.CategoriaID,
+			&g.CompraParceladaID, &g.ParcelaNumero, &g.CartaoID, &g.VencimentoFatura, &g.ContaID, &g.IDExterno, &g.CreatedAt, valorOrdenacao)
+		return g.ID, err
+	})
+}
+
+func (r gastosVariaveisPgx) TotaisPorMes(ctx context.Context, usuarioID int, inicio, fim time.Time) ([]TotalGastosVariaveis, error) {
+	query := `
+        SELECT COALESCE(categoria_id, 0), date_trunc('month', COALESCE(vencimento_fatura, data))::date, SUM(valor)
+        FROM gastos_variaveis
+        WHERE usuario_id = $1 AND COALESCE(vencimento_fatura, data) >= $2 AND COALESCE(vencimento_fatura, data) < $3
+        GROUP BY 1, 2
+    `
+	rows, err := r.db.Query(ctx, query, usuarioID, inicio, fim)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totais []TotalGastosVariaveis
+	for rows.Next() {
+		var t TotalGastosVariaveis
+		if err := rows.Scan(&t.CategoriaID, &t.Mes, &t.Total); err != nil {
+			return nil, err
+		}
+		totais = append(totais, t)
+	}
+	return totais, rows.Err()
+}
+
+type categoriasPgx struct{ db banco }
+
+func (r categoriasPgx) Disponivel(ctx context.Context, usuarioID, id int) (bool, error) {
+	var existe bool
+	query := `SELECT EXISTS (SELECT 1 FROM categorias WHERE id = $1 AND (usuario_id IS NULL OR usuario_id = $2))`
+	err := r.db.QueryRow(ctx, query, id, usuarioID).Scan(&existe)
+	return existe, err
+}
+
+func (r categoriasPgx) Buscar(ctx context.Context, ids []int) ([]models.Categoria, error) {
+	query := `SELECT id, usuario_id, nome, cor, icone, usuario_id IS NULL, created_at FROM categorias WHERE id = ANY($1)`
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categorias []models.Categoria
+	for rows.Next() {
+		var c models.Categoria
+		if err := rows.Scan(&c.ID, &c.UsuarioID, &c.Nome, &c.Cor, &c.Icone, &c.Padrao, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		categorias = append(categorias, c)
+	}
+	return categorias, rows.Err()
+}
+
+type contasPgx struct{ db banco }
+
+func (r contasPgx) Criar(ctx context.Context, usuarioID int, d DadosConta) (int, error) {
+	query := `
+        INSERT INTO contas (usuario_id, nome, tipo, saldo_inicial)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id
+    `
+	var id int
+	err := r.db.QueryRow(ctx, query, usuarioID, d.Nome, d.Tipo, d.SaldoInicial).Scan(&id)
+	if database.ErroViolacaoUnica(err) {
+		return 0, ErrDuplicado
+	}
+	return id, err
+}
+
+func (r contasPgx) Atualizar(ctx context.Context, usuarioID, id int, d DadosConta) error {
+	query := `
+        UPDATE contas
+        SET nome = $1, tipo = $2, saldo_inicial = $3
+        WHERE id = $4 AND usuario_id = $5
+    `
+	tag, err := r.db.Exec(ctx, query, d.Nome, d.Tipo, d.SaldoInicial, id, usuarioID)
+	if database.ErroViolacaoUnica(err) {
+		return ErrDuplicado
+	}
+	return verificarAfetadas(tag, err)
+}
+
+func (r contasPgx) Remover(ctx context.Context, usuarioID, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM contas WHERE id = $1 AND usuario_id = $2`, id, usuarioID)
+	if database.ErroViolacaoChaveEstrangeira(err) {
+		return ErrEmUso
+	}
+	return verificarAfetadas(tag, err)
+}
+
+func (r contasPgx) Pertence(ctx context.Context, usuarioID, id int) (bool, error) {
+	var existe bool
+	query := `SELECT EXISTS (SELECT 1 FROM contas WHERE id = $1 AND usuario_id = $2)`
+	err := r.db.QueryRow(ctx, query, id, usuarioID).Scan(&existe)
+	return existe, err
+}
+
+func (r contasPgx) ComMovimentos(ctx context.Context, usuarioID int) ([]ContaComMovimentos, error) {
+	query := `
+        SELECT c.id, c.usuario_id, c.nome, c.tipo, c.saldo_inicial, c.created_at,
+               COALESCE((
+                   SELECT SUM(valor) FROM gastos_variaveis
+                   WHERE conta_id = c.id AND COALESCE(vencimento_fatura, data) <= CURRENT_DATE
+               ), 0),
+               COALESCE((SELECT SUM(valor) FROM transferencias WHERE conta_destino_id = c.id AND data <= CURRENT_DATE), 0),
+               COALESCE((SELECT SUM(valor) FROM transferencias WHERE conta_origem_id = c.id AND data <= CURRENT_DATE), 0)
+        FROM contas c
+        WHERE c.usuario_id = $1
+        ORDER BY c.nome, c.id
+    `
+	rows, err := r.db.Query(ctx, query, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contas []ContaComMovimentos
+	for rows.Next() {
+		var c ContaComMovimentos
+		err := rows.Scan(&c.ID, &c.UsuarioID, &c.Nome, &c.Tipo, &c.SaldoInicial, &c.CreatedAt,
+			&c.GastosVariaveis, &c.TransferenciasRecebidas, &c.TransferenciasEnviadas)
+		if err != nil {
+			return nil, err
+		}
+		contas = append(contas, c)
+	}
+	return contas, rows.Err()
+}
+
+func (r contasPgx) InicioRecorrentes(ctx context.Context, usuarioID int) (time.Time, error) {
+	var inicio time.Time
+	query := `
+        SELECT LEAST(
+            COALESCE((SELECT MIN(inicio) FROM gastos_fixos WHERE usuario_id = $1 AND conta_id IS NOT NULL), CURRENT_DATE),
+            COALESCE((SELECT MIN(data_efetiva) FROM rendas WHERE usuario_id = $1 AND conta_id IS NOT NULL), CURRENT_DATE)
+        )
+    `
+	err := r.db.QueryRow(ctx, query, usuarioID).Scan(&inicio)
+	return inicio, err
+}
+
+type transferenciasPgx struct{ db banco }
+
+func (r transferenciasPgx) Criar(ctx context.Context, usuarioID int, d DadosTransferencia) (int, error) {
+	query := `
+        INSERT INTO transferencias (usuario_id, conta_origem_id, conta_destino_id, valor, data, descricao)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id
+    `
+	var id int
+	err := r.db.QueryRow(ctx, query, usuarioID, d.ContaOrigemID, d.ContaDestinoID, d.Valor, d.Data, d.Descricao).Scan(&id)
+	return id, err
+}
+
+func (r transferenciasPgx) Remover(ctx context.Context, usuarioID, id int) error {
+	return verificarAfetadas(r.db.Exec(ctx, `DELETE FROM transferencias WHERE id = $1 AND usuario_id = $2`, id, usuarioID))
+}
+
+// consultaTransferencias descreve a listagem paginada de transferências
+var consultaTransferencias = consultaListagem{
+	tabela:     "transferencias",
+	colunas:    "id, usuario_id, conta_origem_id, conta_destino_id, valor, to_char(data, 'YYYY-MM-DD'), descricao, created_at",
+	colunaData: "data",
+	ordenacoes: OrdenacoesTransferencias,
+}
+
+func (r transferenciasPgx) Listar(ctx context.Context, usuarioID int, l Listagem) ([]Listado[models.Transferencia], error) {
+	return listarPgx(ctx, r.db, consultaTransferencias, usuarioID, l, func(rows pgx.Rows, t *models.Transferencia, valorOrdenacao *string) (int, error) {
+		err := rows.Scan(&t.ID, &t.UsuarioID, &t.ContaOrigemID, &t.ContaDestinoID, &t.Valor, &t.Data,
+			&t.Descricao, &t.CreatedAt, valorOrdenacao)
+		return t.ID, err
+	})
+}
+
+type cartoesPgx struct{ db banco }
+
+func (r cartoesPgx) Obter(ctx context.Context, usuarioID, id int) (models.Cartao, error) {
+	var c models.Cartao
+	query := `
+        SELECT id, usuario_id, nome, limite, dia_fechamento, dia_vencimento, created_at
+        FROM cartoes
+        WHERE id = $1 AND usuario_id = $2
+    `
+	err := r.db.QueryRow(ctx, query, id, usuarioID).Scan(
+		&c.ID, &c.UsuarioID, &c.Nome, &c.Limite, &c.DiaFechamento, &c.DiaVencimento, &c.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return c, ErrNaoEncontrado
+	}
+	return c, err
+}
+
+type metasPgx struct{ db banco }
+
+func (r metasPgx) AportesDescontados(ctx context.Context, usuarioID int, inicio, fim time.Time) (models.Dinheiro, error) {
+	var total models.Dinheiro
+	query := `
+        SELECT COALESCE(SUM(a.valor), 0)
+        FROM aportes_meta a
+        JOIN metas m ON m.id = a.meta_id
+        WHERE m.usuario_id = $1 AND m.descontar_do_saldo AND a.data >= $2 AND a.data < $3
+    `
+	err := r.db.QueryRow(ctx, query, usuarioID, inicio, fim).Scan(&total)
+	return total, err
+}
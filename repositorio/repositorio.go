@@ -0,0 +1,248 @@
+// Package repositorio isola o acesso a usuários, rendas e gastos atrás de interfaces,
+// com uma implementação sobre o pgx (produção) e outra em memória (testes e desenvolvimento).
+package repositorio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jpeccia/quantogasto_app_server/models"
+)
+
+var (
+	// ErrNaoEncontrado indica que o registro não existe ou não pertence ao usuário
+	ErrNaoEncontrado = errors.New("registro não encontrado")
+
+	// ErrDuplicado indica violação de unicidade (ex.: e-mail já cadastrado)
+	ErrDuplicado = errors.New("registro duplicado")
+
+	// ErrIntervaloInvalido indica um gasto fixo com fim anterior ao início
+	ErrIntervaloInvalido = errors.New("fim anterior ao início")
+
+	// ErrEmUso indica um registro que não pode ser removido por ainda ser referenciado
+	// (ex.: conta com transferências)
+	ErrEmUso = errors.New("registro em uso")
+)
+
+// ErroParcelaDeCompra indica um gasto variável que é parcela de uma compra parcelada;
+// ele só muda pela compra
+type ErroParcelaDeCompra struct {
+	CompraParceladaID int
+}
+
+func (e *ErroParcelaDeCompra) Error() string {
+	return fmt.Sprintf("gasto é parcela da compra parcelada %d", e.CompraParceladaID)
+}
+
+// NovoUsuario são os dados do cadastro; Renda, se positiva, vira uma renda mensal de salário
+type NovoUsuario struct {
+	Nome       string
+	Email      string // já normalizado
+	SenhaHash  string
+	FotoPerfil string
+	Cargo      string
+	Renda      models.Dinheiro
+	Idioma     *string // nil para seguir o Accept-Language
+}
+
+// AlteracoesUsuario são os campos do perfil a alterar; campos nil ficam como estão.
+// Renda troca o salário mensal que está valendo por um novo a partir de hoje (ou cria um); zero o encerra.
+type AlteracoesUsuario struct {
+	Nome       *string
+	Cargo      *string
+	FotoPerfil *string
+	Renda      *models.Dinheiro
+}
+
+// Usuarios acessa os cadastros de usuários
+type Usuarios interface {
+	// Criar cadastra o usuário (e a renda inicial) e retorna o ID; ErrDuplicado se o e-mail já existir
+	Criar(ctx context.Context, u NovoUsuario) (int, error)
+	// Obter retorna o usuário com a renda mensal calculada
+	Obter(ctx context.Context, id int) (models.Usuario, error)
+	// Atualizar altera os campos informados de uma vez; ErrNaoEncontrado se o usuário não existir
+	Atualizar(ctx context.Context, id int, a AlteracoesUsuario) error
+	AtualizarFotoPerfil(ctx context.Context, id int, caminho string) error
+	// ObterIdioma retorna o idioma preferido do usuário, ou nil se ele não escolheu nenhum
+	ObterIdioma(ctx context.Context, id int) (*string, error)
+	// AtualizarIdioma grava o idioma preferido; nil volta a seguir o Accept-Language
+	AtualizarIdioma(ctx context.Context, id int, idioma *string) error
+}
+
+// DadosRenda são os campos gravados na criação e edição de uma renda
+type DadosRenda struct {
+	Valor       models.Dinheiro
+	Fonte       string
+	Descricao   string
+	Recorrencia string
+	DataEfetiva time.Time
+	DataFim     *time.Time
+	ContaID     *int
+}
+
+// RendaRegistrada é uma renda gravada, com o ID
+type RendaRegistrada struct {
+	ID int
+	DadosRenda
+}
+
+// Rendas acessa as rendas do usuário
+type Rendas interface {
+	Criar(ctx context.Context, usuarioID int, d DadosRenda) (int, error)
+	Atualizar(ctx context.Context, usuarioID, id int, d DadosRenda) error
+	Remover(ctx context.Context, usuarioID, id int) error
+	// Listar retorna uma página das rendas, ordenada por OrdenacoesRendas; De e Ate filtram pela
+	// data efetiva e categoria e mês não se aplicam
+	Listar(ctx context.Context, usuarioID int, l Listagem) ([]Listado[models.Renda], error)
+	// NoPeriodo retorna as rendas que podem ter recebimentos em [inicio, fim): as únicas com data
+	// efetiva no período e as mensais que começam antes do fim e terminam depois do início
+	NoPeriodo(ctx context.Context, usuarioID int, inicio, fim time.Time) ([]RendaRegistrada, error)
+}
+
+// DadosGastoFixo são os campos gravados na criação e edição de um gasto fixo.
+// Inicio nil usa o mês atual na criação e mantém o atual na edição.
+type DadosGastoFixo struct {
+	Nome        string
+	Valor       models.Dinheiro
+	CategoriaID *int
+	ContaID     *int
+	Inicio      *time.Time
+	Fim         *time.Time
+	Frequencia  string
+	Dia         *int
+}
+
+// GastoFixoRegistrado é um gasto fixo gravado, com o ID; Inicio nunca é nil
+type GastoFixoRegistrado struct {
+	ID int
+	DadosGastoFixo
+}
+
+// GastosFixos acessa os gastos fixos do usuário
+type GastosFixos interface {
+	// Criar e Atualizar retornam ErrIntervaloInvalido se o fim ficar antes do início
+	Criar(ctx context.Context, usuarioID int, d DadosGastoFixo) (int, error)
+	Atualizar(ctx context.Context, usuarioID, id int, d DadosGastoFixo) error
+	Remover(ctx context.Context, usuarioID, id int) error
+	// Listar retorna uma página dos gastos fixos, ordenada por OrdenacoesGastosFixos; De e Ate
+	// filtram pela data de criação e Mes pelos cobrados no mês
+	Listar(ctx context.Context, usuarioID int, l Listagem) ([]Listado[models.GastoFixo], error)
+	// NoPeriodo retorna os gastos fixos que começam antes do fim e terminam no mês do início ou depois
+	NoPeriodo(ctx context.Context, usuarioID int, inicio, fim time.Time) ([]GastoFixoRegistrado, error)
+}
+
+// DadosGastoVariavel são os campos gravados na criação e edição de um gasto variável
+type DadosGastoVariavel struct {
+	Nome             string
+	Valor            models.Dinheiro
+	Data             time.Time
+	CategoriaID      *int
+	CartaoID         *int
+	VencimentoFatura *time.Time
+	ContaID          *int
+}
+
+// GastosVariaveis acessa os gastos variáveis do usuário
+type GastosVariaveis interface {
+	Criar(ctx context.Context, usuarioID int, d DadosGastoVariavel) (int, error)
+	// Atualizar e Remover retornam *ErroParcelaDeCompra se o gasto for parcela de uma compra parcelada
+	Atualizar(ctx context.Context, usuarioID, id int, d DadosGastoVariavel) error
+	Remover(ctx context.Context, usuarioID, id int) error
+	// Listar retorna uma página dos gastos variáveis, ordenada por OrdenacoesGastosVariaveis;
+	// De e Ate filtram pela data do gasto e Mes pelo mês em que ele pesa, a regra de TotaisPorMes
+	Listar(ctx context.Context, usuarioID int, l Listagem) ([]Listado[models.GastoVariavel], error)
+	// TotaisPorMes soma os gastos variáveis em [inicio, fim) por categoria e mês. Compras no
+	// cartão contam pelo vencimento da fatura, e não pela data da compra.
+	TotaisPorMes(ctx context.Context, usuarioID int, inicio, fim time.Time) ([]TotalGastosVariaveis, error)
+}
+
+// TotalGastosVariaveis é a soma dos gastos variáveis de uma categoria em um mês
+type TotalGastosVariaveis struct {
+	CategoriaID int       // 0 para os gastos sem categoria
+	Mes         time.Time // primeiro dia do mês
+	Total       models.Dinheiro
+}
+
+// Categorias acessa as categorias usadas nos gastos
+type Categorias interface {
+	// Disponivel indica se a categoria é padrão ou do usuário
+	Disponivel(ctx context.Context, usuarioID, id int) (bool, error)
+	// Buscar retorna as categorias com os IDs informados; IDs inexistentes são ignorados
+	Buscar(ctx context.Context, ids []int) ([]models.Categoria, error)
+}
+
+// DadosConta são os campos gravados na criação e edição de uma conta
+type DadosConta struct {
+	Nome         string
+	Tipo         string
+	SaldoInicial models.Dinheiro
+}
+
+// ContaComMovimentos é a conta com as somas, até hoje, dos gastos variáveis pagos por ela
+// (compras no cartão pelo vencimento da fatura) e das transferências recebidas e enviadas
+type ContaComMovimentos struct {
+	models.Conta
+	GastosVariaveis         models.Dinheiro
+	TransferenciasRecebidas models.Dinheiro
+	TransferenciasEnviadas  models.Dinheiro
+}
+
+// Contas acessa as contas do usuário
+type Contas interface {
+	// Criar e Atualizar retornam ErrDuplicado se o usuário já tiver uma conta com o nome
+	Criar(ctx context.Context, usuarioID int, d DadosConta) (int, error)
+	Atualizar(ctx context.Context, usuarioID, id int, d DadosConta) error
+	// Remover retorna ErrEmUso se a conta tiver transferências
+	Remover(ctx context.Context, usuarioID, id int) error
+	// Pertence indica se a conta existe e é do usuário
+	Pertence(ctx context.Context, usuarioID, id int) (bool, error)
+	// ComMovimentos retorna as contas do usuário, ordenadas pelo nome
+	ComMovimentos(ctx context.Context, usuarioID int) ([]ContaComMovimentos, error)
+	// InicioRecorrentes retorna a data do primeiro gasto fixo ou renda com conta; hoje se não houver
+	InicioRecorrentes(ctx context.Context, usuarioID int) (time.Time, error)
+}
+
+// DadosTransferencia são os campos gravados na criação de uma transferência
+type DadosTransferencia struct {
+	ContaOrigemID  int
+	ContaDestinoID int
+	Valor          models.Dinheiro
+	Data           time.Time
+	Descricao      string
+}
+
+// Transferencias acessa as transferências entre contas do usuário
+type Transferencias interface {
+	Criar(ctx context.Context, usuarioID int, d DadosTransferencia) (int, error)
+	Remover(ctx context.Context, usuarioID, id int) error
+	// Listar retorna uma página das transferências, ordenada por OrdenacoesTransferencias; De e Ate
+	// filtram pela data da transferência e categoria, conta e mês não se aplicam
+	Listar(ctx context.Context, usuarioID int, l Listagem) ([]Listado[models.Transferencia], error)
+}
+
+// Cartoes acessa os cartões de crédito usados nos gastos variáveis
+type Cartoes interface {
+	// Obter retorna o cartão do usuário; ErrNaoEncontrado se não existir ou for de outro usuário
+	Obter(ctx context.Context, usuarioID, id int) (models.Cartao, error)
+}
+
+// Metas acessa os aportes nas metas de economia
+type Metas interface {
+	// AportesDescontados soma os aportes em [inicio, fim) nas metas que descontam do saldo disponível
+	AportesDescontados(ctx context.Context, usuarioID int, inicio, fim time.Time) (models.Dinheiro, error)
+}
+
+// Repositorios agrupa os repositórios usados pelos handlers
+type Repositorios struct {
+	Usuarios        Usuarios
+	Rendas          Rendas
+	GastosFixos     GastosFixos
+	GastosVariaveis GastosVariaveis
+	Categorias      Categorias
+	Contas          Contas
+	Transferencias  Transferencias
+	Cartoes         Cartoes
+	Metas           Metas
+}